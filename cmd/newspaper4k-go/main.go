@@ -13,17 +13,20 @@ func main() {
 
 	// Example 1: Create a source
 	fmt.Println("1. Creating source")
-	src, err := source.NewDefaultSource(source.SourceRequest{URL: "https://www.lemonde.fr/", Config: *config})
+	impl, err := source.NewDefaultSource(source.SourceRequest{URL: "https://www.lemonde.fr/", Config: *config})
 	if err != nil {
 		fmt.Printf("Error creating source: %v\n", err)
 		return
 	}
 
+	// Program against the Source interface so that the implementation can be swapped
+	var src source.Source = impl
+
 	fmt.Printf("   Source created successfully!")
-	fmt.Printf("   Domain: %s\n", src.ParsedURL.Domain)
-	fmt.Printf("   Scheme: %s\n", src.ParsedURL.Scheme)
-	fmt.Printf("   Subdomain: %s\n", src.ParsedURL.Subdomain)
-	fmt.Printf("   TLD: %s\n", src.ParsedURL.TLD)
+	fmt.Printf("   Domain: %s\n", src.ParsedURL().Domain)
+	fmt.Printf("   Scheme: %s\n", src.ParsedURL().Scheme)
+	fmt.Printf("   Subdomain: %s\n", src.ParsedURL().Subdomain)
+	fmt.Printf("   TLD: %s\n", src.ParsedURL().TLD)
 
 	// Build the source
 	fmt.Println("2. Building source...")
@@ -32,25 +35,25 @@ func main() {
 		return
 	}
 
-	fmt.Printf("   Download status: %t\n", src.IsDownloaded)
-	fmt.Printf("   Parse status: %t\n", src.IsParsed)
-	fmt.Printf("   Categories found: %d\n", len(src.Categories))
-	fmt.Printf("   Feeds found: %d\n", len(src.Feeds))
-	fmt.Printf("   Description: %s\n", src.Description)
+	fmt.Printf("   Download status: %t\n", impl.IsDownloaded)
+	fmt.Printf("   Parse status: %t\n", impl.IsParsed)
+	fmt.Printf("   Categories found: %d\n", len(src.Categories()))
+	fmt.Printf("   Feeds found: %d\n", len(src.Feeds()))
+	fmt.Printf("   Description: %s\n", src.Description())
 	fmt.Printf("   Articles generated: %d\n", src.Size())
 
 	// Show some categories
-	if len(src.Categories) > 0 {
+	if len(src.Categories()) > 0 {
 		fmt.Println("3. Sample categories:")
-		for i, cat := range src.Categories {
+		for i, cat := range src.Categories() {
 			fmt.Printf("   %d. %s\n", i+1, cat.URL)
 		}
 	}
 
 	// Show some feeds
-	if len(src.Feeds) > 0 {
+	if len(src.Feeds()) > 0 {
 		fmt.Println("3. Sample feeds:")
-		for i, feed := range src.Feeds {
+		for i, feed := range src.Feeds() {
 			fmt.Printf("   %d. %s\n", i+1, feed.URL)
 		}
 	}
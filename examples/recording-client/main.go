@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper4k"
+	"github.com/tguidoux/newspaper4k-go/pkg/source"
+)
+
+// recordingClient sends the requests with an http.Client and records them
+type recordingClient struct {
+	client *http.Client
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func (c *recordingClient) Do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.client.Do(req)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.requests = append(c.requests, fmt.Sprintf("%s -> error: %v", req.URL, err))
+		return nil, err
+	}
+	c.requests = append(c.requests, fmt.Sprintf("%s -> %d in %v", req.URL, resp.StatusCode, time.Since(start).Round(time.Millisecond)))
+	return resp, nil
+}
+
+func main() {
+	recorder := &recordingClient{client: &http.Client{Timeout: 30 * time.Second}}
+
+	config := configuration.NewConfiguration()
+	config.HTTPClient = recorder
+
+	// Example 1: Build a source, every download goes through the recorder
+	fmt.Println("1. Building source...")
+	src, err := source.NewDefaultSource(source.SourceRequest{URL: "https://www.lemonde.fr/", Config: *config})
+	if err != nil {
+		fmt.Printf("Error creating source: %v\n", err)
+		return
+	}
+	if err := src.Build(); err != nil {
+		fmt.Printf("Error building source: %v\n", err)
+		return
+	}
+	fmt.Printf("   Articles generated: %d\n", src.Size())
+
+	// Example 2: Download an article with the same client
+	fmt.Println("2. Building article...")
+	articles := src.GetArticles()
+	if len(articles) > 0 {
+		article, err := newspaper4k.NewArticleFromURL(articles[0].URL)
+		if err != nil {
+			fmt.Printf("Error creating article: %v\n", err)
+			return
+		}
+		article.Config.HTTPClient = recorder
+		if err := article.Build(newspaper4k.DefaultExtractors(article.Config)); err != nil {
+			fmt.Printf("Error building article: %v\n", err)
+			return
+		}
+		fmt.Printf("   Title: %s\n", article.Title)
+	}
+
+	fmt.Println("3. Recorded requests:")
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	for i, request := range recorder.requests {
+		fmt.Printf("   %d. %s\n", i+1, request)
+	}
+}
@@ -8,6 +8,10 @@ import (
 	"github.com/tguidoux/newspaper4k-go/internal/parsers"
 )
 
+// imageSelector matches the images kept with their figures and captions,
+// AMP pages using <amp-img>
+const imageSelector = "img, amp-img"
+
 // Cleaner interface defines methods for cleaning HTML documents
 type Cleaner interface {
 	Clean(doc *goquery.Selection) *goquery.Selection
@@ -27,10 +31,42 @@ type DocumentCleaner struct {
 	twitterRe              *regexp.Regexp
 	consentRe              *regexp.Regexp
 	containsArticle        string
+	keepCaptions           bool
+	captionCreditRes       []*regexp.Regexp
+	stripEmptyBlocks       bool
+}
+
+// Options controls the optional behaviors of a DocumentCleaner
+type Options struct {
+	// KeepCaptions turns figcaption text into a <p data-role="caption"> paragraph
+	// placed after the figure images instead of removing it
+	KeepCaptions bool
+	// CaptionCreditPatterns are case-insensitive regular expressions matching
+	// captions that are only photo credits; these are removed even with KeepCaptions.
+	// Invalid patterns are ignored.
+	CaptionCreditPatterns []string
+	// StripEmptyBlocks removes the block elements left without text or media,
+	// such as <p></p> or whitespace-only paragraphs
+	StripEmptyBlocks bool
 }
 
+// emptyBlockSelector matches the block elements removed by StripEmptyBlocks
+const emptyBlockSelector = "p, div, section, article, blockquote, pre, ul, ol, li, h1, h2, h3, h4, h5, h6"
+
+// mediaSelector matches the elements that make a block worth keeping without text
+const mediaSelector = imageSelector + ", picture, video, audio, iframe, embed, object, svg, table, " +
+	"amp-video, amp-iframe, amp-youtube, amp-vimeo, amp-dailymotion"
+
+// maxCreditWords is the longest caption, in words, still considered a photo credit
+const maxCreditWords = 8
+
 // NewDocumentCleaner creates a new DocumentCleaner
 func NewDocumentCleaner() *DocumentCleaner {
+	return NewDocumentCleanerWithOptions(Options{})
+}
+
+// NewDocumentCleanerWithOptions creates a new DocumentCleaner with the given options
+func NewDocumentCleanerWithOptions(opts Options) *DocumentCleaner {
 	dc := &DocumentCleaner{
 		removeNodesRe: regexp.MustCompile(
 			"^side$|combx|retweet|mediaarticlerelated|menucontainer|" +
@@ -60,7 +96,15 @@ func NewDocumentCleaner() *DocumentCleaner {
 		consentRe: regexp.MustCompile(
 			`cookie|cookies|cookieconsent|cookie-consent|cookie_banner|cookie-banner|cookie_notice|cookie-notice|cookiepolicy|cookie_policy|cookiePolicy|consent|consent-banner|consent-popup|gdpr|eu-consent|ccpa|accept-cookies|cookieNotice`,
 		),
-		containsArticle: `.//article|.//*[@id="article"]|.//*[contains(@itemprop,"articleBody")]`,
+		containsArticle:  `.//article|.//*[@id="article"]|.//*[contains(@itemprop,"articleBody")]`,
+		keepCaptions:     opts.KeepCaptions,
+		stripEmptyBlocks: opts.StripEmptyBlocks,
+	}
+
+	for _, pattern := range opts.CaptionCreditPatterns {
+		if re, err := regexp.Compile("(?i)" + pattern); err == nil {
+			dc.captionCreditRes = append(dc.captionCreditRes, re)
+		}
 	}
 
 	return dc
@@ -98,6 +142,10 @@ func (dc *DocumentCleaner) Clean(node *goquery.Selection) *goquery.Selection {
 
 	node = dc.reduceArticle(node)
 
+	if dc.stripEmptyBlocks {
+		node = dc.removeEmptyBlocks(node)
+	}
+
 	return node
 }
 
@@ -134,7 +182,7 @@ func (dc *DocumentCleaner) cleanArticleTags(node *goquery.Selection) *goquery.Se
 // cleanEmTags removes <em> tags that don't contain any <img> tags
 func (dc *DocumentCleaner) cleanEmTags(node *goquery.Selection) *goquery.Selection {
 	node.Find("em").Each(func(i int, s *goquery.Selection) {
-		if s.Find("img").Length() == 0 {
+		if s.Find(imageSelector).Length() == 0 {
 			parsers.DropTags(s)
 		}
 	})
@@ -143,9 +191,25 @@ func (dc *DocumentCleaner) cleanEmTags(node *goquery.Selection) *goquery.Selecti
 
 // cleanCaptionTags removes image caption tags from the document
 func (dc *DocumentCleaner) cleanCaptionTags(node *goquery.Selection) *goquery.Selection {
+	if dc.keepCaptions {
+		// Replace figures and captions by their images followed by the caption text
+		node.Find("figure, figcaption").Each(func(i int, s *goquery.Selection) {
+			if s.Parent().Length() == 0 {
+				return
+			}
+			s.BeforeSelection(s.Find(imageSelector))
+			if caption := dc.captionText(s.Find("figcaption").AddSelection(s.Filter("figcaption"))); caption != "" {
+				p := parsers.CreateElement("p", caption, "")
+				p.SetAttr("data-role", "caption")
+				s.BeforeSelection(p)
+			}
+			s.Remove()
+		})
+	}
+
 	// Remove figure tags but keep img tags
 	node.Find("figure").Each(func(i int, s *goquery.Selection) {
-		imgs := s.Find("img")
+		imgs := s.Find(imageSelector)
 		if imgs.Length() > 0 && s.Parent().Length() > 0 {
 			s.Parent().AppendSelection(imgs)
 		}
@@ -154,7 +218,7 @@ func (dc *DocumentCleaner) cleanCaptionTags(node *goquery.Selection) *goquery.Se
 
 	// Remove figcaption tags but keep img tags
 	node.Find("figcaption").Each(func(i int, s *goquery.Selection) {
-		imgs := s.Find("img")
+		imgs := s.Find(imageSelector)
 		if imgs.Length() > 0 && s.Parent().Length() > 0 {
 			s.Parent().AppendSelection(imgs)
 		}
@@ -176,6 +240,23 @@ func (dc *DocumentCleaner) cleanCaptionTags(node *goquery.Selection) *goquery.Se
 	return node
 }
 
+// captionText returns the text of the captions, or "" when it is empty or
+// only a photo credit
+func (dc *DocumentCleaner) captionText(captions *goquery.Selection) string {
+	text := parsers.InnerTrim(captions.Text())
+	if text == "" {
+		return ""
+	}
+	if len(strings.Fields(text)) <= maxCreditWords {
+		for _, re := range dc.captionCreditRes {
+			if re.MatchString(text) {
+				return ""
+			}
+		}
+	}
+	return text
+}
+
 // removeDropCaps removes spans with class dropcap or drop_cap
 func (dc *DocumentCleaner) removeDropCaps(node *goquery.Selection) *goquery.Selection {
 	node.Find("span[class~='dropcap'], span[class~='drop_cap']").Remove()
@@ -250,6 +331,19 @@ func (dc *DocumentCleaner) cleanParaSpans(node *goquery.Selection) *goquery.Sele
 	return node
 }
 
+// removeEmptyBlocks removes the block elements without text or media under
+// node, innermost first so that blocks holding only empty blocks go as well
+func (dc *DocumentCleaner) removeEmptyBlocks(node *goquery.Selection) *goquery.Selection {
+	blocks := node.Find(emptyBlockSelector)
+	for i := blocks.Length() - 1; i >= 0; i-- {
+		block := blocks.Eq(i)
+		if strings.TrimSpace(block.Text()) == "" && block.Find(mediaSelector).Length() == 0 {
+			block.Remove()
+		}
+	}
+	return node
+}
+
 // reduceArticle reduces the article by removing unnecessary tags
 func (dc *DocumentCleaner) reduceArticle(node *goquery.Selection) *goquery.Selection {
 	body := node.Find("body")
@@ -257,7 +351,8 @@ func (dc *DocumentCleaner) reduceArticle(node *goquery.Selection) *goquery.Selec
 		return node
 	}
 
-	keepTags := []string{"p", "br", "img", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "body", "article", "section"}
+	keepTags := []string{"p", "br", "img", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "body", "article", "section",
+		"amp-img", "amp-video", "amp-iframe", "amp-youtube", "amp-vimeo", "amp-dailymotion"}
 
 	body.Find("*").Each(func(i int, s *goquery.Selection) {
 		tagName := s.Get(0).Data
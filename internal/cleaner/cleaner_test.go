@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/pkg/constants"
 )
 
 func TestNewDocumentCleaner(t *testing.T) {
@@ -145,3 +146,101 @@ func TestCleanParaSpans(t *testing.T) {
 		t.Error("Span inside p not removed")
 	}
 }
+
+func TestCleanCaptionTags(t *testing.T) {
+	html := `<html><body><article>
+<p>The mayor opened the new bridge on Monday.</p>
+<figure><img src="bridge.jpg"><figcaption>Mayor Anne Dupont cuts the ribbon on the Lindqvist bridge in Malmö</figcaption></figure>
+<figure><img src="crowd.jpg"><figcaption>Photo: Reuters</figcaption></figure>
+<figure><img src="river.jpg"><figcaption>Getty Images</figcaption></figure>
+<figcaption>   </figcaption>
+</article></body></html>`
+
+	dc := NewDocumentCleanerWithOptions(Options{
+		KeepCaptions:          true,
+		CaptionCreditPatterns: []string{`^photo\b`, `\bgetty images\b`},
+	})
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := dc.Clean(doc.Selection)
+
+	captions := result.Find("p[data-role='caption']")
+	if captions.Length() != 1 {
+		t.Fatalf("Expected 1 caption paragraph, got %d", captions.Length())
+	}
+	if !strings.Contains(captions.Text(), "Lindqvist bridge") {
+		t.Errorf("Expected the substantive caption to survive, got %q", captions.Text())
+	}
+	if prev := captions.Prev(); !prev.Is("img") || prev.AttrOr("src", "") != "bridge.jpg" {
+		t.Error("Expected the caption paragraph to follow its image")
+	}
+	if text := result.Text(); strings.Contains(text, "Reuters") || strings.Contains(text, "Getty") {
+		t.Errorf("Expected credit-only captions to be removed, got %q", text)
+	}
+	if result.Find("figure, figcaption").Length() != 0 || result.Find("img").Length() != 3 {
+		t.Error("Expected figures to be replaced by their images")
+	}
+
+	// Without KeepCaptions every caption is removed
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+	result = NewDocumentCleaner().Clean(doc.Selection)
+	if strings.Contains(result.Text(), "Lindqvist") {
+		t.Error("Expected captions to be removed by default")
+	}
+}
+
+func TestCaptionCreditPatterns(t *testing.T) {
+	dc := NewDocumentCleanerWithOptions(Options{KeepCaptions: true, CaptionCreditPatterns: constants.CAPTION_CREDIT_PATTERNS})
+	tests := []struct {
+		caption string
+		credit  bool
+	}{
+		{"Photo: Reuters", true},
+		{"Photograph by Jane Doe", true},
+		{"Image credit: NASA", true},
+		{"Source: company handout", true},
+		{"© 2025 The City Archives", true},
+		{"Getty Images", true},
+		{"John Smith/Reuters", true},
+		{"Jane Doe / AFP via Getty Images", true},
+		{"(AP Photo/Evan Vucci)", true},
+		{"Sources say the AP will appeal", false},
+		{"Reuters building in London", false},
+		{"The AP style guide on the desk", false},
+		{"Photographers gather outside the court", false},
+		{"Images of the flooded harbour", false},
+	}
+	for _, tt := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader("<figcaption>" + tt.caption + "</figcaption>"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if credit := dc.captionText(doc.Find("figcaption")) == ""; credit != tt.credit {
+			t.Errorf("Caption %q: expected credit %v, got %v", tt.caption, tt.credit, credit)
+		}
+	}
+}
+
+func TestCleanKeepsAMPMedia(t *testing.T) {
+	html := `<html><body><article>
+<p>The harbour reopened on Monday after the storm.</p>
+<figure><amp-img src="harbour.jpg" width="1200" height="800"></amp-img><figcaption>The harbour</figcaption></figure>
+<amp-video src="ferry.mp4" width="640" height="360"></amp-video>
+<amp-youtube data-videoid="dQw4w9WgXcQ" width="480" height="270"></amp-youtube>
+</article></body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := NewDocumentCleaner().Clean(doc.Selection)
+
+	if result.Find("figure").Length() != 0 || result.Find("amp-img[src='harbour.jpg']").Length() != 1 {
+		t.Error("Expected the figure to be replaced by its amp-img")
+	}
+	if result.Find("amp-video, amp-youtube").Length() != 2 {
+		t.Error("Expected the AMP players to be kept")
+	}
+}
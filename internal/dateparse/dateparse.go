@@ -0,0 +1,193 @@
+// Package dateparse parses the dates found in articles and feeds: ISO and RFC
+// timestamps, numeric dates, dates with month names in the main languages,
+// Unix timestamps and times followed by a zone abbreviation such as "ET".
+package dateparse
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	anydate "github.com/araddon/dateparse"
+)
+
+// DefaultLayouts are the layouts tried by Parse, in order, once the value is
+// normalized: month names translated to English, weekdays, ordinals and
+// connecting words removed, "a.m." turned into "AM" and zone abbreviations
+// stripped. Ambiguous numeric dates are read month first, as in the US, and
+// day first when that fails.
+var DefaultLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2 January 2006 15:04:05.999999999 -0700",
+	"2 January 2006 15:04:05.999999999",
+	"2 January 2006 15:04",
+	"2 January 2006 3:04 PM",
+	"2 January 2006, 15:04",
+	"2 January 2006",
+	"2. January 2006 15:04",
+	"2. January 2006",
+	"02-January-06 15:04:05",
+	"January 2, 2006 3:04:05 PM",
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006, 3:04 PM",
+	"January 2, 2006 15:04:05",
+	"January 2, 2006 15:04",
+	"January 2, 2006",
+	"January 2 2006",
+	"January 2 15:04:05 2006",
+	"January 2006",
+	"1/2/2006 3:04:05 PM",
+	"1/2/2006 3:04 PM",
+	"1/2/2006 15:04:05",
+	"1/2/2006 15:04",
+	"1/2/2006",
+	"2/1/2006 15:04:05",
+	"2/1/2006 15:04",
+	"2/1/2006",
+	"2.1.2006 15:04:05",
+	"2.1.2006 15:04",
+	"2.1.2006",
+	"2-1-2006 15:04",
+	"2-1-2006",
+	"2006/1/2 15:04:05",
+	"2006/1/2 15:04",
+	"2006/1/2",
+	"2006年1月2日 15:04",
+	"2006年1月2日",
+	"20060102",
+}
+
+// Parser parses dates with an ordered registry of layouts
+type Parser struct {
+	layouts []string
+}
+
+// defaultParser is the Parser used by Parse
+var defaultParser = New()
+
+// New returns a Parser trying extraLayouts before DefaultLayouts
+func New(extraLayouts ...string) *Parser {
+	layouts := make([]string, 0, len(extraLayouts)+len(DefaultLayouts))
+	layouts = append(layouts, extraLayouts...)
+	layouts = append(layouts, DefaultLayouts...)
+	return &Parser{layouts: layouts}
+}
+
+// Parse parses value with the default layouts
+func Parse(value string) (time.Time, error) {
+	return defaultParser.Parse(value)
+}
+
+// Parse parses value, trying Unix timestamps first, then the layouts of the
+// registry on the normalized value and finally a format detection. Dates
+// without zone are UTC.
+func (p *Parser) Parse(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, errors.New("empty date")
+	}
+	if t, ok := parseUnix(value); ok {
+		return t, nil
+	}
+
+	normalized, zone := normalize(value)
+	for _, layout := range p.layouts {
+		if t, err := time.Parse(layout, normalized); err == nil {
+			return zone.apply(t), nil
+		}
+	}
+	if t, err := anydate.ParseAny(normalized); err == nil {
+		return zone.apply(t), nil
+	}
+	t, err := anydate.ParseAny(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+	}
+	return t, nil
+}
+
+var (
+	// unixRe matches Unix timestamps in seconds or milliseconds, with optional decimals
+	unixRe = regexp.MustCompile(`^(\d{9,10}|\d{12,13})(\.\d+)?$`)
+	// commaFractionRe matches the ISO 8601 comma before fractional seconds
+	commaFractionRe = regexp.MustCompile(`(\d{2}:\d{2}:\d{2}),(\d+)`)
+	// meridiemRe matches a.m., p.m., am and pm
+	meridiemRe = regexp.MustCompile(`(?i)(\d)\s*([ap])\.?\s?m\b\.?`)
+	// hourMarkRe matches the French and Portuguese "09h15" times
+	hourMarkRe = regexp.MustCompile(`\b(\d{1,2})h(\d{2})\b`)
+	// ordinalRe matches day ordinals such as 27th or 1er
+	ordinalRe = regexp.MustCompile(`(?i)\b(\d{1,2})(st|nd|rd|th|er)\b`)
+	// wordRe matches the words of the value along with a trailing period
+	wordRe = regexp.MustCompile(`\p{L}+\.?`)
+	// zoneSuffixRe matches a trailing zone abbreviation, possibly in parentheses
+	zoneSuffixRe = regexp.MustCompile(`\s+\(?([A-Z]{2,5})\)?$`)
+)
+
+// parseUnix parses Unix timestamps in seconds or milliseconds between 1970 and 2100
+func parseUnix(value string) (time.Time, bool) {
+	if !unixRe.MatchString(value) {
+		return time.Time{}, false
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if len(strings.Split(value, ".")[0]) >= 12 {
+		n /= 1000
+	}
+	sec, frac := math.Modf(n)
+	t := time.Unix(int64(sec), int64(math.Round(frac*1e3))*int64(time.Millisecond)).UTC()
+	if t.Year() > 2100 {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// normalize rewrites value for the layouts and returns the zone abbreviation
+// it ended with, if known
+func normalize(value string) (string, *zone) {
+	value = commaFractionRe.ReplaceAllString(value, "$1.$2")
+	value = hourMarkRe.ReplaceAllString(value, "$1:$2")
+	value = ordinalRe.ReplaceAllString(value, "$1")
+	value = meridiemRe.ReplaceAllStringFunc(value, func(m string) string {
+		sub := meridiemRe.FindStringSubmatch(m)
+		return sub[1] + " " + strings.ToUpper(sub[2]) + "M"
+	})
+
+	value = wordRe.ReplaceAllStringFunc(value, func(word string) string {
+		lower := strings.ToLower(strings.TrimSuffix(word, "."))
+		if month, ok := monthNames[lower]; ok {
+			return month
+		}
+		if weekdayNames[lower] || connectingWords[lower] {
+			return ""
+		}
+		return word
+	})
+	value = strings.Join(strings.Fields(value), " ")
+	value = strings.TrimLeft(value, ",- ")
+	value = strings.ReplaceAll(value, " ,", ",")
+
+	var z *zone
+	if m := zoneSuffixRe.FindStringSubmatch(value); m != nil {
+		if known, ok := zones[m[1]]; ok {
+			z = &known
+			value = strings.TrimSpace(value[:len(value)-len(m[0])])
+		}
+	}
+	return strings.TrimRight(value, ", "), z
+}
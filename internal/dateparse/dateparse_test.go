@@ -0,0 +1,98 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	utc := func(y int, m time.Month, d, h, min, s int) time.Time {
+		return time.Date(y, m, d, h, min, s, 0, time.UTC)
+	}
+	zoned := func(offset time.Duration, y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, time.FixedZone("", int(offset.Seconds())))
+	}
+
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		// ISO 8601 and RFC formats
+		{"2025-08-27T09:15:30Z", utc(2025, 8, 27, 9, 15, 30)},
+		{"2025-08-27T09:15:30+02:00", zoned(2*time.Hour, 2025, 8, 27, 9, 15).Add(30 * time.Second)},
+		{"2025-08-27T09:15:30.123Z", utc(2025, 8, 27, 9, 15, 30).Add(123 * time.Millisecond)},
+		{"2025-08-27T09:15:30,123Z", utc(2025, 8, 27, 9, 15, 30).Add(123 * time.Millisecond)},
+		{"2025-08-27T09:15:30,5+01:00", zoned(time.Hour, 2025, 8, 27, 9, 15).Add(30*time.Second + 500*time.Millisecond)},
+		{"2025-08-27T09:15:30+0200", zoned(2*time.Hour, 2025, 8, 27, 9, 15).Add(30 * time.Second)},
+		{"2025-08-27T09:15", utc(2025, 8, 27, 9, 15, 0)},
+		{"2025-08-27 09:15:30", utc(2025, 8, 27, 9, 15, 30)},
+		{"2025-08-27", utc(2025, 8, 27, 0, 0, 0)},
+		{"Wed, 27 Aug 2025 09:15:30 +0000", utc(2025, 8, 27, 9, 15, 30)},
+		{"Wed, 27 Aug 2025 09:15:30 GMT", utc(2025, 8, 27, 9, 15, 30)},
+		{"Wednesday, 27-Aug-25 09:15:30 UTC", utc(2025, 8, 27, 9, 15, 30)},
+		{"20250827", utc(2025, 8, 27, 0, 0, 0)},
+		// Numeric dates
+		{"27/08/2025 09:15", utc(2025, 8, 27, 9, 15, 0)},
+		{"08/27/2025 9:15 PM", utc(2025, 8, 27, 21, 15, 0)},
+		{"05/08/2025", utc(2025, 5, 8, 0, 0, 0)},
+		{"27.08.2025 um 09:15 Uhr", utc(2025, 8, 27, 9, 15, 0)},
+		{"27.08.2025", utc(2025, 8, 27, 0, 0, 0)},
+		{"2025/08/27", utc(2025, 8, 27, 0, 0, 0)},
+		{"2025年8月27日", utc(2025, 8, 27, 0, 0, 0)},
+		// English month names
+		{"Aug. 27, 2025 9:15 a.m. ET", zoned(-4*time.Hour, 2025, 8, 27, 9, 15)},
+		{"Jan. 15, 2025 9:15 p.m. ET", zoned(-5*time.Hour, 2025, 1, 15, 21, 15)},
+		{"August 27, 2025 at 9:15 AM PDT", zoned(-7*time.Hour, 2025, 8, 27, 9, 15)},
+		{"Wednesday, August 27th, 2025", utc(2025, 8, 27, 0, 0, 0)},
+		{"27 August 2025 09:15 CET", zoned(time.Hour, 2025, 8, 27, 9, 15)},
+		{"27 Aug 2025 09:15 (CEST)", zoned(2*time.Hour, 2025, 8, 27, 9, 15)},
+		{"Sept. 3, 2025", utc(2025, 9, 3, 0, 0, 0)},
+		{"August 2025", utc(2025, 8, 1, 0, 0, 0)},
+		// Other languages
+		{"27 de agosto de 2025 09:15", utc(2025, 8, 27, 9, 15, 0)},
+		{"mercredi 27 août 2025 à 09h15", utc(2025, 8, 27, 9, 15, 0)},
+		{"1er septembre 2025", utc(2025, 9, 1, 0, 0, 0)},
+		{"Mittwoch, 27. August 2025", utc(2025, 8, 27, 0, 0, 0)},
+		{"27. März 2025 09:15", utc(2025, 3, 27, 9, 15, 0)},
+		{"27 agosto 2025 alle ore 09:15", utc(2025, 8, 27, 9, 15, 0)},
+		{"27 augustus 2025 om 09:15", utc(2025, 8, 27, 9, 15, 0)},
+		{"27 августа 2025 г. в 09:15", utc(2025, 8, 27, 9, 15, 0)},
+		{"27 sierpnia 2025 r.", utc(2025, 8, 27, 0, 0, 0)},
+		{"27 Ağustos 2025 Çarşamba 09:15", utc(2025, 8, 27, 9, 15, 0)},
+		{"quarta-feira, 27 de agosto de 2025", utc(2025, 8, 27, 0, 0, 0)},
+		// Unix timestamps
+		{"1756286130", utc(2025, 8, 27, 9, 15, 30)},
+		{"1756286130123", utc(2025, 8, 27, 9, 15, 30).Add(123 * time.Millisecond)},
+		{"1756286130.5", utc(2025, 8, 27, 9, 15, 30).Add(500 * time.Millisecond)},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.value)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("Parse(%q) = %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, value := range []string{"", "   ", "yesterday", "not a date", "99/99/2025"} {
+		if got, err := Parse(value); err == nil {
+			t.Errorf("Parse(%q) = %v, expected an error", value, got)
+		}
+	}
+}
+
+func TestExtraLayouts(t *testing.T) {
+	const value = "27|08|2025"
+	if _, err := Parse(value); err == nil {
+		t.Fatalf("Expected %q to need an extra layout", value)
+	}
+	got, err := New("02|01|2006").Parse(value)
+	if err != nil || !got.Equal(time.Date(2025, 8, 27, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the extra layout to parse %q, got %v, %v", value, got, err)
+	}
+}
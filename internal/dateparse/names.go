@@ -0,0 +1,143 @@
+package dateparse
+
+import "time"
+
+// monthNames maps the lowercased month names and abbreviations of English,
+// Spanish, French, German, Portuguese, Italian, Dutch, Russian, Polish and
+// Turkish to the English names understood by the layouts
+var monthNames = map[string]string{}
+
+func init() {
+	names := [12][]string{
+		{"january", "jan", "enero", "ene", "janvier", "janv", "januar", "jänner", "janeiro", "gennaio", "gen", "januari", "январь", "января", "янв", "styczeń", "stycznia", "sty", "ocak", "oca"},
+		{"february", "feb", "febrero", "février", "févr", "fevr", "februar", "fevereiro", "fev", "febbraio", "februari", "февраль", "февраля", "фев", "luty", "lutego", "lut", "şubat", "şub"},
+		{"march", "mar", "marzo", "mars", "märz", "mär", "mrz", "março", "marco", "maart", "mrt", "март", "марта", "marzec", "marca", "mart"},
+		{"april", "apr", "abril", "abr", "avril", "avr", "aprile", "апрель", "апреля", "апр", "kwiecień", "kwietnia", "kwi", "nisan", "nis"},
+		{"may", "mayo", "mai", "maio", "maggio", "mag", "mei", "май", "мая", "maj", "maja", "mayıs"},
+		{"june", "jun", "junio", "juin", "juni", "junho", "giugno", "giu", "июнь", "июня", "czerwiec", "czerwca", "cze", "haziran", "haz"},
+		{"july", "jul", "julio", "juillet", "juil", "juli", "julho", "luglio", "lug", "июль", "июля", "lipiec", "lipca", "lip", "temmuz", "tem"},
+		{"august", "aug", "agosto", "ago", "août", "aout", "augustus", "август", "августа", "авг", "sierpień", "sierpnia", "sie", "ağustos", "ağu"},
+		{"september", "sep", "sept", "septiembre", "septembre", "setembro", "set", "settembre", "сентябрь", "сентября", "сен", "wrzesień", "września", "wrz", "eylül", "eyl"},
+		{"october", "oct", "octubre", "octobre", "oktober", "okt", "outubro", "out", "ottobre", "ott", "октябрь", "октября", "окт", "październik", "października", "paź", "ekim", "eki"},
+		{"november", "nov", "noviembre", "novembre", "novembro", "ноябрь", "ноября", "ноя", "listopad", "listopada", "lis", "kasım", "kas"},
+		{"december", "dec", "diciembre", "dic", "décembre", "déc", "dezember", "dez", "dezembro", "dicembre", "декабрь", "декабря", "дек", "grudzień", "grudnia", "gru", "aralık", "ara"},
+	}
+	for i, forms := range names {
+		for _, name := range forms {
+			monthNames[name] = time.Month(i + 1).String()
+		}
+	}
+}
+
+// weekdayNames are the lowercased weekday names and abbreviations removed
+// before parsing, in the languages of monthNames
+var weekdayNames = map[string]bool{}
+
+func init() {
+	for _, name := range []string{
+		"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+		"mon", "tue", "tues", "wed", "thu", "thur", "thurs", "fri", "sat", "sun",
+		"lunes", "martes", "miércoles", "jueves", "viernes", "sábado", "domingo",
+		"lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi", "dimanche",
+		"montag", "dienstag", "mittwoch", "donnerstag", "freitag", "samstag", "sonntag",
+		"segunda", "terça", "quarta", "quinta", "sexta", "feira",
+		"lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato", "domenica",
+		"maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag", "zondag",
+		"понедельник", "вторник", "среда", "четверг", "пятница", "суббота", "воскресенье",
+		"poniedziałek", "wtorek", "środa", "czwartek", "piątek", "sobota", "niedziela",
+		"pazartesi", "salı", "çarşamba", "perşembe", "cuma", "cumartesi", "pazar",
+	} {
+		weekdayNames[name] = true
+	}
+}
+
+// connectingWords are the lowercased words between the parts of a date, such
+// as "de" in "27 de agosto de 2025" or "um" and "Uhr" around German times.
+// The German "am" is kept since it is the meridiem once normalized.
+var connectingWords = map[string]bool{
+	"at": true, "on": true, "of": true,
+	"de": true, "del": true, "a": true, "las": true, "los": true,
+	"le": true, "à": true, "às": true,
+	"um": true, "uhr": true,
+	"alle": true, "ore": true, "il": true,
+	"om": true, "uur": true,
+	"в": true, "г": true, "года": true,
+	"r": true, "o": true, "godz": true,
+	"saat": true,
+}
+
+// zone is the UTC offset of a zone abbreviation. US zones follow the US
+// daylight saving time rules.
+type zone struct {
+	name   string
+	offset time.Duration
+	usDST  bool
+}
+
+// zones maps the zone abbreviations found after dates to their offsets
+var zones = map[string]zone{
+	"UTC":  {"UTC", 0, false},
+	"GMT":  {"GMT", 0, false},
+	"ET":   {"ET", -5 * time.Hour, true},
+	"EST":  {"EST", -5 * time.Hour, false},
+	"EDT":  {"EDT", -4 * time.Hour, false},
+	"CT":   {"CT", -6 * time.Hour, true},
+	"CST":  {"CST", -6 * time.Hour, false},
+	"CDT":  {"CDT", -5 * time.Hour, false},
+	"MT":   {"MT", -7 * time.Hour, true},
+	"MST":  {"MST", -7 * time.Hour, false},
+	"MDT":  {"MDT", -6 * time.Hour, false},
+	"PT":   {"PT", -8 * time.Hour, true},
+	"PST":  {"PST", -8 * time.Hour, false},
+	"PDT":  {"PDT", -7 * time.Hour, false},
+	"WET":  {"WET", 0, false},
+	"WEST": {"WEST", time.Hour, false},
+	"BST":  {"BST", time.Hour, false},
+	"CET":  {"CET", time.Hour, false},
+	"CEST": {"CEST", 2 * time.Hour, false},
+	"MEZ":  {"MEZ", time.Hour, false},
+	"MESZ": {"MESZ", 2 * time.Hour, false},
+	"EET":  {"EET", 2 * time.Hour, false},
+	"EEST": {"EEST", 3 * time.Hour, false},
+	"MSK":  {"MSK", 3 * time.Hour, false},
+	"IST":  {"IST", 5*time.Hour + 30*time.Minute, false},
+	"SGT":  {"SGT", 8 * time.Hour, false},
+	"HKT":  {"HKT", 8 * time.Hour, false},
+	"JST":  {"JST", 9 * time.Hour, false},
+	"KST":  {"KST", 9 * time.Hour, false},
+	"AEST": {"AEST", 10 * time.Hour, false},
+	"AEDT": {"AEDT", 11 * time.Hour, false},
+}
+
+// apply moves the wall clock time t, parsed without zone, to the zone. Times
+// already carrying an offset are returned unchanged.
+func (z *zone) apply(t time.Time) time.Time {
+	if z == nil {
+		return t
+	}
+	if name, offset := t.Zone(); offset != 0 || (name != "UTC" && name != "") {
+		return t
+	}
+	offset := z.offset
+	if z.usDST && inUSDaylightTime(t) {
+		offset += time.Hour
+	}
+	loc := time.FixedZone(z.name, int(offset.Seconds()))
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// inUSDaylightTime reports whether the wall clock time t is between the second
+// Sunday of March and the first Sunday of November, at 2am
+func inUSDaylightTime(t time.Time) bool {
+	start := nthSunday(t.Year(), time.March, 2).Add(2 * time.Hour)
+	end := nthSunday(t.Year(), time.November, 1).Add(2 * time.Hour)
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return !wall.Before(start) && wall.Before(end)
+}
+
+// nthSunday returns the nth Sunday of the month at midnight UTC
+func nthSunday(year int, month time.Month, n int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	days := (7 - int(first.Weekday())) % 7
+	return first.AddDate(0, 0, days+7*(n-1))
+}
@@ -0,0 +1,327 @@
+package helpers
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+)
+
+// FetchResult is the outcome of an HTTP GET shared between concurrent callers.
+// Body is shared as well and must not be modified.
+type FetchResult struct {
+	URL        string      // Final URL after redirects
+	Redirects  []string    // URLs redirected from, in order, empty when not redirected
+	StatusCode int         // HTTP status code
+	Header     http.Header // Response headers
+	Body       []byte      // Full response body
+}
+
+// FetchOptions tunes a single Fetcher.Get call
+type FetchOptions struct {
+	RecentCacheSize int           // Number of recently fetched bodies to keep, 0 disables the cache
+	RecentCacheTTL  time.Duration // How long a recently fetched body can be reused
+	Refresh         bool          // Skips the recently fetched bodies, the new result replacing them
+	UserAgent       string        // User-Agent header of the request, Go's default when empty
+	// Headers are set on the request before UserAgent
+	Headers map[string]string
+	// MaxRetries is the number of times a request failing with a network
+	// error, a 5xx or a 429 status is retried, 0 disables retries
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled for every
+	// following one. A Retry-After header of a 429 or 503 response takes
+	// precedence, up to maxRetryAfter.
+	RetryBackoff time.Duration
+	// Context cancels the request, or stops waiting for the request of a
+	// concurrent caller, context.Background() when nil
+	Context context.Context
+}
+
+// Fetcher coalesces concurrent GET requests for the same URL into a single
+// fetch and optionally remembers recently fetched bodies.
+type Fetcher struct {
+	mu       sync.Mutex
+	inFlight map[string]*fetchCall
+	recent   *list.List               // most recently used first
+	entries  map[string]*list.Element // key -> element of recent
+}
+
+type fetchCall struct {
+	ctx  context.Context // context of the caller making the request
+	done chan struct{}
+	res  *FetchResult
+	err  error
+}
+
+type recentEntry struct {
+	key       string
+	res       *FetchResult
+	fetchedAt time.Time
+}
+
+// DefaultFetcher is the process wide Fetcher shared by articles and sources
+var DefaultFetcher = NewFetcher()
+
+// NewFetcher creates an empty Fetcher
+func NewFetcher() *Fetcher {
+	return &Fetcher{
+		inFlight: map[string]*fetchCall{},
+		recent:   list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// Get downloads rawURL with client, http.DefaultClient when nil. Concurrent
+// calls for the same URL with the same client and request headers share one
+// request. Every caller gets its own copy of the result.
+func (f *Fetcher) Get(client configuration.HTTPClient, rawURL string, opts FetchOptions) (*FetchResult, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	key := requestKey(client, rawURL, opts)
+
+	f.mu.Lock()
+	if res, ok := f.lookupRecent(key, opts); ok && !opts.Refresh {
+		f.mu.Unlock()
+		return res.clone(), nil
+	}
+	if call, ok := f.inFlight[key]; ok {
+		f.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// The request was canceled by the caller that made it, not by this one
+		if call.err != nil && call.ctx.Err() != nil {
+			return f.Get(client, rawURL, opts)
+		}
+		return call.res.clone(), call.err
+	}
+	call := &fetchCall{ctx: ctx, done: make(chan struct{})}
+	f.inFlight[key] = call
+	f.mu.Unlock()
+
+	call.res, call.err = fetchWithRetries(ctx, client, rawURL, opts)
+
+	f.mu.Lock()
+	delete(f.inFlight, key)
+	// Error statuses are not worth replaying, a later call may succeed
+	if call.err == nil && call.res.StatusCode >= 200 && call.res.StatusCode < 300 {
+		f.storeRecent(key, call.res, opts)
+	}
+	f.mu.Unlock()
+	close(call.done)
+
+	return call.res.clone(), call.err
+}
+
+// clone returns a copy of r whose header and body can be modified, nil for nil
+func (r *FetchResult) clone() *FetchResult {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.Redirects = slices.Clone(r.Redirects)
+	c.Header = r.Header.Clone()
+	c.Body = bytes.Clone(r.Body)
+	return &c
+}
+
+// lookupRecent returns a cached result younger than the TTL. Callers must hold f.mu.
+func (f *Fetcher) lookupRecent(key string, opts FetchOptions) (*FetchResult, bool) {
+	if opts.RecentCacheSize <= 0 || opts.RecentCacheTTL <= 0 {
+		return nil, false
+	}
+	el, ok := f.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*recentEntry)
+	if time.Since(entry.fetchedAt) > opts.RecentCacheTTL {
+		f.recent.Remove(el)
+		delete(f.entries, key)
+		return nil, false
+	}
+	f.recent.MoveToFront(el)
+	return entry.res, true
+}
+
+// storeRecent remembers a result, evicting the least recently used ones. Callers must hold f.mu.
+func (f *Fetcher) storeRecent(key string, res *FetchResult, opts FetchOptions) {
+	if opts.RecentCacheSize <= 0 || opts.RecentCacheTTL <= 0 {
+		return
+	}
+	if el, ok := f.entries[key]; ok {
+		el.Value = &recentEntry{key: key, res: res, fetchedAt: time.Now()}
+		f.recent.MoveToFront(el)
+	} else {
+		f.entries[key] = f.recent.PushFront(&recentEntry{key: key, res: res, fetchedAt: time.Now()})
+	}
+	for f.recent.Len() > opts.RecentCacheSize {
+		oldest := f.recent.Back()
+		f.recent.Remove(oldest)
+		delete(f.entries, oldest.Value.(*recentEntry).key)
+	}
+}
+
+// maxRetryAfter bounds the wait requested by a Retry-After header
+const maxRetryAfter = time.Minute
+
+// fetchWithRetries calls doFetch, retrying network errors and 5xx and 429
+// responses up to opts.MaxRetries times. The last response is returned when
+// every attempt got a retryable status.
+func fetchWithRetries(ctx context.Context, client configuration.HTTPClient, rawURL string, opts FetchOptions) (*FetchResult, error) {
+	backoff := opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		res, err := doFetch(ctx, client, rawURL, opts)
+		if attempt >= opts.MaxRetries || ctx.Err() != nil || !retryable(res, err) {
+			return res, err
+		}
+
+		wait := backoff
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				wait = min(retryAfter, maxRetryAfter)
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// retryable reports whether a request which got res or err is worth retrying
+func retryable(res *FetchResult, err error) bool {
+	if err != nil {
+		return true
+	}
+	return res.StatusCode >= http.StatusInternalServerError || res.StatusCode == http.StatusTooManyRequests
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// doFetch performs the HTTP GET and reads the whole body
+func doFetch(ctx context.Context, client configuration.HTTPClient, rawURL string, opts FetchOptions) (*FetchResult, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = requestHeader(opts)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	var redirects []string
+	for req := resp.Request; req.Response != nil; req = req.Response.Request {
+		redirects = append([]string{req.Response.Request.URL.String()}, redirects...)
+	}
+
+	return &FetchResult{
+		URL:        resp.Request.URL.String(),
+		Redirects:  redirects,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}, nil
+}
+
+// requestHeader returns the headers of the requests made with opts
+func requestHeader(opts FetchOptions) http.Header {
+	header := http.Header{}
+	for k, v := range opts.Headers {
+		header.Set(k, v)
+	}
+	if opts.UserAgent != "" {
+		header.Set("User-Agent", opts.UserAgent)
+	}
+	return header
+}
+
+// requestKey identifies the requests which can share a response: the same
+// URL, see fetchKey, sent by the same client with the same headers
+func requestKey(client configuration.HTTPClient, rawURL string, opts FetchOptions) string {
+	var b strings.Builder
+	b.WriteString(fetchKey(rawURL))
+	b.WriteString("\n")
+	b.WriteString(clientKey(client))
+	header := requestHeader(opts)
+	for _, name := range slices.Sorted(maps.Keys(header)) {
+		fmt.Fprintf(&b, "\n%s: %s", name, strings.Join(header[name], ", "))
+	}
+	return b.String()
+}
+
+// clientKey identifies client, by address for pointers such as *http.Client
+func clientKey(client configuration.HTTPClient) string {
+	if client == nil {
+		return "default"
+	}
+	v := reflect.ValueOf(client)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return fmt.Sprintf("%T@%x", client, v.Pointer())
+	}
+	return fmt.Sprintf("%T%+v", client, client)
+}
+
+// fetchKey canonicalizes a URL so that trivially different spellings share a fetch
+func fetchKey(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
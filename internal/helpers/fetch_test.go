@@ -0,0 +1,239 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+)
+
+func TestFetcherCoalescesConcurrentRequests(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("<html>shared</html>"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher()
+	var wg sync.WaitGroup
+	results := make([]*FetchResult, 10)
+	errs := make([]error, 10)
+	for i := range 10 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = fetcher.Get(server.Client(), server.URL+"/article#comments", FetchOptions{})
+		}(i)
+	}
+	wg.Wait()
+
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected exactly one request, server saw %d", got)
+	}
+	for i := range 10 {
+		if errs[i] != nil {
+			t.Fatalf("Goroutine %d failed: %v", i, errs[i])
+		}
+		if string(results[i].Body) != "<html>shared</html>" || results[i].StatusCode != http.StatusOK {
+			t.Errorf("Goroutine %d got unexpected result %d %q", i, results[i].StatusCode, results[i].Body)
+		}
+	}
+}
+
+func TestFetcherRecentCache(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher()
+	opts := FetchOptions{RecentCacheSize: 1, RecentCacheTTL: time.Minute}
+
+	for range 3 {
+		if _, err := fetcher.Get(server.Client(), server.URL+"/a", opts); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected recently fetched page to be reused, server saw %d requests", got)
+	}
+
+	// Without the cache every sequential call reaches the server
+	if _, err := fetcher.Get(server.Client(), server.URL+"/a", FetchOptions{}); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("Expected a new request when the cache is disabled, server saw %d requests", got)
+	}
+
+	// A refresh reaches the server and replaces the cached page
+	refresh := opts
+	refresh.Refresh = true
+	if _, err := fetcher.Get(server.Client(), server.URL+"/a", refresh); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("Expected a new request for a refresh, server saw %d requests", got)
+	}
+
+	// A size of one evicts /a when /b is fetched
+	_, _ = fetcher.Get(server.Client(), server.URL+"/b", opts)
+	_, _ = fetcher.Get(server.Client(), server.URL+"/a", opts)
+	if got := hits.Load(); got != 5 {
+		t.Errorf("Expected /a to be evicted, server saw %d requests", got)
+	}
+}
+
+func TestFetcherContext(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+			_, _ = w.Write([]byte("<html>slow</html>"))
+		}
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := fetcher.Get(server.Client(), server.URL, FetchOptions{Context: ctx})
+		leaderErr <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// A caller sharing the canceled request makes its own
+	waiter := make(chan *FetchResult, 1)
+	go func() {
+		res, _ := fetcher.Get(server.Client(), server.URL, FetchOptions{})
+		waiter <- res
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the canceled request to fail with context.Canceled, got %v", err)
+	}
+	if res := <-waiter; res == nil || string(res.Body) != "<html>slow</html>" {
+		t.Errorf("Expected the other caller to get the page, got %+v", res)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("Expected the page to be requested again, server saw %d requests", got)
+	}
+
+	if _, err := fetcher.Get(server.Client(), server.URL, FetchOptions{Context: ctx}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected no request with a canceled context, got %v", err)
+	}
+}
+
+func TestFetcherRetries(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n := hits.Add(1); {
+		case n == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case n == 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte("<html>back</html>"))
+		}
+	}))
+	defer server.Close()
+
+	res, err := NewFetcher().Get(server.Client(), server.URL, FetchOptions{MaxRetries: 1, RetryBackoff: time.Millisecond})
+	if err != nil || res.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected the last response once the retries are exhausted, got %+v, %v", res, err)
+	}
+
+	hits.Store(0)
+	res, err = NewFetcher().Get(server.Client(), server.URL, FetchOptions{MaxRetries: 2, RetryBackoff: time.Millisecond})
+	if err != nil || res.StatusCode != http.StatusOK || string(res.Body) != "<html>back</html>" {
+		t.Errorf("Expected the page after 2 retries, got %+v, %v", res, err)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("Expected 3 requests, server saw %d", got)
+	}
+
+	hits.Store(2)
+	if _, err := NewFetcher().Get(server.Client(), server.URL, FetchOptions{MaxRetries: 2}); err != nil || hits.Load() != 3 {
+		t.Errorf("Expected a successful response not to be retried, server saw %d requests", hits.Load())
+	}
+
+	if wait, ok := parseRetryAfter("120"); !ok || wait != 2*time.Minute {
+		t.Errorf("Expected a Retry-After in seconds, got %v", wait)
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Error("Expected an invalid Retry-After to be ignored")
+	}
+}
+
+func TestFetcherRequestIdentity(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("Accept-Language") + " " + r.UserAgent()))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher()
+	cache := FetchOptions{RecentCacheSize: 10, RecentCacheTTL: time.Minute}
+	get := func(client configuration.HTTPClient, path string, opts FetchOptions) *FetchResult {
+		t.Helper()
+		opts.RecentCacheSize, opts.RecentCacheTTL = cache.RecentCacheSize, cache.RecentCacheTTL
+		res, err := fetcher.Get(client, server.URL+path, opts)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		return res
+	}
+
+	fr := get(server.Client(), "/a", FetchOptions{Headers: map[string]string{"Accept-Language": "fr"}, UserAgent: "reader/1.0"})
+	de := get(server.Client(), "/a", FetchOptions{Headers: map[string]string{"Accept-Language": "de"}, UserAgent: "reader/1.0"})
+	if string(fr.Body) != "fr reader/1.0" || string(de.Body) != "de reader/1.0" {
+		t.Errorf("Expected the responses to the headers of every caller, got %q and %q", fr.Body, de.Body)
+	}
+	other := get(&http.Client{}, "/a", FetchOptions{Headers: map[string]string{"Accept-Language": "fr"}, UserAgent: "reader/1.0"})
+	if hits.Load() != 3 || string(other.Body) != "fr reader/1.0" {
+		t.Errorf("Expected another client not to reuse the cached response, server saw %d requests", hits.Load())
+	}
+
+	// Results are copies
+	fr.Body[0] = 'x'
+	fr.Header.Set("X-Mutated", "1")
+	again := get(server.Client(), "/a", FetchOptions{Headers: map[string]string{"Accept-Language": "fr"}, UserAgent: "reader/1.0"})
+	if hits.Load() != 3 || string(again.Body) != "fr reader/1.0" || again.Header.Get("X-Mutated") != "" {
+		t.Errorf("Expected an unmodified cached response, got %q", again.Body)
+	}
+
+	// Error statuses are not cached
+	for range 2 {
+		if res := get(server.Client(), "/missing", FetchOptions{}); res.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected a 404, got %d", res.StatusCode)
+		}
+	}
+	if got := hits.Load(); got != 5 {
+		t.Errorf("Expected the 404 to be requested again, server saw %d requests", got)
+	}
+
+	if SharedHTTPClient(5, -1, nil) != SharedHTTPClient(5, -1, nil) || SharedHTTPClient(5, -1, nil) == SharedHTTPClient(5, 3, nil) {
+		t.Error("Expected one shared client per configuration")
+	}
+}
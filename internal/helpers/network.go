@@ -1,7 +1,13 @@
 package helpers
 
 import (
+	"fmt"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -10,14 +16,84 @@ const (
 	DefaultTimeoutSeconds int = 10
 )
 
-// CreateHTTPClient creates an HTTP client with timeout configuration
-func CreateHTTPClient(timeoutSeconds int) *http.Client {
-	return &http.Client{
+// CreateHTTPClient creates an HTTP client with timeout configuration, sending
+// the requests through proxies. proxies maps a URL scheme ("http", "https")
+// or "all" to a proxy URL, as RequestsParams.Proxies; without proxies the
+// proxy of the environment (HTTP_PROXY, HTTPS_PROXY) is used.
+func CreateHTTPClient(timeoutSeconds int, proxies map[string]string) *http.Client {
+	client := &http.Client{
 		Timeout: time.Duration(timeoutSeconds) * time.Second,
 	}
+	if len(proxies) > 0 {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = proxyFunc(proxies)
+		client.Transport = transport
+	}
+	return client
+}
+
+// proxyFunc returns the Transport.Proxy choosing the proxy of a request in
+// proxies by its scheme, then "all"
+func proxyFunc(proxies map[string]string) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxy, ok := proxies[strings.ToLower(req.URL.Scheme)]
+		if !ok {
+			proxy, ok = proxies["all"]
+		}
+		if !ok || proxy == "" {
+			return nil, nil
+		}
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", proxy, err)
+		}
+		return http.ProxyURL(u)(req)
+	}
 }
 
 // CreateDefaultHTTPClient creates an HTTP client with default timeout
 func CreateDefaultHTTPClient() *http.Client {
-	return CreateHTTPClient(DefaultTimeoutSeconds)
+	return CreateHTTPClient(DefaultTimeoutSeconds, nil)
+}
+
+// CreateHTTPClientWithRedirects creates an HTTP client with timeout
+// configuration following at most maxRedirects redirects, and failing on
+// redirect loops
+func CreateHTTPClientWithRedirects(timeoutSeconds, maxRedirects int, proxies map[string]string) *http.Client {
+	client := CreateHTTPClient(timeoutSeconds, proxies)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		for _, previous := range via {
+			if previous.URL.String() == req.URL.String() {
+				return fmt.Errorf("redirect loop back to %s", req.URL)
+			}
+		}
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+	return client
+}
+
+// sharedClients holds the clients of SharedHTTPClient by configuration
+var sharedClients sync.Map
+
+// SharedHTTPClient returns a client created by CreateHTTPClient, or by
+// CreateHTTPClientWithRedirects when maxRedirects is not negative, shared by
+// all the callers with the same configuration. A Fetcher tells clients apart
+// by identity, so only requests sent with a shared client are coalesced.
+func SharedHTTPClient(timeoutSeconds, maxRedirects int, proxies map[string]string) *http.Client {
+	key := fmt.Sprintf("%d/%d", timeoutSeconds, maxRedirects)
+	for _, scheme := range slices.Sorted(maps.Keys(proxies)) {
+		key += fmt.Sprintf("/%s=%s", scheme, proxies[scheme])
+	}
+	if client, ok := sharedClients.Load(key); ok {
+		return client.(*http.Client)
+	}
+	client := CreateHTTPClient(timeoutSeconds, proxies)
+	if maxRedirects >= 0 {
+		client = CreateHTTPClientWithRedirects(timeoutSeconds, maxRedirects, proxies)
+	}
+	shared, _ := sharedClients.LoadOrStore(key, client)
+	return shared.(*http.Client)
 }
@@ -1,6 +1,9 @@
 package helpers
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -30,7 +33,7 @@ func TestCreateHTTPClient(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client := CreateHTTPClient(tt.timeoutSeconds)
+			client := CreateHTTPClient(tt.timeoutSeconds, nil)
 			if client == nil {
 				t.Fatal("Expected non-nil client")
 			}
@@ -51,3 +54,39 @@ func TestCreateDefaultHTTPClient(t *testing.T) {
 		t.Errorf("Expected timeout %v, got %v", expectedTimeout, client.Timeout)
 	}
 }
+
+func TestCreateHTTPClientProxies(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("proxied " + r.URL.String()))
+	}))
+	defer proxy.Close()
+
+	tests := []struct {
+		name    string
+		proxies map[string]string
+	}{
+		{name: "by scheme", proxies: map[string]string{"http": proxy.URL}},
+		{name: "all schemes", proxies: map[string]string{"https": "http://unused.invalid", "all": proxy.URL}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := CreateHTTPClient(5, tt.proxies).Get("http://news.invalid/story")
+			if err != nil {
+				t.Fatalf("Error sending the request through the proxy: %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != "proxied http://news.invalid/story" {
+				t.Errorf("Expected the proxy to answer, got %q", body)
+			}
+		})
+	}
+
+	if _, err := CreateHTTPClient(5, map[string]string{"http": "://bad"}).Get("http://news.invalid/story"); err == nil {
+		t.Error("Expected an error for an invalid proxy URL")
+	}
+	if SharedHTTPClient(5, -1, map[string]string{"http": proxy.URL}) == SharedHTTPClient(5, -1, nil) {
+		t.Error("Expected clients with different proxies not to be shared")
+	}
+}
@@ -0,0 +1,109 @@
+package helpers
+
+import (
+	"bufio"
+	"strings"
+)
+
+// robotsRule is an Allow or Disallow line of a robots.txt group
+type robotsRule struct {
+	allow bool
+	path  string
+}
+
+// RobotsAllowed tells whether the robots.txt content robotsTxt lets the
+// crawler named by userAgent fetch path, following RFC 9309: the group of the
+// longest user-agent token found in userAgent applies, else the "*" group,
+// and the longest matching rule wins, Allow winning ties. Rules support the
+// "*" and "$" wildcards.
+func RobotsAllowed(robotsTxt, userAgent, path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	userAgent = strings.ToLower(userAgent)
+
+	var (
+		rules, wildcard []robotsRule
+		bestAgent       string
+		groupAgents     []string
+		groupRules      []robotsRule
+		inRules         bool
+	)
+	endGroup := func() {
+		for _, agent := range groupAgents {
+			switch {
+			case agent == "*":
+				wildcard = append(wildcard, groupRules...)
+			case strings.Contains(userAgent, agent) && len(agent) > len(bestAgent):
+				bestAgent, rules = agent, groupRules
+			case agent == bestAgent:
+				rules = append(rules, groupRules...)
+			}
+		}
+		groupAgents, groupRules, inRules = nil, nil, false
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(robotsTxt))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "user-agent":
+			// A user-agent line after rules starts a new group
+			if inRules {
+				endGroup()
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			// An empty Disallow allows everything
+			if value != "" {
+				groupRules = append(groupRules, robotsRule{allow: strings.EqualFold(strings.TrimSpace(field), "allow"), path: value})
+			}
+		}
+	}
+	endGroup()
+
+	if bestAgent == "" {
+		rules = wildcard
+	}
+	allowed, longest := true, -1
+	for _, rule := range rules {
+		if len(rule.path) < longest || !robotsPathMatch(rule.path, path) {
+			continue
+		}
+		if len(rule.path) > longest || rule.allow {
+			allowed, longest = rule.allow, len(rule.path)
+		}
+	}
+	return allowed
+}
+
+// robotsPathMatch tells whether path starts with the robots.txt pattern, "*"
+// matching any sequence of characters and a final "$" the end of path
+func robotsPathMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for _, part := range parts[1:] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	if !anchored {
+		return true
+	}
+	// The last part has to end path, try its last occurrence
+	last := parts[len(parts)-1]
+	return rest == "" || (len(parts) > 1 && strings.HasSuffix(path, last))
+}
@@ -0,0 +1,46 @@
+package helpers
+
+import "testing"
+
+func TestRobotsAllowed(t *testing.T) {
+	robotsTxt := `# Example
+User-agent: *
+Disallow: /private/
+Allow: /private/press/
+Disallow: /*.pdf$
+
+User-agent: newspaper4k-go
+User-agent: otherbot
+Disallow: /drafts
+
+User-agent: blocked
+Disallow: /
+`
+	tests := []struct {
+		userAgent, path string
+		allowed         bool
+	}{
+		{"Mozilla/5.0", "/2025/04/01/story.html", true},
+		{"Mozilla/5.0", "/private/notes.html", false},
+		{"Mozilla/5.0", "/private/press/release.html", true},
+		{"Mozilla/5.0", "/files/report.pdf", false},
+		{"Mozilla/5.0", "/files/report.pdf.html", true},
+		// The group of the crawler replaces the "*" group
+		{"newspaper4k-go/1.7.0", "/private/notes.html", true},
+		{"newspaper4k-go/1.7.0", "/drafts/story.html", false},
+		{"Blocked/2.0", "/", false},
+		{"Mozilla/5.0", "", true},
+	}
+	for _, tt := range tests {
+		if allowed := RobotsAllowed(robotsTxt, tt.userAgent, tt.path); allowed != tt.allowed {
+			t.Errorf("RobotsAllowed(%q, %q) = %v, expected %v", tt.userAgent, tt.path, allowed, tt.allowed)
+		}
+	}
+
+	if !RobotsAllowed("", "newspaper4k-go", "/story") {
+		t.Error("Expected an empty robots.txt to allow everything")
+	}
+	if !RobotsAllowed("User-agent: *\nDisallow:\n", "newspaper4k-go", "/story") {
+		t.Error("Expected an empty Disallow to allow everything")
+	}
+}
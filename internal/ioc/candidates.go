@@ -0,0 +1,210 @@
+package ioc
+
+import (
+	"sort"
+	"strings"
+)
+
+// The regexes are run on the tokens (runs of non-space characters) that may
+// hold an IOC of their type only. Go regexes run in linear time, but slowly
+// on the large patterns, so running every regex on the whole text of an
+// adversarial page, e.g. 1 MB of "a.a-a.a-", took seconds.
+
+// span is the range [start, end) of data
+type span struct {
+	start, end int
+}
+
+// tokenFilters tell whether a token may hold an IOC of their type. They are
+// necessary conditions of the regexes, checked without them.
+var tokenFilters = map[Type]func(token string) bool{
+	Bitcoin: func(token string) bool { return hasRun(token, 26, isAlphanumeric) },
+	MD5:     func(token string) bool { return hasRun(token, 32, isHexDigit) },
+	SHA1:    func(token string) bool { return hasRun(token, 40, isHexDigit) },
+	SHA256:  func(token string) bool { return hasRun(token, 64, isHexDigit) },
+	SHA512:  func(token string) bool { return hasRun(token, 128, isHexDigit) },
+	Domain:  hasDomainTLD,
+	IPv4: func(token string) bool {
+		return strings.ContainsAny(token, "0123456789") && strings.Contains(token, ".")
+	},
+	IPv6:  func(token string) bool { return strings.Count(token, ":") >= 2 },
+	URL:   func(token string) bool { return strings.Contains(token, "://") },
+	File:  hasFileExtension,
+	CVE:   func(token string) bool { return containsFold(token, "cve-") },
+	CAPEC: func(token string) bool { return containsFold(token, "capec-") },
+	CWE:   func(token string) bool { return containsFold(token, "cwe-") },
+	CPE:   func(token string) bool { return containsFold(token, "cpe:") },
+}
+
+// emailTokens is the number of tokens around an "@" or "[at]" an email may
+// span, as in "john [ at ] example.com"
+const emailTokens = 3
+
+// candidateSpans returns the spans of data the regex of t has to run on, in
+// order. A span starts at the space before its token, for the patterns
+// looking at it. Email addresses span several tokens when defanged with
+// spaces, their spans cover the tokens around every "@" and "at".
+func candidateSpans(data string, tokens []span, t Type) []span {
+	var spans []span
+	add := func(first, last int) {
+		s := span{start: max(tokens[first].start-1, 0), end: tokens[last].end}
+		if n := len(spans); n > 0 && s.start <= spans[n-1].end {
+			spans[n-1].end = max(spans[n-1].end, s.end)
+			return
+		}
+		spans = append(spans, s)
+	}
+
+	if t == Email {
+		for _, anchor := range emailAnchors(data) {
+			i := sort.Search(len(tokens), func(i int) bool { return tokens[i].end > anchor })
+			add(max(i-emailTokens, 0), min(i+emailTokens, len(tokens)-1))
+		}
+		return spans
+	}
+
+	filter := tokenFilters[t]
+	for i, token := range tokens {
+		if filter == nil || filter(data[token.start:token.end]) {
+			add(i, i)
+		}
+	}
+	return spans
+}
+
+// splitTokens returns the runs of non-space characters of data, space being
+// the ASCII spaces matched by \s
+func splitTokens(data string) []span {
+	var tokens []span
+	start := -1
+	for i := 0; i <= len(data); i++ {
+		if i < len(data) && !isSpace(data[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, span{start: start, end: i})
+			start = -1
+		}
+	}
+	return tokens
+}
+
+// emailAnchors returns the offsets of the "@" of data, and of the "at"
+// following a bracket as in "[at]" or "( at )"
+func emailAnchors(data string) []int {
+	var anchors []int
+	for i := 0; i < len(data); i++ {
+		switch {
+		case data[i] == '@':
+			anchors = append(anchors, i)
+		case i+1 < len(data) && (data[i] == 'a' || data[i] == 'A') && (data[i+1] == 't' || data[i+1] == 'T'):
+			before := i - 1
+			if before >= 0 && data[before] == ' ' {
+				before--
+			}
+			if before >= 0 && (data[before] == '[' || data[before] == '(') {
+				anchors = append(anchors, i)
+			}
+		}
+	}
+	return anchors
+}
+
+// hasDomainTLD tells whether a dot of token, possibly defanged as "[.]",
+// is followed by a top-level domain of the Domain regex ending a word
+func hasDomainTLD(token string) bool {
+	for i := strings.IndexByte(token, '.'); i >= 0; i = nextIndexByte(token, '.', i) {
+		start := i + 1
+		if start < len(token) && (token[start] == ']' || token[start] == ')') {
+			start++
+		}
+		end := start
+		for end < len(token) && token[end] >= 'a' && token[end] <= 'z' {
+			end++
+		}
+		if end > start && (end == len(token) || !isWordChar(token[end])) && domainTLDSet[token[start:end]] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFileExtension tells whether a dot of token is followed by an extension
+// of the File regex
+func hasFileExtension(token string) bool {
+	for i := strings.IndexByte(token, '.'); i >= 0; i = nextIndexByte(token, '.', i) {
+		for end := i + 2; end <= min(i+1+maxFileExtensionLength, len(token)) && isLowerAlphanumeric(token[end-1]); end++ {
+			if fileExtensionSet[token[i+1:end]] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maxFileExtensionLength is the length of the longest of fileExtensions
+var maxFileExtensionLength = func() int {
+	longest := 0
+	for extension := range fileExtensionSet {
+		longest = max(longest, len(extension))
+	}
+	return longest
+}()
+
+// nextIndexByte returns the index of the first c of s after i, or -1
+func nextIndexByte(s string, c byte, i int) int {
+	next := strings.IndexByte(s[i+1:], c)
+	if next < 0 {
+		return -1
+	}
+	return i + 1 + next
+}
+
+// hasRun tells whether s has at least n consecutive bytes accepted by valid
+func hasRun(s string, n int, valid func(byte) bool) bool {
+	run := 0
+	for i := 0; i < len(s); i++ {
+		if !valid(s[i]) {
+			run = 0
+			continue
+		}
+		if run++; run >= n {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold tells whether s contains the lower case ASCII substr, ignoring
+// the case
+func containsFold(s, substr string) bool {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i]|0x20 == substr[0] && strings.EqualFold(s[i:i+len(substr)], substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+func isWordChar(c byte) bool {
+	return isAlphanumeric(c) || c == '_'
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isLowerAlphanumeric(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z'
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
@@ -0,0 +1,94 @@
+package ioc
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tguidoux/newspaper4k-go/internal/helpers"
+)
+
+func FuzzExtractIOCs(f *testing.F) {
+	f.Add("contact john[AT]example[.]com or visit hxxps://evil[.]example[.]com/a.exe")
+	f.Add("192.168.1[.]1 2001:db8::1 CVE-2024-12345 cpe:2.3:a:vendor:product")
+	f.Add("john [ at ] example.com 1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2 report.pdf.exe d41d8cd98f00b204e9800998ecf8427e")
+	f.Add("a.a-a.a- test(@)test.com x.co-uk y.comz fe80::1 CWE-79")
+	f.Fuzz(func(t *testing.T, data string) {
+		found := make(map[Type][]string)
+		for _, i := range ExtractIOCs(data, true) {
+			if i.IOC == "" {
+				t.Errorf("Empty IOC of type %v in %q", i.Type, data)
+			}
+			_ = i.toFanged().toDefanged()
+			found[i.Type] = append(found[i.Type], i.IOC)
+		}
+
+		// The candidate filters must not change the matches of the regexes
+		data = dropLongTokens(data, MaxTokenLength)
+		for typ, regex := range iocRegexes {
+			if expected := helpers.UniqueStringsSimple(regex.FindAllString(data, -1)); !slices.Equal(found[typ], expected) {
+				t.Errorf("%v in %q: expected %q, got %q", typ, data, expected, found[typ])
+			}
+		}
+	})
+}
+
+func FuzzParseIOC(f *testing.F) {
+	f.Add("john[AT]example[.]com")
+	f.Add("hxxp[://]example[.]com")
+	f.Fuzz(func(t *testing.T, data string) {
+		if ioc := ParseIOC(data); ioc == nil {
+			t.Errorf("Expected an IOC for %q", data)
+		} else if ioc.IOC != "" && !strings.Contains(data, ioc.IOC) {
+			t.Errorf("IOC %q not found in %q", ioc.IOC, data)
+		}
+	})
+}
+
+func TestExtractIOCsSkipsLongTokens(t *testing.T) {
+	data := "mail john@example.com " + strings.Repeat("a.", MaxTokenLength) + " then visit example.org"
+	var found []string
+	for _, i := range ExtractIOCs(data, true) {
+		if len(i.IOC) > MaxTokenLength {
+			t.Errorf("Expected the long run to be skipped, got a %v of %d bytes", i.Type, len(i.IOC))
+		}
+		found = append(found, i.IOC)
+	}
+	for _, expected := range []string{"john@example.com", "example.org"} {
+		if !slices.Contains(found, expected) {
+			t.Errorf("Expected %q around the long run, got %q", expected, found)
+		}
+	}
+
+	if got := dropLongTokens("short words\tonly", 8); got != "short words\tonly" {
+		t.Errorf("Expected short tokens to be kept, got %q", got)
+	}
+	if got := dropLongTokens("keep verylongtoken keep", 8); got != "keep   keep" {
+		t.Errorf("Expected the long token to be replaced by a space, got %q", got)
+	}
+}
+
+// minAdversarialThroughput is the throughput, in MB/s, ExtractIOCs has to keep
+// on adversarial text
+const minAdversarialThroughput = 10
+
+// BenchmarkExtractIOCsAdversarial measures 1 MB of pathological text: long
+// runs of dots and hyphens, which are skipped, and short ones, which are not
+// but hold no candidate token for the large patterns
+func BenchmarkExtractIOCsAdversarial(b *testing.B) {
+	inputs := map[string]string{
+		"long runs":  strings.Repeat("a.", 1<<19),
+		"short runs": strings.Repeat(strings.Repeat("a.a-", MaxTokenLength/4-1)+" ", 1<<20/MaxTokenLength),
+	}
+	for name, data := range inputs {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for b.Loop() {
+				ExtractIOCs(data, true)
+			}
+			if throughput := float64(len(data)*b.N) / 1e6 / b.Elapsed().Seconds(); throughput < minAdversarialThroughput {
+				b.Errorf("Expected at least %d MB/s, got %.2f MB/s", minAdversarialThroughput, throughput)
+			}
+		})
+	}
+}
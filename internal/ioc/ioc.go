@@ -3,6 +3,9 @@ package ioc
 import (
 	"context"
 	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/tguidoux/newspaper4k-go/internal/helpers"
 )
@@ -20,13 +23,29 @@ func ParseIOC(ioc string) *IOC {
 	return ret
 }
 
+// MaxTokenLength is the length of the longest run of non-space characters
+// searched for IOCs. Longer runs, such as minified scripts, base64 blobs or
+// adversarial "a.a.a.a..." strings, cannot be IOCs and are skipped.
+const MaxTokenLength = 4096
+
 // ExtractIOCs returns the IOCs found in the provided data. When getFangedIOCs
 // is true, fanged IOCs are included as well.
+//
+// The runs longer than MaxTokenLength are skipped, and each regular
+// expression only runs on the tokens that may hold an IOC of its type, see
+// BenchmarkExtractIOCsAdversarial for the throughput on adversarial text.
 func ExtractIOCs(data string, getFangedIOCs bool) []*IOC {
 	var iocs []*IOC
 
+	data = dropLongTokens(data, MaxTokenLength)
+	tokens := splitTokens(data)
+
 	for t, regex := range iocRegexes {
-		matches := helpers.UniqueStringsSimple(regex.FindAllString(data, -1))
+		var found []string
+		for _, s := range candidateSpans(data, tokens, t) {
+			found = append(found, regex.FindAllString(data[s.start:s.end], -1)...)
+		}
+		matches := helpers.UniqueStringsSimple(found)
 		for _, m := range matches {
 			i := &IOC{IOC: m, Type: t}
 			if !i.isFanged() || getFangedIOCs {
@@ -37,6 +56,33 @@ func ExtractIOCs(data string, getFangedIOCs bool) []*IOC {
 	return iocs
 }
 
+// dropLongTokens replaces the runs of non-space characters longer than
+// maxLength by a space
+func dropLongTokens(data string, maxLength int) string {
+	var b strings.Builder
+	start := 0 // start of the current run
+	kept := 0  // end of the data copied to b
+	for i, r := range data + " " {
+		if !unicode.IsSpace(r) {
+			continue
+		}
+		if i-start > maxLength {
+			if b.Len() == 0 {
+				b.Grow(len(data))
+			}
+			b.WriteString(data[kept:start])
+			b.WriteByte(' ')
+			kept = i
+		}
+		start = i + utf8.RuneLen(r)
+	}
+	if kept == 0 {
+		return data
+	}
+	b.WriteString(data[min(kept, len(data)):])
+	return b.String()
+}
+
 // ExtractIOCsReader reads from reader, extracts IOCs and sends them to matches.
 // Note: output order is not deterministic because of map-based deduplication.
 func ExtractIOCsReader(ctx context.Context, reader io.Reader, getFangedIOCs bool, matches chan *IOC) error {
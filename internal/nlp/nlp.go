@@ -331,20 +331,25 @@ func Keywords(text string, stopwords *StopWords, maxKeywords int) map[string]flo
 	}
 
 	freq := make(map[string]int)
+	var order []string
 	for _, token := range filteredTokens {
+		if freq[token] == 0 {
+			order = append(order, token)
+		}
 		freq[token]++
 	}
 
-	// Get most common
+	// Get most common, ties in order of first occurrence so that the
+	// keywords of a text do not change from one run to the next
 	type kv struct {
 		Key   string
 		Value int
 	}
 	var sorted []kv
-	for k, v := range freq {
-		sorted = append(sorted, kv{k, v})
+	for _, k := range order {
+		sorted = append(sorted, kv{k, freq[k]})
 	}
-	sort.Slice(sorted, func(i, j int) bool {
+	sort.SliceStable(sorted, func(i, j int) bool {
 		return sorted[i].Value > sorted[j].Value
 	})
 
@@ -383,31 +388,53 @@ func SplitSentences(text string) []string {
 	return cleaned
 }
 
-// Summarize summarizes an article into the most relevant sentences
-func Summarize(title, text string, stopwords *StopWords, maxSents int) []string {
+// SummaryWeights weigh the four features blended into the score of a
+// sentence by ScoredSentences
+type SummaryWeights struct {
+	Title     float64 // Overlap with the title
+	Frequency float64 // Density of the keywords of the text
+	Length    float64 // Closeness to the ideal sentence length
+	Position  float64 // Position in the text
+}
+
+// DefaultSummaryWeights are the historical weights of the summarizer
+var DefaultSummaryWeights = SummaryWeights{Title: 1.5, Frequency: 2.0, Length: 1.0, Position: 1.0}
+
+// Summarize summarizes an article into the most relevant sentences, scored with weights
+func Summarize(title, text string, stopwords *StopWords, maxSents int, weights SummaryWeights) []string {
 	if len(text) == 0 || len(title) == 0 || maxSents <= 0 {
 		return []string{}
 	}
 
 	summaries := []string{}
+	for _, rank := range KeySentences(title, text, stopwords, maxSents, weights) {
+		summaries = append(summaries, rank.Sentence)
+	}
+	return summaries
+}
+
+// KeySentences returns the n best ranked sentences of text with their scores,
+// in the order of the text
+func KeySentences(title, text string, stopwords *StopWords, n int, weights SummaryWeights) []SentenceRank {
+	if len(text) == 0 || len(title) == 0 || n <= 0 {
+		return []SentenceRank{}
+	}
+
 	sentences := SplitSentences(text)
 	keys := Keywords(text, stopwords, SummarizeKeywordCount)
 	titleWords := stopwords.Tokenize(title)
 
 	// Score sentences
-	ranks := ScoredSentences(sentences, titleWords, keys, stopwords)
+	ranks := ScoredSentences(sentences, titleWords, keys, stopwords, weights)
 
-	// Filter out the first maxSents relevant sentences
-	if len(ranks) > maxSents {
-		ranks = ranks[:maxSents]
+	// Filter out the first n relevant sentences
+	if len(ranks) > n {
+		ranks = ranks[:n]
 	}
 	sort.Slice(ranks, func(i, j int) bool {
 		return ranks[i].Index < ranks[j].Index // Sort by sentence order in the text
 	})
-	for _, rank := range ranks {
-		summaries = append(summaries, rank.Sentence)
-	}
-	return summaries
+	return ranks
 }
 
 // SentenceRank represents a sentence with its score and index
@@ -441,8 +468,8 @@ func TitleScore(titleTokens, sentenceTokens []string, stopwords *StopWords) floa
 	return float64(intersection) / float64(len(filteredTitle))
 }
 
-// ScoredSentences scores sentences based on different features
-func ScoredSentences(sentences, titleWords []string, keywords map[string]float64, stopwords *StopWords) []SentenceRank {
+// ScoredSentences scores sentences based on different features, blended with weights
+func ScoredSentences(sentences, titleWords []string, keywords map[string]float64, stopwords *StopWords, weights SummaryWeights) []SentenceRank {
 	sentenceCount := len(sentences)
 	ranks := make([]SentenceRank, 0, sentenceCount)
 
@@ -455,7 +482,7 @@ func ScoredSentences(sentences, titleWords []string, keywords map[string]float64
 		dbsFeature := DBS(sentenceTokens, keywords)
 		frequency := (sbsFeature + dbsFeature) / 2.0 * 10.0
 		// Weighted average of scores from four categories
-		totalScore := (titleFeatures*1.5 + frequency*2.0 + sentLen*1.0 + sentPos*1.0) / 4.0
+		totalScore := (titleFeatures*weights.Title + frequency*weights.Frequency + sentLen*weights.Length + sentPos*weights.Position) / 4.0
 		ranks = append(ranks, SentenceRank{
 			Index:    i,
 			Sentence: s,
@@ -96,7 +96,7 @@ func TestSummarize(t *testing.T) {
 	title := "Test Article"
 	text := "This is the first sentence. It contains important information. The second sentence is also relevant. However, this sentence might be less important. Finally, the last sentence summarizes everything."
 
-	summary := Summarize(title, text, sw, 2)
+	summary := Summarize(title, text, sw, 2, DefaultSummaryWeights)
 
 	if len(summary) != 2 {
 		t.Errorf("Expected 2 sentences in summary, got %d", len(summary))
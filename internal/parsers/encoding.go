@@ -0,0 +1,107 @@
+package parsers
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// utf16Sample is the number of leading bytes inspected to guess a BOM-less UTF-16 page
+const utf16Sample = 512
+
+// GetUnicodeHTML handles encoding detection and returns proper UTF-8 HTML.
+// UTF-16 content (with or without a byte order mark) is transcoded to UTF-8
+// and stray NUL bytes are stripped, since they make the HTML parser drop the document.
+// Other encodings are detected as by GetUnicodeHTMLWithContentType without a
+// Content-Type header.
+func GetUnicodeHTML(htmlContent string) string {
+	return GetUnicodeHTMLWithContentType(htmlContent, "")
+}
+
+// GetUnicodeHTMLWithContentType is like GetUnicodeHTML for a page served with
+// the contentType header. Content which is not valid UTF-8 is transcoded from
+// the charset of contentType, else the one declared by a <meta charset> or
+// http-equiv tag in its first 1024 bytes, else the one sniffed by
+// golang.org/x/net/html/charset (windows-1252 for an undeclared page). Valid
+// UTF-8 is kept as is whatever the declared charset, since it is what a
+// mislabeled page or HTML already decoded by the caller looks like.
+func GetUnicodeHTMLWithContentType(htmlContent, contentType string) string {
+	if htmlContent == "" {
+		return htmlContent
+	}
+
+	switch {
+	case strings.HasPrefix(htmlContent, "\xef\xbb\xbf"):
+		htmlContent = htmlContent[3:]
+	case strings.HasPrefix(htmlContent, "\xff\xfe"):
+		htmlContent = decodeUTF16(htmlContent[2:], unicode.LittleEndian)
+	case strings.HasPrefix(htmlContent, "\xfe\xff"):
+		htmlContent = decodeUTF16(htmlContent[2:], unicode.BigEndian)
+	default:
+		if endianness, ok := guessUTF16(htmlContent); ok {
+			htmlContent = decodeUTF16(htmlContent, endianness)
+		} else if !utf8.ValidString(htmlContent) {
+			htmlContent = decodeCharset(htmlContent, contentType)
+		}
+	}
+
+	return strings.ReplaceAll(htmlContent, "\x00", "")
+}
+
+// decodeCharset transcodes content from its declared or sniffed charset to
+// UTF-8, returning it unchanged when it cannot be decoded
+func decodeCharset(content, contentType string) string {
+	enc, _, _ := charset.DetermineEncoding([]byte(content), contentType)
+	if enc == nil {
+		return content
+	}
+	decoded, err := enc.NewDecoder().String(content)
+	if err != nil {
+		return content
+	}
+	return decoded
+}
+
+// guessUTF16 detects BOM-less UTF-16 by the position of the NUL bytes that
+// pad ASCII characters (markup is mostly ASCII, even on East Asian pages)
+func guessUTF16(content string) (unicode.Endianness, bool) {
+	sample := content[:min(len(content), utf16Sample)]
+	if len(sample) < 4 {
+		return unicode.LittleEndian, false
+	}
+
+	evenNUL, oddNUL := 0, 0
+	for i := 0; i < len(sample); i++ {
+		if sample[i] != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenNUL++
+		} else {
+			oddNUL++
+		}
+	}
+
+	pairs := len(sample) / 2
+	switch {
+	case oddNUL*10 >= pairs*4 && evenNUL*10 < pairs:
+		return unicode.LittleEndian, true
+	case evenNUL*10 >= pairs*4 && oddNUL*10 < pairs:
+		return unicode.BigEndian, true
+	}
+	return unicode.LittleEndian, false
+}
+
+// decodeUTF16 transcodes UTF-16 content to UTF-8, returning the input unchanged on failure
+func decodeUTF16(content string, endianness unicode.Endianness) string {
+	if len(content)%2 == 1 {
+		content = content[:len(content)-1]
+	}
+	decoded, err := unicode.UTF16(endianness, unicode.IgnoreBOM).NewDecoder().String(content)
+	if err != nil {
+		return content
+	}
+	return decoded
+}
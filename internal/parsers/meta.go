@@ -0,0 +1,154 @@
+package parsers
+
+import (
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/helpers"
+	"github.com/tguidoux/newspaper4k-go/internal/languages"
+	"github.com/tguidoux/newspaper4k-go/internal/urls"
+)
+
+// MetaField returns the content of the first non-empty meta tag among fields
+func MetaField(node *goquery.Selection, fields ...string) string {
+	for _, f := range fields {
+		// GetMetatags handles the property/name/itemprop variations
+		for _, m := range GetMetatags(node, f) {
+			content := GetAttribute(m, "content", nil, "")
+			if contentStr, ok := content.(string); ok && strings.TrimSpace(contentStr) != "" {
+				return strings.TrimSpace(contentStr)
+			}
+		}
+	}
+	return ""
+}
+
+// MetaKeywords returns the comma-separated values of the news_keywords and
+// keywords meta tags, curated news_keywords first, without case-insensitive
+// duplicates
+func MetaKeywords(node *goquery.Selection) []string {
+	var out []string
+	for _, field := range []string{"news_keywords", "keywords"} {
+		for _, p := range strings.Split(MetaField(node, field), ",") {
+			if t := strings.TrimSpace(p); t != "" {
+				out = append(out, t)
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return helpers.UniqueStrings(out, helpers.UniqueOptions{CaseSensitive: false, PreserveOrder: true})
+}
+
+// CanonicalLink returns the URL of the first <link rel="canonical">, or of
+// the og:url meta tag, resolved against pageURL when relative
+func CanonicalLink(pageURL string, node *goquery.Selection) string {
+	// Collect candidate URLs: <link rel="canonical"> and og:url
+	var candidates []string
+
+	for _, el := range GetTags(node, "link", map[string]string{"rel": "canonical"}, "exact", false) {
+		attr := GetAttribute(el, "href", nil, "")
+		if hrefStr, ok := attr.(string); ok && strings.TrimSpace(hrefStr) != "" {
+			candidates = append(candidates, strings.TrimSpace(hrefStr))
+			break // prefer first canonical link
+		}
+	}
+
+	if og := MetaField(node, "og:url"); og != "" {
+		candidates = append(candidates, og)
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	raw := candidates[0]
+
+	// Parse using net/url. If relative, resolve against pageURL.
+	parsedMeta, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	if parsedMeta.IsAbs() {
+		return parsedMeta.String()
+	}
+
+	parsedPage, err := urls.Parse(pageURL)
+	if err != nil {
+		return parsedMeta.String()
+	}
+
+	return parsedPage.ResolveReference(parsedMeta).String()
+}
+
+// FeedLinks returns the URLs of the RSS and Atom feeds declared by <link
+// rel="alternate"> tags, resolved against pageURL, in document order
+func FeedLinks(pageURL string, node *goquery.Selection) []string {
+	var feeds []string
+	for _, el := range GetTags(node, "link", map[string]string{"rel": "alternate"}, "word", false) {
+		mediaType, _, _ := mime.ParseMediaType(el.AttrOr("type", ""))
+		if mediaType != "application/rss+xml" && mediaType != "application/atom+xml" {
+			continue
+		}
+		if href := strings.TrimSpace(el.AttrOr("href", "")); href != "" {
+			feeds = append(feeds, urls.JoinURL(pageURL, href))
+		}
+	}
+	return feeds
+}
+
+// HreflangLink returns the URL of the <link rel="alternate" hreflang> of
+// the page in lang, resolved against pageURL. An alternate declared for the
+// exact language is preferred to a regional variant ("fr" to "fr-CA"). It
+// returns "" when the page declares no alternate in lang.
+func HreflangLink(pageURL string, node *goquery.Selection, lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		return ""
+	}
+	variant := ""
+	for _, el := range GetTags(node, "link", map[string]string{"rel": "alternate"}, "word", false) {
+		href := strings.TrimSpace(el.AttrOr("href", ""))
+		hreflang := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(el.AttrOr("hreflang", "")), "_", "-"))
+		if href == "" || hreflang == "" {
+			continue
+		}
+		if hreflang == lang {
+			return urls.JoinURL(pageURL, href)
+		}
+		if primary, _, _ := strings.Cut(hreflang, "-"); primary == lang && variant == "" {
+			variant = urls.JoinURL(pageURL, href)
+		}
+	}
+	return variant
+}
+
+// LangAttribute returns the first valid lang attribute of sel
+func LangAttribute(sel *goquery.Selection) string {
+	lang := ""
+	sel.EachWithBreak(func(i int, s *goquery.Selection) bool {
+		lang = LanguageCode(s.AttrOr("lang", ""))
+		return lang == ""
+	})
+	return lang
+}
+
+// LanguageCode returns the language code of a lang attribute or meta tag
+// value, reduced to its primary subtag when needed ("de-DE" gives "de"), or ""
+// when it is not a known language
+func LanguageCode(raw string) string {
+	lang := strings.ToLower(strings.TrimSpace(raw))
+	if _, ok := languages.LanguagesDict[lang]; ok {
+		return lang
+	}
+	if primary, _, found := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-"); found {
+		if _, ok := languages.LanguagesDict[primary]; ok {
+			return primary
+		}
+	}
+	return ""
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"math"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
@@ -27,14 +28,6 @@ func DropTags(nodes ...*goquery.Selection) {
 	}
 }
 
-// GetUnicodeHTML handles encoding detection and returns proper UTF-8 HTML
-func GetUnicodeHTML(htmlContent string) string {
-	// Go natively handles UTF-8, so we just return the string as-is
-	// In the original Python code, this used UnicodeDammit from bs4
-	// but Go's string type is already UTF-8
-	return htmlContent
-}
-
 // FromString parses HTML string into a goquery document
 func FromString(htmlContent string) (*goquery.Document, error) {
 	htmlContent = GetUnicodeHTML(htmlContent)
@@ -77,8 +70,7 @@ func GetTagsRegex(node *goquery.Selection, tag string, attribs map[string]string
 			continue
 		}
 
-		selector := fmt.Sprintf("%s[%s]", tag, attr)
-		node.Find(selector).Each(func(i int, s *goquery.Selection) {
+		node.Find(tagSelector(tag)).Each(func(i int, s *goquery.Selection) {
 			attrVal, exists := s.Attr(attr)
 			if exists && regex.MatchString(attrVal) {
 				results = append(results, s)
@@ -89,15 +81,13 @@ func GetTagsRegex(node *goquery.Selection, tag string, attribs map[string]string
 	return results
 }
 
-// GetTags gets list of elements of a certain tag with exact matching attributes
+// GetTags gets list of elements of a certain tag with exact matching attributes.
+// Attribute values are compared in Go rather than injected into a selector, so
+// values containing quotes, brackets or control characters are matched safely.
 func GetTags(node *goquery.Selection, tag string, attribs map[string]string, attribsMatch string, ignoreDashes bool) []*goquery.Selection {
 	if attribs == nil {
-		selector := tag
-		if tag == "" {
-			selector = "*"
-		}
 		var results []*goquery.Selection
-		node.Find(selector).Each(func(i int, s *goquery.Selection) {
+		node.Find(tagSelector(tag)).Each(func(i int, s *goquery.Selection) {
 			results = append(results, s)
 		})
 		return results
@@ -105,33 +95,54 @@ func GetTags(node *goquery.Selection, tag string, attribs map[string]string, att
 
 	var results []*goquery.Selection
 
-	for attr, value := range attribs {
-		var selector string
+	switch attribsMatch {
+	case "exact", "substring", "word":
+	default:
+		log.Printf("attribs_match must be one of 'exact', 'substring' or 'word'")
+		return results
+	}
 
-		switch attribsMatch {
-		case "exact":
-			if ignoreDashes {
-				selector = fmt.Sprintf("%s[%s='%s']", tag, attr, value)
-			} else {
-				selector = fmt.Sprintf("%s[%s='%s']", tag, attr, value)
+	for attr, value := range attribs {
+		node.Find(tagSelector(tag)).Each(func(i int, s *goquery.Selection) {
+			attrVal, exists := s.Attr(attr)
+			if exists && matchAttribute(attrVal, value, attribsMatch) {
+				results = append(results, s)
 			}
-		case "substring":
-			selector = fmt.Sprintf("%s[%s*='%s']", tag, attr, value)
-		case "word":
-			selector = fmt.Sprintf("%s[%s~='%s']", tag, attr, value)
-		default:
-			log.Printf("attribs_match must be one of 'exact', 'substring' or 'word'")
-			return results
-		}
-
-		node.Find(selector).Each(func(i int, s *goquery.Selection) {
-			results = append(results, s)
 		})
 	}
 
 	return results
 }
 
+// tagSelector returns a selector for tag, matching any element when tag is empty
+func tagSelector(tag string) string {
+	if tag == "" {
+		return "*"
+	}
+	return tag
+}
+
+// matchAttribute compares an attribute value the way the CSS attribute selectors
+// [attr=value], [attr*=value] and [attr~=value] do
+func matchAttribute(attrVal, value, attribsMatch string) bool {
+	switch attribsMatch {
+	case "exact":
+		return attrVal == value
+	case "substring":
+		return value != "" && strings.Contains(attrVal, value)
+	case "word":
+		if value == "" || strings.ContainsFunc(value, unicode.IsSpace) {
+			return false
+		}
+		for _, word := range strings.Fields(attrVal) {
+			if word == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // GetElementsByAttribs gets list of elements with exact matching attributes
 func GetElementsByAttribs(node *goquery.Selection, attribs map[string]string, attribsMatch string) []*goquery.Selection {
 	return GetTags(node, "", attribs, attribsMatch, false)
@@ -149,15 +160,12 @@ func GetMetatags(node *goquery.Selection, value string) []*goquery.Selection {
 
 	var results []*goquery.Selection
 
-	selectors := []string{
-		fmt.Sprintf("meta[name='%s']", value),
-		fmt.Sprintf("meta[property='%s']", value),
-		fmt.Sprintf("meta[itemprop='%s']", value),
-	}
-
-	for _, selector := range selectors {
-		node.Find(selector).Each(func(i int, s *goquery.Selection) {
-			results = append(results, s)
+	metas := node.Find("meta")
+	for _, attr := range []string{"name", "property", "itemprop"} {
+		metas.Each(func(i int, s *goquery.Selection) {
+			if attrVal, exists := s.Attr(attr); exists && attrVal == value {
+				results = append(results, s)
+			}
 		})
 	}
 
@@ -230,14 +238,110 @@ func GetText(node *goquery.Selection) string {
 	return InnerTrim(text)
 }
 
-// InnerTrim trims whitespace and collapses multiple spaces
+// UnescapeText decodes the HTML entities left in a text value, including the
+// double-encoded ones such as "&amp;#8217;" found in meta tags and the ones of
+// JSON-LD strings. It is meant for text, not URLs, in which query parameters
+// such as "&copy=" would be decoded.
+func UnescapeText(text string) string {
+	for range 3 {
+		unescaped := html.UnescapeString(text)
+		if unescaped == text {
+			break
+		}
+		text = unescaped
+	}
+	return text
+}
+
+// InnerTrim trims whitespace, collapses multiple spaces and drops control
+// and zero-width characters
 func InnerTrim(text string) string {
+	text = StripControlChars(text)
 	// Replace multiple whitespace with single space
 	re := regexp.MustCompile(`\s+`)
 	text = re.ReplaceAllString(text, " ")
 	return strings.TrimSpace(text)
 }
 
+// StripControlChars removes control and invisible format characters such as
+// zero-width spaces and byte order marks. Whitespace is kept, as are the
+// zero-width (non-)joiners that some scripts need to render correctly.
+func StripControlChars(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return r
+		case r == '\u200c' || r == '\u200d':
+			return r
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// sentenceEndRe matches the end of a sentence and the spaces following it
+var sentenceEndRe = regexp.MustCompile(`[.!?…]+["'”’)\]]*\s+`)
+
+// DedupeConsecutiveSentences removes the sentences of text repeating the
+// sentence right before them exactly, as left by overlapping blocks. Spaces
+// between sentences are collapsed.
+func DedupeConsecutiveSentences(text string) string {
+	var kept []string
+	previous := ""
+	start := 0
+	add := func(sentence string) {
+		sentence = strings.TrimSpace(sentence)
+		if sentence == "" || sentence == previous {
+			return
+		}
+		kept = append(kept, sentence)
+		previous = sentence
+	}
+	for _, loc := range sentenceEndRe.FindAllStringIndex(text, -1) {
+		add(text[start:loc[1]])
+		start = loc[1]
+	}
+	add(text[start:])
+	return strings.Join(kept, " ")
+}
+
+// StripEmoji removes emoji, their modifiers and the joiners that glue emoji
+// sequences together, then collapses the leftover whitespace
+func StripEmoji(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	b.Grow(len(text))
+	for i, r := range runes {
+		if isEmoji(r) {
+			continue
+		}
+		if r == '\u200d' && ((i > 0 && isEmoji(runes[i-1])) || (i+1 < len(runes) && isEmoji(runes[i+1]))) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return InnerTrim(b.String())
+}
+
+// isEmoji reports whether r belongs to one of the emoji blocks or is an
+// emoji presentation modifier
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, transport, flags, skin tones
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // miscellaneous symbols and dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // arrows and stars such as ⭐
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // tag sequences of subdivision flags
+		return true
+	case r == 0xFE0F || r == 0x20E3: // emoji presentation selector, keycap
+		return true
+	}
+	return false
+}
+
 // GetAttribute gets the unicode attribute of the node
 func GetAttribute(node *goquery.Selection, attr string, type_ any, default_ any) any {
 	attrVal, exists := node.Attr(attr)
@@ -306,14 +410,43 @@ func OuterHTML(node *goquery.Selection) string {
 	}
 
 	return buf.String()
-} // GetLdJsonObject gets the JSON-LD object from the node
+}
+
+// GetLdJsonObject gets the JSON-LD object from the node
 func GetLdJsonObject(node *goquery.Selection) []map[string]any {
-	var results []map[string]any
+	return GetLdJsonObjectWithLimit(node, 0)
+}
+
+// GetLdJsonObjectWithLimit is like GetLdJsonObject but skips the JSON-LD
+// blocks larger than maxBytes without decoding them. There is no limit when
+// maxBytes is 0 or less.
+func GetLdJsonObjectWithLimit(node *goquery.Selection, maxBytes int) []map[string]any {
+	return ParseLdJson(node, maxBytes).Objects
+}
 
+// LdJson holds the JSON-LD blocks of a document, decoded once for all the
+// extractors reading them
+type LdJson struct {
+	Raw     []string         // Text of every block, in document order, whether it decodes or not
+	Objects []map[string]any // Objects of the blocks within the size limit, top-level arrays flattened
+	Graph   []map[string]any // Objects followed by the members of their @graph, nested graphs included
+	Members []map[string]any // Members of the @graph of the objects, nested graphs included
+	Errors  []error          // Decoding errors of the malformed blocks within the size limit
+}
+
+// ParseLdJson decodes the JSON-LD blocks of node, skipping the ones larger
+// than maxBytes. There is no limit when maxBytes is 0 or less.
+func ParseLdJson(node *goquery.Selection, maxBytes int) *LdJson {
+	ld := &LdJson{}
 	node.Find("script[type='application/ld+json']").Each(func(i int, s *goquery.Selection) {
 		jsonStr := s.Text()
+		ld.Raw = append(ld.Raw, jsonStr)
+		if maxBytes > 0 && len(jsonStr) > maxBytes {
+			return
+		}
 		var jsonData any
 		if err := json.Unmarshal([]byte(jsonStr), &jsonData); err != nil {
+			ld.Errors = append(ld.Errors, err)
 			return
 		}
 
@@ -321,15 +454,64 @@ func GetLdJsonObject(node *goquery.Selection) []map[string]any {
 		case []any:
 			for _, item := range v {
 				if obj, ok := item.(map[string]any); ok {
-					results = append(results, obj)
+					ld.Objects = append(ld.Objects, obj)
 				}
 			}
 		case map[string]any:
-			results = append(results, v)
+			ld.Objects = append(ld.Objects, v)
 		}
 	})
 
-	return results
+	var addGraph func(obj map[string]any, member bool)
+	addGraph = func(obj map[string]any, member bool) {
+		ld.Graph = append(ld.Graph, obj)
+		if member {
+			ld.Members = append(ld.Members, obj)
+		}
+		graph, _ := obj["@graph"].([]any)
+		for _, item := range graph {
+			if child, ok := item.(map[string]any); ok {
+				addGraph(child, true)
+			}
+		}
+	}
+	for _, obj := range ld.Objects {
+		addGraph(obj, false)
+	}
+	return ld
+}
+
+// robotsValueDirectives are the robots directives written "name: value"
+var robotsValueDirectives = map[string]bool{
+	"max-snippet":       true,
+	"max-image-preview": true,
+	"max-video-preview": true,
+	"unavailable_after": true,
+}
+
+// ParseRobotsDirectives splits robots meta tag contents or X-Robots-Tag
+// header values into lowercase, deduplicated directives. Values scoped to a
+// crawler other than googlebot, e.g. "bingbot: noindex", are ignored.
+func ParseRobotsDirectives(values ...string) []string {
+	var directives []string
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if agent, rest, ok := strings.Cut(value, ":"); ok && !strings.Contains(agent, ",") {
+			agent = strings.TrimSpace(agent)
+			if !robotsValueDirectives[agent] {
+				if agent != "googlebot" {
+					continue
+				}
+				value = rest
+			}
+		}
+		for _, directive := range strings.Split(value, ",") {
+			if directive = strings.TrimSpace(directive); directive != "" && !slices.Contains(directives, directive) {
+				directives = append(directives, directive)
+			}
+		}
+	}
+	return directives
 }
 
 // GetNodeDepth gets the depth of the node (how deep its children are)
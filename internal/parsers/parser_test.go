@@ -1,10 +1,14 @@
 package parsers
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
 )
 
 func TestDropTags(t *testing.T) {
@@ -33,6 +37,64 @@ func TestGetUnicodeHTML(t *testing.T) {
 	}
 }
 
+func TestGetUnicodeHTMLUTF16(t *testing.T) {
+	input := "<html><head><title>Hello, 世界</title></head><body><p>News</p></body></html>"
+	cases := []struct {
+		name       string
+		endianness unicode.Endianness
+		bom        unicode.BOMPolicy
+	}{
+		{"LE with BOM", unicode.LittleEndian, unicode.UseBOM},
+		{"BE with BOM", unicode.BigEndian, unicode.UseBOM},
+		{"LE without BOM", unicode.LittleEndian, unicode.IgnoreBOM},
+		{"BE without BOM", unicode.BigEndian, unicode.IgnoreBOM},
+	}
+	for _, c := range cases {
+		encoded, err := unicode.UTF16(c.endianness, c.bom).NewEncoder().String(input)
+		if err != nil {
+			t.Fatalf("%s: failed to encode: %v", c.name, err)
+		}
+		if output := GetUnicodeHTML(encoded); output != input {
+			t.Errorf("%s: expected %q, got %q", c.name, input, output)
+		}
+	}
+
+	if output := GetUnicodeHTML("<p>a\x00b</p>"); output != "<p>ab</p>" {
+		t.Errorf("Expected NUL bytes to be stripped, got %q", output)
+	}
+	if output := GetUnicodeHTML("\xef\xbb\xbf<p>x</p>"); output != "<p>x</p>" {
+		t.Errorf("Expected UTF-8 BOM to be stripped, got %q", output)
+	}
+}
+
+func TestGetUnicodeHTMLCharsets(t *testing.T) {
+	latin1, err := charmap.ISO8859_1.NewEncoder().String("<p>Le journal a publié l'été dernier</p>")
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	shiftJIS, err := japanese.ShiftJIS.NewEncoder().String("<p>港の新しい橋</p>")
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	cases := []struct {
+		name        string
+		content     string
+		contentType string
+		expected    string
+	}{
+		{"header", shiftJIS, "text/html; charset=Shift_JIS", "港の新しい橋"},
+		{"meta charset", `<meta charset="shift_jis">` + shiftJIS, "", "港の新しい橋"},
+		{"http-equiv", `<meta http-equiv="Content-Type" content="text/html; charset=iso-8859-1">` + latin1, "", "publié l'été"},
+		{"undeclared", latin1, "text/html", "publié l'été"},
+		{"mislabeled UTF-8", "<p>publié l'été</p>", "text/html; charset=iso-8859-1", "<p>publié l'été</p>"},
+	}
+	for _, c := range cases {
+		if output := GetUnicodeHTMLWithContentType(c.content, c.contentType); !strings.Contains(output, c.expected) {
+			t.Errorf("%s: expected %q in %q", c.name, c.expected, output)
+		}
+	}
+}
+
 func TestFromString(t *testing.T) {
 	html := `<html><body><h1>Title</h1></body></html>`
 	doc, err := FromString(html)
@@ -75,6 +137,27 @@ func TestGetTags(t *testing.T) {
 	}
 }
 
+func TestGetTagsUnsafeValues(t *testing.T) {
+	html := "<div data-name=\"O'Brien ']\" class=\"story\nbody\">text</div><meta property=\"og:title\" content='He said \"hi\"'>"
+	doc, _ := FromString(html)
+
+	if results := GetTags(doc.Selection, "div", map[string]string{"data-name": "O'Brien ']"}, "exact", false); len(results) != 1 {
+		t.Errorf("Expected quote-containing value to match, got %d results", len(results))
+	}
+	if results := GetTags(doc.Selection, "div", map[string]string{"class": "body"}, "word", false); len(results) != 1 {
+		t.Errorf("Expected newline-separated class to match as a word, got %d results", len(results))
+	}
+	if results := GetTags(doc.Selection, "div", map[string]string{"class": "story\nbody"}, "word", false); len(results) != 0 {
+		t.Errorf("Expected a value with whitespace to never match as a word, got %d results", len(results))
+	}
+	if results := GetTags(doc.Selection, "", map[string]string{"data-name": "Brien"}, "substring", false); len(results) != 1 {
+		t.Errorf("Expected substring match, got %d results", len(results))
+	}
+	if results := GetMetatags(doc.Selection, "og:title'] , *[x='"); len(results) != 0 {
+		t.Errorf("Expected a selector-like value to match nothing, got %d results", len(results))
+	}
+}
+
 func TestGetElementsByAttribs(t *testing.T) {
 	html := `<div><a href="http://example.com">link</a></div>`
 	doc, _ := FromString(html)
@@ -144,6 +227,74 @@ func TestInnerTrim(t *testing.T) {
 	}
 }
 
+func TestInnerTrimControlChars(t *testing.T) {
+	input := "Breaking\u200b news \U0001F525\ufeff from\x00 the\u00ad city"
+	expected := "Breaking news \U0001F525 from the city"
+	if result := InnerTrim(input); result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	// Zero-width non-joiners carry meaning in Persian and Indic scripts
+	persian := "می\u200cخواهم"
+	if result := InnerTrim(persian); result != persian {
+		t.Errorf("Expected ZWNJ to be kept, got %q", result)
+	}
+}
+
+func TestUnescapeText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Jane &amp; John", "Jane & John"},
+		{"It&amp;#8217;s a caf&eacute;", "It’s a café"},
+		{"&amp;amp;amp;", "&"},
+		{"AT&T", "AT&T"},
+	}
+	for _, tt := range tests {
+		if result := UnescapeText(tt.input); result != tt.expected {
+			t.Errorf("UnescapeText(%q): expected %q, got %q", tt.input, tt.expected, result)
+		}
+	}
+}
+
+func TestStripEmoji(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Fire \U0001F525 in the hills", "Fire in the hills"},
+		{"Family \U0001F468\u200d\U0001F469\u200d\U0001F467 day", "Family day"},
+		{"Thumbs \U0001F44D\U0001F3FD up", "Thumbs up"},
+		{"Sunny \u2600\ufe0f today", "Sunny today"},
+		{"Flag \U0001F1EB\U0001F1F7!", "Flag !"},
+		{"Caf\u00e9 \u2014 \u00a9 2024 \u20ac5", "Caf\u00e9 \u2014 \u00a9 2024 \u20ac5"},
+	}
+	for _, tt := range tests {
+		if result := StripEmoji(tt.input); result != tt.expected {
+			t.Errorf("StripEmoji(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestDedupeConsecutiveSentences(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"The storm hit. The storm hit. Boats returned.", "The storm hit. Boats returned."},
+		{"Is it over? Is it over? \"Yes.\" \"Yes.\" No doubt", "Is it over? \"Yes.\" No doubt"},
+		{"The storm hit. Boats returned. The storm hit.", "The storm hit. Boats returned. The storm hit."},
+		{"The storm hit The storm hit", "The storm hit The storm hit"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if result := DedupeConsecutiveSentences(tt.input); result != tt.expected {
+			t.Errorf("DedupeConsecutiveSentences(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
 func TestGetAttribute(t *testing.T) {
 	html := `<a href="http://example.com">link</a>`
 	doc, _ := FromString(html)
@@ -187,6 +338,52 @@ func TestGetLdJsonObject(t *testing.T) {
 	}
 }
 
+func TestGetLdJsonObjectWithLimit(t *testing.T) {
+	big := `{"@type": "ItemList", "itemListElement": [` + strings.Repeat(`{"@type": "Product", "name": "item"},`, 100) + `{}]}`
+	html := `<script type="application/ld+json">` + big + `</script><script type="application/ld+json">{"name": "test"}</script>`
+	doc, _ := FromString(html)
+
+	if results := GetLdJsonObjectWithLimit(doc.Selection, 1024); len(results) != 1 || results[0]["name"] != "test" {
+		t.Errorf("Expected only the small block, got %v", results)
+	}
+	if results := GetLdJsonObjectWithLimit(doc.Selection, 0); len(results) != 2 {
+		t.Errorf("Expected both blocks without a limit, got %d", len(results))
+	}
+}
+
+func TestParseLdJson(t *testing.T) {
+	html := `<script type="application/ld+json">{"name": "page", "@graph": [{"name": "article", "@graph": [{"name": "nested"}]}, "ignored"]}</script>` +
+		`<script type="application/ld+json">[{"name": "first"}, {"name": "second"}]</script>` +
+		`<script type="application/ld+json">{"name": "broken",</script>` +
+		`<script type="application/ld+json">{"name": "` + strings.Repeat("x", 200) + `"}</script>`
+	doc, _ := FromString(html)
+	ld := ParseLdJson(doc.Selection, 150)
+
+	names := func(objects []map[string]any) []any {
+		var names []any
+		for _, obj := range objects {
+			names = append(names, obj["name"])
+		}
+		return names
+	}
+	if want := []any{"page", "first", "second"}; !slices.Equal(names(ld.Objects), want) {
+		t.Errorf("Expected the objects %v, got %v", want, names(ld.Objects))
+	}
+	if want := []any{"page", "article", "nested", "first", "second"}; !slices.Equal(names(ld.Graph), want) {
+		t.Errorf("Expected the graph %v, got %v", want, names(ld.Graph))
+	}
+	if want := []any{"article", "nested"}; !slices.Equal(names(ld.Members), want) {
+		t.Errorf("Expected the graph members %v, got %v", want, names(ld.Members))
+	}
+	if len(ld.Raw) != 4 {
+		t.Errorf("Expected the text of the 4 blocks, got %d", len(ld.Raw))
+	}
+	// The oversized block is skipped, not reported as malformed
+	if len(ld.Errors) != 1 {
+		t.Errorf("Expected 1 decoding error, got %v", ld.Errors)
+	}
+}
+
 func TestGetNodeDepth(t *testing.T) {
 	html := `<div><p><span>text</span></p></div>`
 	doc, _ := FromString(html)
@@ -252,3 +449,14 @@ func TestMin(t *testing.T) {
 		t.Error("min(2, 1) should be 1")
 	}
 }
+
+func TestParseRobotsDirectives(t *testing.T) {
+	got := ParseRobotsDirectives("NoIndex, NOFOLLOW", "googlebot: noarchive", "otherbot: nosnippet", "unavailable_after: 2025-06-01", "noindex")
+	expected := []string{"noindex", "nofollow", "noarchive", "unavailable_after: 2025-06-01"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if got := ParseRobotsDirectives("", " "); len(got) != 0 {
+		t.Errorf("Expected no directives, got %v", got)
+	}
+}
@@ -0,0 +1,26 @@
+package urls
+
+import "testing"
+
+func FuzzParse(f *testing.F) {
+	f.Add("https://www.example.co.uk/news/2025/01/06/storm.html?utm_source=x#top")
+	f.Add("http://[2001:db8::1]:8080/a/b.")
+	f.Add("http://newsroom/article/123")
+	f.Fuzz(func(t *testing.T, rawURL string) {
+		u, err := Parse(rawURL)
+		if err != nil {
+			return
+		}
+		_ = u.GetPathChunks()
+		_ = u.IsValidNewsArticleURL()
+		_ = u.Copy()
+	})
+}
+
+func FuzzPrepareURL(f *testing.F) {
+	f.Add("/news/storm.html?utm_source=x", "https://www.example.com/")
+	f.Add("//cdn.example.com/a.jpg", "https://example.com")
+	f.Fuzz(func(t *testing.T, rawURL, sourceURL string) {
+		_ = PrepareURL(rawURL, sourceURL)
+	})
+}
@@ -2,6 +2,7 @@ package urls
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"regexp"
 	"slices"
@@ -58,6 +59,12 @@ type URL struct {
 	Port      string
 	ICANN     bool
 	FileType  string
+	// IsIP is set for IPv4 and IPv6 hosts, whose Domain is the address
+	IsIP bool
+	// TLDGuessed is set when the suffix of the host is not in the public
+	// suffix list, e.g. a gTLD newer than the bundled list, and TLD is its
+	// last label
+	TLDGuessed bool
 	*url.URL
 }
 
@@ -71,12 +78,15 @@ func (u *URL) Copy() *URL {
 	}
 	copiedURL := *u.URL
 	return &URL{
-		Domain:    u.Domain,
-		Subdomain: u.Subdomain,
-		TLD:       u.TLD,
-		Port:      u.Port,
-		ICANN:     u.ICANN,
-		URL:       &copiedURL,
+		Domain:     u.Domain,
+		Subdomain:  u.Subdomain,
+		TLD:        u.TLD,
+		Port:       u.Port,
+		ICANN:      u.ICANN,
+		FileType:   u.FileType,
+		IsIP:       u.IsIP,
+		TLDGuessed: u.TLDGuessed,
+		URL:        &copiedURL,
 	}
 }
 
@@ -159,12 +169,33 @@ func cleanURL(urlStr string) string {
 
 	// Clean up the path by replacing multiple slashes with a single slash
 	cleanedPath := strings.ReplaceAll(parsedURL.Path, "//", "/")
+
+	// /foo/ and /foo are the same page: keep a single form, except for the root
+	if len(cleanedPath) > 1 {
+		cleanedPath = strings.TrimRight(cleanedPath, "/")
+	}
 	parsedURL.Path = cleanedPath
 	urlStr = parsedURL.String()
 
 	return urlStr
 }
 
+// StripQuery removes the query parameters of urlStr except those named in keep
+func StripQuery(urlStr string, keep []string) string {
+	parsedURL, err := url.Parse(urlStr)
+	if err != nil || parsedURL.RawQuery == "" {
+		return urlStr
+	}
+	queryValues := parsedURL.Query()
+	for name := range queryValues {
+		if !slices.Contains(keep, name) {
+			delete(queryValues, name)
+		}
+	}
+	parsedURL.RawQuery = queryValues.Encode()
+	return parsedURL.String()
+}
+
 // JoinURL joins a base URL with a relative URL
 func JoinURL(baseURL, relativeURL string) string {
 	base, err := url.Parse(baseURL)
@@ -359,7 +390,17 @@ func Parse(s string) (*URL, error) {
 	}
 
 	// extract domain, subdomain, tld, ...
-	dom, port := domainPort(url.Host)
+	dom, port := url.Hostname(), url.Port()
+	fileType := extractFileTypeFromPath(url.Path)
+
+	// IP hosts and single-label intranet hosts have no public suffix
+	if ip := net.ParseIP(dom); ip != nil {
+		return &URL{Domain: dom, Port: port, IsIP: true, URL: url, FileType: fileType}, nil
+	}
+	if !strings.Contains(strings.Trim(dom, "."), ".") {
+		return &URL{Domain: strings.Trim(dom, "."), Port: port, URL: url, FileType: fileType}, nil
+	}
+
 	// etld+1
 	etld1, err := publicsuffix.EffectiveTLDPlusOne(dom)
 	suffix, icann := publicsuffix.PublicSuffix(strings.ToLower(dom))
@@ -386,34 +427,22 @@ func Parse(s string) (*URL, error) {
 		sub = rest
 	}
 
-	// extract file type
-	fileType := extractFileTypeFromPath(url.Path)
+	// Unlisted suffixes fall back to the last label (the "*" rule), while
+	// the private suffixes of the list, such as github.io, have several
+	guessed := !icann && !strings.Contains(suffix, ".")
 
 	return &URL{
-		Subdomain: sub,
-		Domain:    domName,
-		TLD:       tld,
-		Port:      port,
-		ICANN:     icann,
-		URL:       url,
-		FileType:  fileType,
+		Subdomain:  sub,
+		Domain:     domName,
+		TLD:        tld,
+		Port:       port,
+		ICANN:      icann,
+		TLDGuessed: guessed,
+		URL:        url,
+		FileType:   fileType,
 	}, nil
 }
 
-// domainPort splits host:port into domain and port
-func domainPort(host string) (string, string) {
-	for i := len(host) - 1; i >= 0; i-- {
-		if host[i] == ':' {
-			return host[:i], host[i+1:]
-		} else if host[i] < '0' || host[i] > '9' {
-			return host, ""
-		}
-	}
-	//will only land here if the string is all digits,
-	//net/url should prevent that from happening
-	return host, ""
-}
-
 // URLToFileType extracts the file type from a URL
 func extractFileTypeFromPath(path string) string {
 
@@ -175,6 +175,47 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseHostsWithoutPublicSuffix(t *testing.T) {
+	tests := []struct {
+		name           string
+		urlStr         string
+		wantDomain     string
+		wantSubdomain  string
+		wantTLD        string
+		wantPort       string
+		wantIP         bool
+		wantTLDGuessed bool
+	}{
+		{name: "IPv4 host", urlStr: "http://192.168.1.20:8080/news/2025/storm.html", wantDomain: "192.168.1.20", wantPort: "8080", wantIP: true},
+		{name: "IPv6 host", urlStr: "http://[2001:db8::1]/news/storm.html", wantDomain: "2001:db8::1", wantIP: true},
+		{name: "intranet single label", urlStr: "http://newsroom/article/123", wantDomain: "newsroom"},
+		{name: "unknown gTLD", urlStr: "https://www.daily.newsfictionaltld/world/storm.html", wantDomain: "daily", wantSubdomain: "www", wantTLD: "newsfictionaltld", wantTLDGuessed: true},
+		{name: "listed TLD", urlStr: "https://www.bbc.co.uk/news", wantDomain: "bbc", wantSubdomain: "www", wantTLD: "co.uk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.urlStr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.Domain != tt.wantDomain || got.Subdomain != tt.wantSubdomain || got.TLD != tt.wantTLD || got.Port != tt.wantPort {
+				t.Errorf("Parse() = domain %q, subdomain %q, TLD %q, port %q, want %q, %q, %q, %q",
+					got.Domain, got.Subdomain, got.TLD, got.Port, tt.wantDomain, tt.wantSubdomain, tt.wantTLD, tt.wantPort)
+			}
+			if got.IsIP != tt.wantIP || got.TLDGuessed != tt.wantTLDGuessed {
+				t.Errorf("Parse() IsIP = %v, TLDGuessed = %v, want %v, %v", got.IsIP, got.TLDGuessed, tt.wantIP, tt.wantTLDGuessed)
+			}
+
+			// Other pages of the same host share the domain and TLD
+			other, err := Parse(got.Scheme + "://" + got.Host + "/other/page")
+			if err != nil || other.Domain != got.Domain || other.TLD != got.TLD {
+				t.Errorf("Expected another page of %s on the same domain, got %+v (%v)", got.Host, other, err)
+			}
+		})
+	}
+}
+
 func TestURL_IsValidNewsArticleURL(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -473,6 +514,21 @@ func TestCleanURL(t *testing.T) {
 			input: "http://example.com:8080/path?utm_source=test&other=param",
 			want:  "http://example.com:8080/path?other=param",
 		},
+		{
+			name:  "URL with trailing slash",
+			input: "https://example.com/news/article/",
+			want:  "https://example.com/news/article",
+		},
+		{
+			name:  "URL with trailing slash and query",
+			input: "https://example.com/news/article/?id=3#top",
+			want:  "https://example.com/news/article?id=3",
+		},
+		{
+			name:  "Root URL keeps its slash",
+			input: "https://example.com/",
+			want:  "https://example.com/",
+		},
 	}
 
 	for _, tt := range tests {
@@ -484,3 +540,20 @@ func TestCleanURL(t *testing.T) {
 		})
 	}
 }
+
+func TestStripQuery(t *testing.T) {
+	tests := []struct {
+		input    string
+		keep     []string
+		expected string
+	}{
+		{"https://example.com/story.html?sess=abc&ref=homepage", nil, "https://example.com/story.html"},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ&sess=abc", []string{"v"}, "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"https://example.com/story.html", []string{"v"}, "https://example.com/story.html"},
+	}
+	for _, tt := range tests {
+		if got := StripQuery(tt.input, tt.keep); got != tt.expected {
+			t.Errorf("StripQuery(%q, %v) = %q, expected %q", tt.input, tt.keep, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package warc implements a minimal reader for WARC archives.
+// Only response records are returned; other record types are skipped.
+package warc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Record is an HTTP response stored in a WARC archive
+type Record struct {
+	TargetURI   string // Original URL of the response (WARC-Target-URI)
+	StatusCode  int    // HTTP status code of the archived response
+	ContentType string // Content-Type header of the archived response
+	Body        []byte // Decoded HTTP response body
+}
+
+// DefaultMaxRecordLength is the default Reader.MaxRecordLength: 64 MiB
+const DefaultMaxRecordLength = 64 << 20
+
+// ErrRecordTooLarge is returned by Reader.Next for a response record longer
+// than Reader.MaxRecordLength. The record is skipped and reading can go on.
+var ErrRecordTooLarge = errors.New("WARC record too large")
+
+// Reader reads response records from a WARC stream
+type Reader struct {
+	// MaxRecordLength bounds the length of the records read in memory. The
+	// longer records are skipped without being read, whatever their
+	// Content-Length, so that a broken or hostile archive cannot exhaust
+	// the memory.
+	MaxRecordLength int64
+
+	r *bufio.Reader
+}
+
+// NewReader creates a Reader from r. Gzip compressed archives (.warc.gz) are detected automatically.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		br = bufio.NewReader(gz)
+	}
+	return &Reader{MaxRecordLength: DefaultMaxRecordLength, r: br}, nil
+}
+
+// Next returns the next response record, or io.EOF when the archive is exhausted
+func (wr *Reader) Next() (*Record, error) {
+	for {
+		headers, err := wr.readHeaders()
+		if err != nil {
+			return nil, err
+		}
+
+		length, err := strconv.ParseInt(headers["content-length"], 10, 64)
+		if err != nil || length < 0 {
+			return nil, fmt.Errorf("invalid WARC Content-Length %q", headers["content-length"])
+		}
+		if !strings.EqualFold(headers["warc-type"], "response") {
+			if err := wr.skip(length); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if ct := strings.ToLower(headers["content-type"]); ct != "" && !strings.HasPrefix(ct, "application/http") {
+			if err := wr.skip(length); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if length > wr.MaxRecordLength {
+			if err := wr.skip(length); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %d bytes in WARC record %s", ErrRecordTooLarge, length, headers["warc-target-uri"])
+		}
+
+		// The block grows with the data actually read, not with the
+		// announced length
+		block, err := io.ReadAll(io.LimitReader(wr.r, length))
+		if err != nil {
+			return nil, fmt.Errorf("truncated WARC record: %w", err)
+		}
+		if int64(len(block)) < length {
+			return nil, fmt.Errorf("truncated WARC record: %w", io.ErrUnexpectedEOF)
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(block)), nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HTTP response in WARC record %s: %w", headers["warc-target-uri"], err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read HTTP body in WARC record %s: %w", headers["warc-target-uri"], err)
+		}
+
+		return &Record{
+			TargetURI:   strings.Trim(headers["warc-target-uri"], "<>"),
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        body,
+		}, nil
+	}
+}
+
+// skip discards the next length bytes of the stream
+func (wr *Reader) skip(length int64) error {
+	if _, err := io.CopyN(io.Discard, wr.r, length); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("truncated WARC record: %w", err)
+	}
+	return nil
+}
+
+// readHeaders reads the version line and the named fields of the next record
+func (wr *Reader) readHeaders() (map[string]string, error) {
+	// skip the blank lines separating records
+	var line string
+	for {
+		l, err := wr.r.ReadString('\n')
+		if err != nil && (err != io.EOF || strings.TrimSpace(l) == "") {
+			return nil, err
+		}
+		line = strings.TrimSpace(l)
+		if line != "" {
+			break
+		}
+	}
+	if !strings.HasPrefix(line, "WARC/") {
+		return nil, fmt.Errorf("invalid WARC record header %q", line)
+	}
+
+	headers := map[string]string{}
+	for {
+		l, err := wr.r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("truncated WARC header: %w", err)
+		}
+		l = strings.TrimSpace(l)
+		if l == "" {
+			return headers, nil
+		}
+		name, value, ok := strings.Cut(l, ":")
+		if !ok {
+			continue
+		}
+		headers[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+}
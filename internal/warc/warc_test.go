@@ -0,0 +1,86 @@
+package warc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func warcRecord(warcType, uri, block string) string {
+	return fmt.Sprintf("WARC/1.0\r\nWARC-Type: %s\r\nWARC-Target-URI: <%s>\r\nContent-Type: application/http\r\nContent-Length: %d\r\n\r\n%s\r\n\r\n", warcType, uri, len(block), block)
+}
+
+func TestReaderResponsesOnly(t *testing.T) {
+	archive := warcRecord("request", "https://example.com/a", "GET /a HTTP/1.1\r\nHost: example.com\r\n\r\n") +
+		warcRecord("response", "https://example.com/a", "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<p>a</p>") +
+		warcRecord("metadata", "https://example.com/a", "fetchTimeMs: 12") +
+		warcRecord("response", "https://example.com/b", "HTTP/1.1 404 Not Found\r\nContent-Type: text/html\r\n\r\n<p>b</p>")
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, _ = w.Write([]byte(archive))
+	_ = w.Close()
+
+	for name, data := range map[string][]byte{"plain": []byte(archive), "gzip": gz.Bytes()} {
+		reader, err := NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: NewReader failed: %v", name, err)
+		}
+
+		var records []*Record
+		for {
+			record, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: Next failed: %v", name, err)
+			}
+			records = append(records, record)
+		}
+
+		if len(records) != 2 {
+			t.Fatalf("%s: expected 2 response records, got %d", name, len(records))
+		}
+		if records[0].TargetURI != "https://example.com/a" || string(records[0].Body) != "<p>a</p>" || records[0].ContentType != "text/html" {
+			t.Errorf("%s: unexpected first record %+v", name, records[0])
+		}
+		if records[1].StatusCode != 404 {
+			t.Errorf("%s: expected status 404, got %d", name, records[1].StatusCode)
+		}
+	}
+}
+
+func TestReaderRecordLength(t *testing.T) {
+	large := warcRecord("response", "https://example.com/large", "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<p>"+strings.Repeat("large ", 100)+"</p>")
+	small := warcRecord("response", "https://example.com/small", "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\n\r\n<p>small</p>")
+
+	reader, err := NewReader(strings.NewReader(large + small))
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	reader.MaxRecordLength = 200
+	if _, err := reader.Next(); !errors.Is(err, ErrRecordTooLarge) {
+		t.Fatalf("Expected ErrRecordTooLarge, got %v", err)
+	}
+	record, err := reader.Next()
+	if err != nil || record.TargetURI != "https://example.com/small" {
+		t.Fatalf("Expected the record following the skipped one, got %+v, %v", record, err)
+	}
+
+	// A hostile length is neither allocated nor waited for
+	for _, length := range []string{"1099511627776", "1000"} {
+		hostile := "WARC/1.0\r\nWARC-Type: response\r\nContent-Type: application/http\r\nContent-Length: " + length + "\r\n\r\nHTTP/1.1 200 OK\r\n\r\n"
+		reader, err := NewReader(strings.NewReader(hostile))
+		if err != nil {
+			t.Fatalf("NewReader failed: %v", err)
+		}
+		if _, err := reader.Next(); err == nil || !strings.Contains(err.Error(), "truncated") {
+			t.Errorf("Content-Length %s: expected a truncated record error, got %v", length, err)
+		}
+	}
+}
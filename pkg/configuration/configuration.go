@@ -3,6 +3,10 @@ package configuration
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	newspaper4kgo "github.com/tguidoux/newspaper4k-go"
 )
@@ -12,6 +16,16 @@ type DownloadOptions struct {
 	InputHTML string // If provided, use this HTML instead of downloading
 }
 
+// NoStopwordsMode selects how NLP handles languages without a stop word list
+type NoStopwordsMode int
+
+const (
+	// NoStopwordsSkip leaves keywords and summary empty
+	NoStopwordsSkip NoStopwordsMode = iota
+	// NoStopwordsFrequency ranks a reduced number of keywords by frequency alone and leaves the summary empty
+	NoStopwordsFrequency
+)
+
 // Configuration holds settings for Article/Source objects.
 type Configuration struct {
 	MinWordCount         int
@@ -42,6 +56,123 @@ type Configuration struct {
 	UseCachedCategories  bool
 	DownloadOptions      DownloadOptions
 	MaxFeeds             int
+	// VideoProviderAllowlist restricts embedded players to these providers when not empty
+	VideoProviderAllowlist []string
+	// VideoProviderDenylist drops embedded players from these providers
+	VideoProviderDenylist []string
+	// LangDetectMinConfidence is the minimum confidence required to use a detected
+	// language for NLP; below it the configured language or English is used instead.
+	// 0 (the default) uses the detected language whatever its confidence.
+	LangDetectMinConfidence float64
+	// MetaDataAllowlist restricts Article.MetaData to the meta tags with these names or
+	// properties, compared case-insensitively. Every meta tag is stored when empty.
+	MetaDataAllowlist []string
+	// LanguagePriority orders the language signals of a page: "article" (lang attribute of
+	// <article> or <main>), "html", "og" (og:locale), "meta" (other meta tags), "jsonld"
+	// (inLanguage) and "detect" (detection from the text). Signals after "detect" are only
+	// used when detection fails, unlisted signals are ignored. Defaults to constants.LANGUAGE_PRIORITY.
+	LanguagePriority []string
+	// RecentDownloadCacheSize is the number of recently downloaded pages reused across
+	// articles and sources, 0 disables the cache (concurrent downloads are always coalesced)
+	RecentDownloadCacheSize int
+	// RecentDownloadCacheTTLSeconds is how long a recently downloaded page can be reused
+	RecentDownloadCacheTTLSeconds int
+	// MaxTextLength caps the text used for NLP (in bytes), 0 disables the cap
+	MaxTextLength int
+	// MaxTextHeadRatio is the share of MaxTextLength taken from the start of the text, the rest comes from its end
+	MaxTextHeadRatio float64
+	// TruncateText also cuts Article.Text to MaxTextLength instead of only the NLP input
+	TruncateText bool
+	// ExtractTables stores the data tables of the article body in Article.Tables
+	ExtractTables bool
+	// ResolveOEmbed fetches the oEmbed endpoint of recognized video providers to fill Article.Videos
+	ResolveOEmbed bool
+	// OEmbedEndpoints overrides the oEmbed endpoint per provider (see constants.OEMBED_ENDPOINTS)
+	OEmbedEndpoints map[string]string
+	// StripEmoji removes emoji from Article.Text (control and zero-width characters are always removed)
+	StripEmoji bool
+	// NormalizeTitle cleans up the extracted title: whitespace, label prefixes, trailing ellipses and ALL-CAPS
+	NormalizeTitle bool
+	// TitleLabelPrefixes overrides the leading labels removed by NormalizeTitle (see constants.TITLE_LABEL_PREFIXES)
+	TitleLabelPrefixes []string
+	// MaxJSONLDBytes skips JSON-LD blocks larger than this many bytes instead of decoding them (0 disables the limit)
+	MaxJSONLDBytes int
+	// KeepImageCaptions keeps figcaption text as a caption paragraph of the article instead of removing it
+	KeepImageCaptions bool
+	// CaptionCreditPatterns overrides the patterns of credit-only captions that are always removed (see constants.CAPTION_CREDIT_PATTERNS)
+	CaptionCreditPatterns []string
+	// NoStopwordsNLP selects how NLP handles languages without stop words instead of applying English ones
+	NoStopwordsNLP NoStopwordsMode
+	// DropNoArchiveContent clears the HTML and text of articles whose publisher set noarchive, keeping only their metadata
+	DropNoArchiveContent bool
+	// NonHTMLCategoryRetries is how many times a category served with a non-HTML content type is downloaded again before being skipped
+	NonHTMLCategoryRetries int
+	// FeedMaxRedirects is the number of redirects followed when probing a feed URL
+	FeedMaxRedirects int
+	// FeedAllowCrossDomainRedirects accepts the feeds redirected to another registrable domain than the source's
+	FeedAllowCrossDomainRedirects bool
+	// SectionVocabulary overrides the path chunks recognized as sections by Article.SectionFromURL (see constants.URL_SECTIONS)
+	SectionVocabulary []string
+	// SkipBodyForNonArticles skips body extraction for pages whose og:type is website or profile
+	SkipBodyForNonArticles bool
+	// MaxImages caps Article.Images, keeping the images closest to the article body (0 disables the cap)
+	MaxImages int
+	// UpgradeInsecureImageURLs rewrites the http:// top image, meta image and favicon of https articles to https://
+	// when they are hosted on the article's site or a known image CDN (see constants.IMAGE_CDN_HOSTS)
+	UpgradeInsecureImageURLs bool
+	// ExtraDateLayouts are time layouts tried before the built-in ones when parsing dates
+	ExtraDateLayouts []string
+	// FirstParagraphMinWords is the number of words a paragraph needs to be returned by Article.FirstParagraph
+	FirstParagraphMinWords int
+	// StripEmptyBlocks removes the paragraphs and other blocks left without text or media from Article.ArticleHTML
+	StripEmptyBlocks bool
+	// SiteWideArticleCategories fills Article.Categories with the categories of the whole source found
+	// in the article page, as before, instead of the sections and tags the article is filed under
+	SiteWideArticleCategories bool
+	// AuthorNameParticles overrides the lowercase surname particles kept within author names and not
+	// counted as words when validating them (see constants.AUTHOR_NAME_PARTICLES)
+	AuthorNameParticles []string
+	// CleanDocKeepHead keeps the <head> of the page, meta tags included, in Article.GetCleanDoc for
+	// re-extraction, when false the clean document only has the cleaned body
+	CleanDocKeepHead bool
+	// SummaryWeights weigh the title overlap, keyword frequency, length and position of the sentences
+	// when summarizing, the zero value meaning the defaults
+	SummaryWeights SummaryWeights
+	// FollowOutboundCanonical replaces the teaser of an aggregator page, a body shorter than MinWordCount
+	// words, by the full story it links to: its off-domain canonical link or its single link introduced by
+	// "read more at", "originally published on"... (see constants.OUTBOUND_STORY_PATTERNS). One hop at most
+	// is followed, and not from pages with a nofollow robots directive.
+	FollowOutboundCanonical bool
+	// OutboundCanonicalDomains restricts FollowOutboundCanonical to these registrable domains when not empty
+	OutboundCanonicalDomains []string
+	// KeepRawJSONLD keeps the text of every JSON-LD block of the page in Article.RawJSONLD, malformed
+	// and oversized blocks included, to debug what the extractors skipped
+	KeepRawJSONLD bool
+	// MetaImageDefaultScheme is the scheme of protocol-relative meta images (//cdn.example.com/a.jpg) when the
+	// article URL has no http or https scheme to borrow, e.g. for HTML-only input
+	MetaImageDefaultScheme string
+	// DedupeImages keeps a single entry in Article.Images for the variants of an image differing only by their
+	// size query parameters (?w=300, ?w=800, see constants.IMAGE_SIZE_QUERY_PARAMS), the largest declared one.
+	// Off by default.
+	DedupeImages bool
+	// DedupeSentences removes from Article.Text the sentences repeating the sentence right before them, as
+	// left by overlapping blocks merged into the article body
+	DedupeSentences bool
+	// MaxSummaryChars caps the length of Article.Summary in characters, dropping the last selected sentences
+	// so that it ends on a sentence boundary, 0 for no cap
+	MaxSummaryChars int
+	// HTTPClient sends the requests of articles, sources and extractors instead of a client built from
+	// RequestsParams, e.g. to route them through a caching proxy. Its timeout and redirect policy apply instead
+	// of RequestsParams.Timeout and FeedMaxRedirects. nil uses the default client.
+	HTTPClient HTTPClient
+	// ParseNoscriptImages collects the <img> elements of <noscript> blocks, the fallback of lazy-loaded images,
+	// into Article.Images and the top image candidates
+	ParseNoscriptImages bool
+}
+
+// HTTPClient sends HTTP requests, as implemented by *http.Client
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
 }
 
 // TopImageSettings holds settings for finding top image.
@@ -52,44 +183,111 @@ type TopImageSettings struct {
 	MaxRetries int
 }
 
+// SummaryWeights weigh the features blended into the score of the sentences
+// considered for the summary
+type SummaryWeights struct {
+	Title     float64 // Overlap with the title
+	Frequency float64 // Density of the keywords of the text
+	Length    float64 // Closeness to the ideal sentence length
+	Position  float64 // Position in the text
+}
+
 // RequestsParams holds HTTP request parameters.
 type RequestsParams struct {
 	Timeout int
+	// Proxies maps a URL scheme ("http", "https") or "all" to the URL of the
+	// proxy the requests go through, e.g. {"http": "http://proxy:3128"}. The
+	// proxy of the environment is used when empty.
 	Proxies map[string]string
+	// Headers are sent with every request of articles and sources, the
+	// User-Agent header being replaced by UserAgent or UserAgents when set
 	Headers map[string]string
+	// UserAgent is sent with every request when set, instead of UserAgents
+	UserAgent string
+	// UserAgents is a pool of user agents used in turn, one per request, for sites blocking a static one
+	UserAgents []string
+	// MaxRetries is the number of times a download failing with a network error, a 5xx or a 429 status is
+	// retried, 0 disables retries
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry of a download, doubled for every following one. The
+	// Retry-After header of the response is honored instead when present.
+	RetryBackoff time.Duration
+
+	// userAgentTurn counts the requests sent with a user agent of UserAgents,
+	// shared by the copies of the configuration made after its first use
+	userAgentTurn *atomic.Uint64
+}
+
+// userAgentTurnMu guards the creation of the userAgentTurn of the
+// RequestsParams not made by NewConfiguration
+var userAgentTurnMu sync.Mutex
+
+// NextUserAgent returns the user agent of the next request: UserAgent, else
+// the next user agent of UserAgents in round-robin, else the User-Agent header.
+// The rotation is shared by the copies of a configuration made by
+// NewConfiguration, or made after its first request otherwise.
+func (rp *RequestsParams) NextUserAgent() string {
+	if rp.UserAgent != "" {
+		return rp.UserAgent
+	}
+	if len(rp.UserAgents) > 0 {
+		return rp.UserAgents[(rp.turn().Add(1)-1)%uint64(len(rp.UserAgents))]
+	}
+	return rp.Headers["User-Agent"]
+}
+
+// turn returns the user agent counter, creating it on the first request of
+// a configuration literal or decoded from a file
+func (rp *RequestsParams) turn() *atomic.Uint64 {
+	userAgentTurnMu.Lock()
+	defer userAgentTurnMu.Unlock()
+	if rp.userAgentTurn == nil {
+		rp.userAgentTurn = new(atomic.Uint64)
+	}
+	return rp.userAgentTurn
 }
 
 // NewConfiguration returns a Configuration with default values.
 func NewConfiguration() *Configuration {
 	return &Configuration{
-		MinWordCount:         300,
-		MinSentCount:         7,
-		MaxTitle:             200,
-		MaxText:              100000,
-		MaxKeywords:          35,
-		MaxWorkers:           20,
-		MaxFeeds:             100,
-		MaxAuthors:           10,
-		MaxSummary:           5000,
-		MaxSummarySent:       5,
-		MaxFileMemo:          20000,
-		TopImageSettings:     TopImageSettings{MinWidth: 300, MinHeight: 200, MinArea: 10000, MaxRetries: 2},
-		MemorizeArticles:     true,
-		DisableCategoryCache: false,
-		FetchImages:          true,
-		FollowMetaRefresh:    false,
-		UseMetaLanguage:      true,
-		CleanArticleHTML:     true,
-		HTTPSuccessOnly:      true,
-		language:             "",
-		RequestsParams:       RequestsParams{Timeout: 30, Proxies: map[string]string{}, Headers: map[string]string{"User-Agent": fmt.Sprintf("newspaper4k-go/%s", newspaper4kgo.Version)}},
-		NumberThreads:        10,
-		Verbose:              false,
-		ThreadTimeoutSeconds: 10,
-		AllowBinaryContent:   false,
-		IgnoredContentTypes:  map[string]string{},
-		UseCachedCategories:  true,
-		DownloadOptions:      DownloadOptions{InputHTML: ""},
+		MinWordCount:             300,
+		MinSentCount:             7,
+		MaxTitle:                 200,
+		MaxText:                  100000,
+		MaxKeywords:              35,
+		MaxWorkers:               20,
+		MaxFeeds:                 100,
+		MaxAuthors:               10,
+		MaxSummary:               5000,
+		MaxSummarySent:           5,
+		MaxFileMemo:              20000,
+		TopImageSettings:         TopImageSettings{MinWidth: 300, MinHeight: 200, MinArea: 10000, MaxRetries: 2},
+		MemorizeArticles:         true,
+		DisableCategoryCache:     false,
+		FetchImages:              true,
+		FollowMetaRefresh:        false,
+		UseMetaLanguage:          true,
+		CleanArticleHTML:         true,
+		HTTPSuccessOnly:          true,
+		language:                 "",
+		RequestsParams:           RequestsParams{Timeout: 30, Proxies: map[string]string{}, Headers: map[string]string{"User-Agent": fmt.Sprintf("newspaper4k-go/%s", newspaper4kgo.Version)}, userAgentTurn: new(atomic.Uint64)},
+		NumberThreads:            10,
+		Verbose:                  false,
+		ThreadTimeoutSeconds:     10,
+		AllowBinaryContent:       false,
+		IgnoredContentTypes:      map[string]string{},
+		UseCachedCategories:      true,
+		DownloadOptions:          DownloadOptions{InputHTML: ""},
+		MaxTextHeadRatio:         0.8,
+		MaxJSONLDBytes:           512 * 1024,
+		KeepImageCaptions:        true,
+		UpgradeInsecureImageURLs: true,
+		FeedMaxRedirects:         3,
+		FirstParagraphMinWords:   12,
+		StripEmptyBlocks:         true,
+		CleanDocKeepHead:         true,
+		SummaryWeights:           SummaryWeights{Title: 1.5, Frequency: 2.0, Length: 1.0, Position: 1.0},
+		MetaImageDefaultScheme:   "https",
 	}
 }
 
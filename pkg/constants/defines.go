@@ -6,6 +6,37 @@ var MOTLEY_REPLACEMENT = []string{"&#65533;", ""}
 // TITLE_REPLACEMENTS is used for cleaning split titles
 var TITLE_REPLACEMENTS = []string{"&raquo;", "»"}
 
+// TITLE_LABEL_PREFIXES leading labels stripped from titles when followed by a separator
+var TITLE_LABEL_PREFIXES = []string{"VIDEO", "VIDÉO", "WATCH", "LIVE", "EN DIRECT", "OPINION", "PHOTOS", "BREAKING"}
+
+// CAPTION_CREDIT_PATTERNS match image captions that are only photo credits:
+// a credit label, a copyright notice, or an agency closing the caption
+var CAPTION_CREDIT_PATTERNS = []string{
+	`^\W*((photo|image|picture)\s+)?(photo|photograph|image|picture|credit|source|foto)s?\s*:`,
+	`^\W*(photo|photograph|picture|foto)s?\s+by\b`,
+	`^\W*(©|\(c\)|copyright)`,
+	`(^|[/|,(]|\bvia)\s*(getty images|reuters|afp|associated press|ap|shutterstock|istock|epa|dpa)\W*$`,
+	`\bap photo\s*/`,
+}
+
+// NON_SECTION_SUBDOMAINS subdomains that never hold a news section
+var NON_SECTION_SUBDOMAINS = []string{
+	"www", "m", "mobile", "amp", "cdn", "static", "img", "images", "media", "assets",
+	"api", "login", "account", "accounts", "auth", "subscribe", "subscription", "shop", "store",
+	"help", "support", "mail", "email", "ads", "jobs", "careers",
+}
+
+// URL_SECTIONS path chunks recognized as news sections by Article.SectionFromURL
+var URL_SECTIONS = []string{
+	"news", "world", "international", "national", "local", "politics", "business", "economy", "money", "markets",
+	"tech", "technology", "science", "health", "education", "environment", "climate",
+	"sport", "sports", "football", "soccer", "culture", "arts", "entertainment", "music", "film", "movies",
+	"books", "lifestyle", "travel", "food", "fashion", "opinion", "opinions", "editorial", "weather",
+	"monde", "politique", "economie", "societe", "actualite", "actualites",
+	"welt", "politik", "wirtschaft", "kultur",
+	"mundo", "politica", "economia", "deportes", "cultura", "sociedad",
+}
+
 // A_REL_TAG_SELECTOR XPath selector for anchor tags with rel='tag'
 const A_REL_TAG_SELECTOR = "//a[@rel='tag']"
 
@@ -18,14 +49,36 @@ const RE_LANG = "^[A-Za-z]{2}$"
 // RE_URL regex pattern for URL extraction
 const RE_URL = `^(http:\/\/www\.|https:\/\/www\.|http:\/\/|https:\/\/|\/|\/\/)?[A-z0-9_-]*?[:]?[A-z0-9_-]*?[@]?[A-z0-9]+([\-\.]{1}[a-z0-9]+)*\.[a-z]{2,5}(:[0-9]{1,5})?(\/.*)?$`
 
+// AUTHOR_NAME_PARTICLES lowercase particles of surnames, e.g. "van der" in "Maria van der Berg"
+var AUTHOR_NAME_PARTICLES = []string{
+	"van", "von", "der", "den", "de", "del", "della", "di", "da", "das", "dos", "du",
+	"la", "le", "ter", "ten", "y", "e", "bin", "ibn", "al", "el", "zu", "af",
+}
+
+// OUTBOUND_STORY_PATTERNS lowercased phrases introducing the link to the full story on an aggregator page
+var OUTBOUND_STORY_PATTERNS = []string{
+	"read more at",
+	"read more on",
+	"read the full story",
+	"full story at",
+	"full story on",
+	"continue reading at",
+	"continue reading on",
+	"originally published on",
+	"originally published at",
+	"originally published in",
+	"originally appeared on",
+	"originally appeared in",
+}
+
 // AUTHOR_ATTRS attributes to look for author information
 var AUTHOR_ATTRS = []string{"name", "rel", "itemprop", "class", "id", "property"}
 
-// AUTHOR_VALS values to look for in author attributes
+// AUTHOR_VALS values to look for in author attributes. Dublin Core creators
+// are read from Article.DublinCore, see DUBLIN_CORE_AUTHOR_KEYS.
 var AUTHOR_VALS = []string{
 	"author",
 	"byline",
-	"dc.creator",
 	"byl",
 	"article:author",
 	"article:author_name",
@@ -117,6 +170,41 @@ var PUBLISH_DATE_META_INFO = []string{
 	"eomportal-lastUpdate",
 }
 
+// DUBLIN_CORE_PREFIXES lowercased meta name prefixes of the Dublin Core, eprints and PRISM tags kept in Article.DublinCore
+var DUBLIN_CORE_PREFIXES = []string{"dc.", "dcterms.", "eprints.", "prism."}
+
+// DUBLIN_CORE_TITLE_KEYS Article.DublinCore keys holding the title, by priority
+var DUBLIN_CORE_TITLE_KEYS = []string{"dc.title", "dcterms.title", "eprints.title"}
+
+// DUBLIN_CORE_AUTHOR_KEYS Article.DublinCore keys holding the authors, by priority
+var DUBLIN_CORE_AUTHOR_KEYS = []string{"dc.creator", "dcterms.creator", "eprints.creators_name", "dc.creator.personalname"}
+
+// DUBLIN_CORE_DATE_KEYS Article.DublinCore keys holding the publication date, by priority
+var DUBLIN_CORE_DATE_KEYS = []string{
+	"dcterms.issued",
+	"dc.date.issued",
+	"dcterms.created",
+	"dc.date.created",
+	"dcterms.date",
+	"dc.date",
+	"eprints.date",
+}
+
+// DUBLIN_CORE_KEYWORD_KEYS Article.DublinCore keys holding the keywords, by priority
+var DUBLIN_CORE_KEYWORD_KEYS = []string{"dc.subject", "dcterms.subject", "eprints.keywords"}
+
+// DUBLIN_CORE_CONTRIBUTOR_KEYS Article.DublinCore keys holding the contributors, listed after the creators, by priority
+var DUBLIN_CORE_CONTRIBUTOR_KEYS = []string{"dc.contributor", "dcterms.contributor"}
+
+// DUBLIN_CORE_ABSTRACT_KEYS Article.DublinCore keys holding the abstract, by priority
+var DUBLIN_CORE_ABSTRACT_KEYS = []string{"dcterms.abstract", "dc.description.abstract", "eprints.abstract", "dc.description", "dcterms.description"}
+
+// DUBLIN_CORE_DOI_KEYS Article.DublinCore keys holding the DOI, by priority
+var DUBLIN_CORE_DOI_KEYS = []string{"prism.doi", "dc.identifier.doi", "dc.identifier", "dcterms.identifier", "eprints.id_number"}
+
+// DUBLIN_CORE_PUBLICATION_KEYS Article.DublinCore keys holding the name of the publication, by priority
+var DUBLIN_CORE_PUBLICATION_KEYS = []string{"prism.publicationname", "eprints.publication"}
+
 // PublishDateTag represents a tag configuration for extracting publish dates
 type PublishDateTag struct {
 	Attribute string `json:"attribute"`
@@ -207,6 +295,10 @@ var META_LANGUAGE_TAGS = []map[string]string{
 	{"tag": "meta", "attr": "name", "value": "lang"},
 }
 
+// LANGUAGE_PRIORITY is the default order of the language signals of a page,
+// see Configuration.LanguagePriority
+var LANGUAGE_PRIORITY = []string{"article", "html", "og", "meta", "jsonld", "detect"}
+
 // URL_STOPWORDS words to ignore in URLs
 var URL_STOPWORDS = []string{
 	"about",
@@ -358,6 +450,26 @@ var VIDEO_TAGS = []string{"iframe", "embed", "object", "video"}
 // VIDEO_PROVIDERS supported video providers
 var VIDEO_PROVIDERS = []string{"youtube", "youtu.be", "vimeo", "dailymotion", "kewego", "twitch"}
 
+// OEMBED_ENDPOINTS oEmbed endpoints of the supported video providers
+var OEMBED_ENDPOINTS = map[string]string{
+	"youtube":     "https://www.youtube.com/oembed",
+	"youtu.be":    "https://www.youtube.com/oembed",
+	"vimeo":       "https://vimeo.com/api/oembed.json",
+	"dailymotion": "https://www.dailymotion.com/services/oembed",
+}
+
+// AUDIO_MIME_TYPES maps audio file extensions to their MIME type
+var AUDIO_MIME_TYPES = map[string]string{
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".ogg":  "audio/ogg",
+	".oga":  "audio/ogg",
+	".opus": "audio/opus",
+	".wav":  "audio/wav",
+	".flac": "audio/flac",
+}
+
 var CATEGORY_URL_PREFIXES = []string{
 	"category",
 	"categories",
@@ -462,3 +574,46 @@ var COMMON_ARTICLE_URL_GOODWORDS = []string{
 	"/content",
 	"/item?id=", // Hacker News style
 }
+
+// ARTICLE_JSONLD_TYPES are schema.org Article and its subtypes, marking the
+// JSON-LD objects of article pages
+var ARTICLE_JSONLD_TYPES = []string{
+	"Article",
+	"AdvertiserContentArticle",
+	"AnalysisNewsArticle",
+	"AskPublicNewsArticle",
+	"BackgroundNewsArticle",
+	"BlogPosting",
+	"LiveBlogPosting",
+	"NewsArticle",
+	"OpinionNewsArticle",
+	"Report",
+	"ReportageNewsArticle",
+	"ReviewNewsArticle",
+	"SatiricalArticle",
+	"ScholarlyArticle",
+	"MedicalScholarlyArticle",
+	"SocialMediaPosting",
+	"DiscussionForumPosting",
+	"TechArticle",
+	"APIReference",
+}
+
+// IMAGE_SIZE_QUERY_PARAMS are the query parameters resizing an image, ignored
+// when comparing the URLs of images (see Config.DedupeImages)
+var IMAGE_SIZE_QUERY_PARAMS = []string{
+	"w", "width", "h", "height", "size", "resize", "fit", "crop", "dpr", "quality", "q",
+}
+
+// IMAGE_CDN_HOSTS are the domains of image CDNs known to serve their images over https
+var IMAGE_CDN_HOSTS = []string{
+	"akamaihd.net",
+	"akamaized.net",
+	"cloudfront.net",
+	"cloudinary.com",
+	"fastly.net",
+	"ggpht.com",
+	"googleusercontent.com",
+	"imgix.net",
+	"wp.com",
+}
@@ -0,0 +1,205 @@
+package newspaper4k
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/parsers"
+	"github.com/tguidoux/newspaper4k-go/internal/urls"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/constants"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+// isoDurationRe matches ISO 8601 durations such as PT1H2M30S
+var isoDurationRe = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// AudioExtractor extracts audio files (podcast enclosures, <audio> sources) from HTML content
+type AudioExtractor struct {
+	config *configuration.Configuration
+}
+
+// NewAudioExtractor creates a new AudioExtractor
+func NewAudioExtractor(config *configuration.Configuration) *AudioExtractor {
+	return &AudioExtractor{config: config}
+}
+
+// Parse extracts audio files from the article
+func (ae *AudioExtractor) Parse(a *newspaper.Article) error {
+	if a.Doc == nil {
+		doc, err := parsers.FromString(a.HTML)
+		if err != nil {
+			return err
+		}
+		a.Doc = doc
+	}
+	audio := ae.getAudio(a.Doc, a.URL, a.JSONLD(maxJSONLDBytes(ae.config)).Graph)
+	if len(audio) > 0 {
+		a.Audio = audio
+	}
+	return nil
+}
+
+// getAudio extracts all audio files from the document
+func (ae *AudioExtractor) getAudio(doc *goquery.Document, articleURL string, jsonLD []map[string]any) []newspaper.AudioInfo {
+	var audio []newspaper.AudioInfo
+	seen := map[string]int{}
+
+	add := func(rawURL, mimeType string, duration time.Duration) {
+		audioURL := urls.JoinURL(articleURL, rawURL)
+		if audioURL == "" {
+			return
+		}
+		if mimeType == "" {
+			mimeType = ae.guessMimeType(audioURL)
+		}
+		// Merge information found in several places for the same file
+		if i, ok := seen[audioURL]; ok {
+			if audio[i].MimeType == "" {
+				audio[i].MimeType = mimeType
+			}
+			if audio[i].Duration == 0 {
+				audio[i].Duration = duration
+			}
+			return
+		}
+		seen[audioURL] = len(audio)
+		audio = append(audio, newspaper.AudioInfo{URL: audioURL, MimeType: mimeType, Duration: duration})
+	}
+
+	// Extract from audio tags and their sources
+	doc.Find("audio").Each(func(i int, element *goquery.Selection) {
+		if src := strings.TrimSpace(element.AttrOr("src", "")); src != "" {
+			add(src, element.AttrOr("type", ""), 0)
+		}
+		element.Find("source").Each(func(i int, source *goquery.Selection) {
+			if src := strings.TrimSpace(source.AttrOr("src", "")); src != "" {
+				add(src, source.AttrOr("type", ""), 0)
+			}
+		})
+	})
+
+	// Extract from RSS-style enclosures
+	doc.Find("enclosure").Each(func(i int, element *goquery.Selection) {
+		mimeType := strings.ToLower(element.AttrOr("type", ""))
+		if !strings.HasPrefix(mimeType, "audio/") {
+			return
+		}
+		if src := strings.TrimSpace(element.AttrOr("url", "")); src != "" {
+			add(src, mimeType, 0)
+		}
+	})
+
+	// Extract from JSON-LD PodcastEpisode / AudioObject
+	for _, obj := range ae.audioObjectsFromJSONLD(jsonLD) {
+		rawURL, _ := obj["contentUrl"].(string)
+		if rawURL == "" {
+			continue
+		}
+		mimeType, _ := obj["encodingFormat"].(string)
+		duration, _ := obj["duration"].(string)
+		add(rawURL, mimeType, parseAudioDuration(duration))
+	}
+
+	return audio
+}
+
+// audioObjectsFromJSONLD returns the audio media objects referenced by the JSON-LD objects.
+// The duration of a PodcastEpisode is copied to its media when the media has none.
+func (ae *AudioExtractor) audioObjectsFromJSONLD(candidates []map[string]any) []map[string]any {
+	var objects []map[string]any
+	for _, obj := range candidates {
+		switch {
+		case hasJSONLDType(obj, "AudioObject"):
+			objects = append(objects, obj)
+		case hasJSONLDType(obj, "PodcastEpisode"):
+			episodeDuration, _ := obj["duration"].(string)
+			if episodeDuration == "" {
+				episodeDuration, _ = obj["timeRequired"].(string)
+			}
+			for _, key := range []string{"associatedMedia", "audio"} {
+				var media []any
+				switch v := obj[key].(type) {
+				case map[string]any:
+					media = []any{v}
+				case []any:
+					media = v
+				}
+				for _, m := range media {
+					mediaObj, ok := m.(map[string]any)
+					if !ok {
+						continue
+					}
+					if d, _ := mediaObj["duration"].(string); d == "" && episodeDuration != "" {
+						mediaObj["duration"] = episodeDuration
+					}
+					objects = append(objects, mediaObj)
+				}
+			}
+		}
+	}
+	return objects
+}
+
+// guessMimeType guesses the audio MIME type from the file extension
+func (ae *AudioExtractor) guessMimeType(audioURL string) string {
+	parsedURL, err := url.Parse(audioURL)
+	if err != nil {
+		return ""
+	}
+	return constants.AUDIO_MIME_TYPES[strings.ToLower(path.Ext(parsedURL.Path))]
+}
+
+// hasJSONLDType checks whether a JSON-LD object has the given @type
+func hasJSONLDType(obj map[string]any, typ string) bool {
+	switch v := obj["@type"].(type) {
+	case string:
+		return v == typ
+	case []any:
+		for _, t := range v {
+			if s, ok := t.(string); ok && s == typ {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseAudioDuration parses ISO 8601 durations (PT1H2M3S), clock values (01:02:03, 62:03) and plain seconds
+func parseAudioDuration(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if m := isoDurationRe.FindStringSubmatch(strings.ToUpper(value)); m != nil {
+		var d time.Duration
+		units := []time.Duration{24 * time.Hour, time.Hour, time.Minute}
+		for i, unit := range units {
+			if m[i+1] != "" {
+				n, _ := strconv.Atoi(m[i+1])
+				d += time.Duration(n) * unit
+			}
+		}
+		if m[4] != "" {
+			secs, _ := strconv.ParseFloat(m[4], 64)
+			d += time.Duration(secs * float64(time.Second))
+		}
+		return d
+	}
+
+	var d time.Duration
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0
+		}
+		d = d*60 + time.Duration(n)
+	}
+	return d * time.Second
+}
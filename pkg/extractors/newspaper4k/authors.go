@@ -1,7 +1,9 @@
 package newspaper4k
 
 import (
+	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -38,7 +40,16 @@ func (ae *AuthorsExtractor) Parse(a *newspaper.Article) error {
 		a.Doc = doc
 	}
 
-	authors := ae.extractAuthors(a.Doc)
+	// Bylines of malformed JSON-LD blocks are lost
+	jsonLD := a.JSONLD(maxJSONLDBytes(ae.config))
+	for _, err := range jsonLD.Errors {
+		a.AddDiagnostic(newspaper.DiagnosticError, fmt.Sprintf("authors: ignored malformed JSON-LD block: %v", err))
+	}
+
+	authors := ae.extractAuthors(a.Doc, jsonLD)
+	if len(authors) == 0 {
+		authors = dublinCoreAuthors(a.DublinCore)
+	}
 
 	// Clean up authors of stopwords
 	authors = ae.cleanAuthors(authors)
@@ -56,11 +67,11 @@ func (ae *AuthorsExtractor) Parse(a *newspaper.Article) error {
 }
 
 // extractAuthors extracts authors from various sources
-func (ae *AuthorsExtractor) extractAuthors(doc *goquery.Document) []string {
+func (ae *AuthorsExtractor) extractAuthors(doc *goquery.Document, jsonLD *parsers.LdJson) []string {
 	authors := []string{}
 
 	// Try 1: Search JSON-LD structured data for authors
-	authors = append(authors, ae.extractFromJSONLD(doc)...)
+	authors = append(authors, ae.extractFromJSONLD(jsonLD)...)
 
 	// Try 2: Search popular author tags for authors
 	authors = append(authors, ae.extractFromAuthorTags(doc)...)
@@ -68,40 +79,46 @@ func (ae *AuthorsExtractor) extractAuthors(doc *goquery.Document) []string {
 	return authors
 }
 
+// dublinCoreAuthors returns the creators then the contributors of the Dublin
+// Core or eprints tags, turning the catalog form "Doe, Jane" into "Jane Doe"
+func dublinCoreAuthors(dc map[string]string) []string {
+	authors := []string{}
+	names := splitDublinCoreValue(dublinCoreValue(dc, constants.DUBLIN_CORE_AUTHOR_KEYS...), ";")
+	names = append(names, splitDublinCoreValue(dublinCoreValue(dc, constants.DUBLIN_CORE_CONTRIBUTOR_KEYS...), ";")...)
+	for _, name := range names {
+		if last, first, ok := strings.Cut(name, ","); ok && !strings.Contains(first, ",") &&
+			len(strings.Fields(last)) == 1 && strings.TrimSpace(first) != "" {
+			name = strings.TrimSpace(first) + " " + strings.TrimSpace(last)
+		}
+		authors = append(authors, name)
+	}
+	return authors
+}
+
 // extractFromJSONLD extracts authors from JSON-LD structured data
-func (ae *AuthorsExtractor) extractFromJSONLD(doc *goquery.Document) []string {
+func (ae *AuthorsExtractor) extractFromJSONLD(jsonLD *parsers.LdJson) []string {
 	authors := []string{}
 
-	// Use parser's GetLdJsonObject method
-	jsonObjects := parsers.GetLdJsonObject(doc.Selection)
-
-	for _, jsonData := range jsonObjects {
-		// Handle @graph structure
-		if graph, exists := jsonData["@graph"]; exists {
-			if graphArray, ok := graph.([]any); ok {
-				for _, item := range graphArray {
-					if itemMap, ok := item.(map[string]any); ok {
-						// Check for Person type
-						if itemType, exists := itemMap["@type"]; exists && itemType == "Person" {
-							if name, exists := itemMap["name"]; exists {
-								if nameStr, ok := name.(string); ok {
-									authors = append(authors, nameStr)
-								}
-							}
-						}
-						// Check for author field
-						if author, exists := itemMap["author"]; exists {
-							authors = append(authors, ae.extractAuthorNames(author)...)
-						}
-					}
-				}
-			}
-		} else {
-			// Check for author field in root
-			if author, exists := jsonData["author"]; exists {
-				authors = append(authors, ae.extractAuthorNames(author)...)
+	// Check for author field in root
+	for _, jsonData := range jsonLD.Objects {
+		if _, exists := jsonData["@graph"]; exists {
+			continue
+		}
+		if author, exists := jsonData["author"]; exists {
+			authors = append(authors, ae.extractAuthorNames(author)...)
+		}
+	}
+
+	// Handle @graph structure, where the Person objects are the authors
+	for _, itemMap := range jsonLD.Members {
+		if itemType, exists := itemMap["@type"]; exists && itemType == "Person" {
+			if nameStr, ok := itemMap["name"].(string); ok {
+				authors = append(authors, nameStr)
 			}
 		}
+		if author, exists := itemMap["author"]; exists {
+			authors = append(authors, ae.extractAuthorNames(author)...)
+		}
 	}
 
 	return authors
@@ -177,6 +194,9 @@ func (ae *AuthorsExtractor) extractFromAuthorTags(doc *goquery.Document) []strin
 
 // parseByline parses author names from a line of text
 func (ae *AuthorsExtractor) parseByline(searchStr string) []string {
+	// Decode entities first, so that "&amp;" and "&#38;" separate names
+	searchStr = parsers.UnescapeText(searchStr)
+
 	// Remove HTML tags
 	htmlRegex := regexp.MustCompile(`<[^>]+>`)
 	searchStr = htmlRegex.ReplaceAllString(searchStr, "")
@@ -193,7 +213,7 @@ func (ae *AuthorsExtractor) parseByline(searchStr string) []string {
 	searchStr = strings.TrimSpace(searchStr)
 
 	// Split by common separators
-	nameTokens := regexp.MustCompile(`[·,|]|\sand\s|\set\s|\sund\s|\/`).Split(searchStr, -1)
+	nameTokens := regexp.MustCompile(`[·,|]|\s&\s|\sand\s|\set\s|\sund\s|\/`).Split(searchStr, -1)
 
 	// Clean and filter tokens
 	validTokens := []string{}
@@ -208,9 +228,9 @@ func (ae *AuthorsExtractor) parseByline(searchStr string) []string {
 			continue
 		}
 
-		// Skip if not 2-5 words or 1-5 words with reasonable length
-		words := regexp.MustCompile(`\w+`).FindAllString(token, -1)
-		if len(words) < 2 || len(words) > 5 {
+		// Skip if not 2-5 words, hyphenated names and surname particles
+		// such as "van der" not counting as several words
+		if words := ae.nameWordCount(token); words < 2 || words > 5 {
 			continue
 		}
 
@@ -220,6 +240,28 @@ func (ae *AuthorsExtractor) parseByline(searchStr string) []string {
 	return validTokens
 }
 
+// nameWordCount returns the number of words of an author name: hyphenated
+// names such as "Jean-Paul" count as one word and lowercase surname particles
+// such as "van" or "de" do not count
+func (ae *AuthorsExtractor) nameWordCount(name string) int {
+	particles := constants.AUTHOR_NAME_PARTICLES
+	if ae.config != nil && ae.config.AuthorNameParticles != nil {
+		particles = ae.config.AuthorNameParticles
+	}
+
+	count := 0
+	for _, word := range strings.Fields(name) {
+		if !letterRegex.MatchString(word) || slices.Contains(particles, word) {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// letterRegex matches words containing a letter
+var letterRegex = regexp.MustCompile(`\pL`)
+
 // cleanAuthors removes stopwords from author names
 func (ae *AuthorsExtractor) cleanAuthors(authors []string) []string {
 	// Create regex pattern for stopwords
@@ -229,7 +271,7 @@ func (ae *AuthorsExtractor) cleanAuthors(authors []string) []string {
 	cleaned := []string{}
 	for _, author := range authors {
 		// Remove stopwords
-		cleanedAuthor := stopwordsRegex.ReplaceAllString(author, "")
+		cleanedAuthor := stopwordsRegex.ReplaceAllString(parsers.UnescapeText(author), "")
 		// Clean up extra punctuation and whitespace
 		cleanedAuthor = regexp.MustCompile(`^[^\w]+|[^\w]+$`).ReplaceAllString(cleanedAuthor, "")
 		cleanedAuthor = strings.TrimSpace(cleanedAuthor)
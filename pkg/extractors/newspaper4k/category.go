@@ -30,7 +30,9 @@ func NewCategoryExtractor(config *configuration.Configuration) *CategoryExtracto
 	}
 }
 
-// Parse extracts categories from the source URL and updates the article in-place
+// Parse extracts the categories the article is filed under and updates the
+// article in-place. With Config.SiteWideArticleCategories, it extracts the
+// categories of the whole source instead.
 func (ce *CategoryExtractor) Parse(a *newspaper.Article) error {
 	ce.categories = []*urls.URL{}
 
@@ -42,13 +44,111 @@ func (ce *CategoryExtractor) Parse(a *newspaper.Article) error {
 		a.Doc = doc
 	}
 
-	categories := ce.parse(a.SourceURL, a.Doc)
+	var categories []*urls.URL
+	if ce.config != nil && ce.config.SiteWideArticleCategories {
+		categories = ce.parse(a.SourceURL, a.Doc)
+	} else {
+		categories = ce.parseArticle(a)
+	}
 	ce.categories = categories
 	a.Categories = categories
 
 	return nil
 }
 
+// articleCategoryLinks match the links to the sections and tags of an article:
+// breadcrumbs, bylines and rel=category or rel=tag anchors
+const articleCategoryLinks = ".breadcrumb a, .breadcrumbs a, nav[aria-label='breadcrumb'] a, nav[aria-label='Breadcrumb'] a, " +
+	"[itemtype*='BreadcrumbList'] a, .byline a, .entry-meta a, .post-meta a, a[rel~='category'], a[rel~='tag']"
+
+// authorPathChunks mark the byline links to author pages rather than sections
+var authorPathChunks = []string{"author", "authors", "by", "people", "profile", "profiles", "staff", "contributors"}
+
+// parseArticle extracts the category URLs the article is filed under, in
+// document order: its breadcrumb, byline and tag links, then the links
+// labelled with its article:section meta tag or JSON-LD articleSection
+func (ce *CategoryExtractor) parseArticle(a *newspaper.Article) []*urls.URL {
+	articleURL, err := urls.Parse(a.URL)
+	if err != nil {
+		return []*urls.URL{}
+	}
+
+	var links []string
+	a.Doc.Find(articleCategoryLinks).Each(func(i int, s *goquery.Selection) {
+		if rel := strings.Fields(strings.ToLower(s.AttrOr("rel", ""))); slices.Contains(rel, "author") {
+			return
+		}
+		links = append(links, s.AttrOr("href", ""))
+	})
+
+	// Sections are names: use the links labelled with them
+	sections := []string{parsers.MetaField(a.Doc.Selection, "article:section")}
+	sections = append(sections, ce.jsonLDSections(a.JSONLD(maxJSONLDBytes(ce.config)).Graph)...)
+	anchors := a.Doc.Find("a[href]")
+	for _, section := range sections {
+		if section = parsers.InnerTrim(section); section == "" {
+			continue
+		}
+		anchors.EachWithBreak(func(i int, s *goquery.Selection) bool {
+			if strings.EqualFold(parsers.InnerTrim(s.Text()), section) {
+				links = append(links, s.AttrOr("href", ""))
+				return false
+			}
+			return true
+		})
+	}
+
+	categories := []*urls.URL{}
+	for _, link := range links {
+		link = strings.TrimSpace(link)
+		if link == "" || strings.HasPrefix(link, "#") {
+			continue
+		}
+		// Unlike the navigation links of the source, these links are known to
+		// be sections or tags, whatever their path
+		categoryURL, err := urls.Parse(urls.JoinURL(a.URL, link))
+		if err != nil || (categoryURL.Scheme != "http" && categoryURL.Scheme != "https") {
+			continue
+		}
+		categoryURL.Fragment = ""
+		if !strings.EqualFold(categoryURL.Domain, articleURL.Domain) || !strings.EqualFold(categoryURL.TLD, articleURL.TLD) {
+			continue
+		}
+		// The home page and the article itself are not categories
+		if strings.Trim(categoryURL.Path, "/") == "" || strings.TrimSuffix(categoryURL.Path, "/") == strings.TrimSuffix(articleURL.Path, "/") {
+			continue
+		}
+		if len(ce.intersection(categoryURL.GetPathChunks(), authorPathChunks)) > 0 {
+			continue
+		}
+		categories = append(categories, categoryURL)
+	}
+
+	return helpers.UniqueStructByKey(
+		categories,
+		func(f *urls.URL) string { return f.String() },
+		helpers.UniqueOptions{CaseSensitive: true, PreserveOrder: true},
+	)
+}
+
+// jsonLDSections returns the articleSection values of the JSON-LD objects
+func (ce *CategoryExtractor) jsonLDSections(jsonLD []map[string]any) []string {
+	var sections []string
+	for _, obj := range jsonLD {
+		switch v := obj["articleSection"].(type) {
+		case string:
+			sections = append(sections, v)
+		case []any:
+			for _, item := range v {
+				if section, ok := item.(string); ok {
+					sections = append(sections, section)
+				}
+			}
+		}
+	}
+	return sections
+}
+
 // parse extracts category URLs from the source
 func (ce *CategoryExtractor) parse(sourceURL string, doc *goquery.Document) []*urls.URL {
 	parsedSourceURL, err := urls.Parse(sourceURL)
@@ -70,7 +170,8 @@ func (ce *CategoryExtractor) parse(sourceURL string, doc *goquery.Document) []*u
 				continue
 			}
 
-			if parsedURL.Domain == "" || parsedURL.TLD == "" {
+			// IP and intranet hosts have no TLD
+			if parsedURL.Domain == "" {
 				continue
 			}
 
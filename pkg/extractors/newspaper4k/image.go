@@ -1,7 +1,12 @@
 package newspaper4k
 
 import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -10,6 +15,8 @@ import (
 	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
 	"github.com/tguidoux/newspaper4k-go/pkg/constants"
 	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 // ImageExtractor extracts images from articles
@@ -19,6 +26,9 @@ type ImageExtractor struct {
 	metaImage string
 	images    []string
 	favicon   string
+
+	topImageWidth  int
+	topImageHeight int
 }
 
 // NewImageExtractor creates a new ImageExtractor
@@ -38,6 +48,8 @@ func (ie *ImageExtractor) Parse(a *newspaper.Article) error {
 	ie.metaImage = ""
 	ie.images = []string{}
 	ie.favicon = ""
+	ie.topImageWidth = 0
+	ie.topImageHeight = 0
 
 	if a.Doc == nil {
 		doc, err := parsers.FromString(a.HTML)
@@ -47,12 +59,19 @@ func (ie *ImageExtractor) Parse(a *newspaper.Article) error {
 		a.Doc = doc
 	}
 
+	if ie.config.ParseNoscriptImages {
+		recoverNoscriptImages(a.Doc)
+	}
+
 	ie.parse(a.Doc, a.TopNode, a.URL)
 
-	a.TopImage = ie.topImage
-	a.MetaImg = ie.metaImage
+	a.TopImage = ie.secureImageURL(a, ie.topImage)
+	a.MetaImg = ie.secureImageURL(a, ie.metaImage)
 	a.Images = ie.images
-	a.MetaFavicon = ie.favicon
+	a.MetaFavicon = ie.secureImageURL(a, ie.favicon)
+	a.TopImageWidth = ie.topImageWidth
+	a.TopImageHeight = ie.topImageHeight
+	a.TopImageOrientation = imageOrientation(ie.topImageWidth, ie.topImageHeight)
 
 	return nil
 }
@@ -61,15 +80,98 @@ func (ie *ImageExtractor) Parse(a *newspaper.Article) error {
 func (ie *ImageExtractor) parse(doc *goquery.Document, topNode *goquery.Selection, articleURL string) {
 	ie.favicon = ie.getFavicon(doc)
 
-	ie.metaImage = ie.getMetaImage(doc)
-	if ie.metaImage != "" {
-		ie.metaImage = urls.JoinURL(articleURL, ie.metaImage)
-	}
+	ie.metaImage = ie.resolveMetaImage(articleURL, ie.getMetaImage(doc))
 
-	ie.images = ie.getImages(doc, articleURL)
+	ie.images = ie.getImages(doc, topNode, articleURL)
 	ie.topImage = ie.getTopImage(doc, topNode, articleURL)
 }
 
+// recoverNoscriptImages parses the <img> elements of the <noscript> blocks of
+// doc, which the HTML parser keeps as text, and appends them to the blocks so
+// that they are found like the other images of the page
+func recoverNoscriptImages(doc *goquery.Document) {
+	doc.Find("noscript").Each(func(i int, s *goquery.Selection) {
+		raw := s.Text()
+		if s.Find("img").Length() > 0 || !strings.Contains(strings.ToLower(raw), "<img") {
+			return
+		}
+		wrapper := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+		nodes, err := html.ParseFragment(strings.NewReader(raw), wrapper)
+		if err != nil {
+			return
+		}
+		for _, n := range nodes {
+			wrapper.AppendChild(n)
+		}
+		s.AppendSelection(goquery.NewDocumentFromNode(wrapper).Find("img"))
+	})
+}
+
+// resolveMetaImage makes the URL of a meta image absolute: protocol-relative
+// URLs take the scheme of the article, or Config.MetaImageDefaultScheme when
+// the article URL has none, root-relative and relative URLs are resolved
+// against the article URL
+func (ie *ImageExtractor) resolveMetaImage(articleURL, imageURL string) string {
+	imageURL = strings.TrimSpace(imageURL)
+	if imageURL == "" {
+		return ""
+	}
+	if !strings.HasPrefix(imageURL, "//") {
+		return urls.JoinURL(articleURL, imageURL)
+	}
+	scheme := ""
+	if u, err := url.Parse(articleURL); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		scheme = u.Scheme
+	} else if ie.config != nil {
+		scheme = ie.config.MetaImageDefaultScheme
+	}
+	if scheme == "" {
+		return imageURL
+	}
+	return scheme + ":" + imageURL
+}
+
+// secureImageURL avoids mixed content for the images of https articles:
+// protocol-relative URLs get the https scheme and, with
+// Config.UpgradeInsecureImageURLs, http URLs hosted on the article's site or a
+// known image CDN are rewritten to https. Other http URLs are kept and
+// reported in the diagnostics.
+func (ie *ImageExtractor) secureImageURL(a *newspaper.Article, imageURL string) string {
+	if imageURL == "" {
+		return ""
+	}
+	articleURL, err := urls.Parse(a.URL)
+	if err != nil || articleURL.Scheme != "https" {
+		return imageURL
+	}
+	if strings.HasPrefix(imageURL, "//") {
+		imageURL = "https:" + imageURL
+	}
+	if !strings.HasPrefix(strings.ToLower(imageURL), "http://") {
+		return imageURL
+	}
+
+	u, err := urls.Parse(imageURL)
+	if err == nil && ie.config.UpgradeInsecureImageURLs && isSecureImageHost(articleURL, u) {
+		u.Scheme = "https"
+		return u.String()
+	}
+	a.AddDiagnostic(newspaper.DiagnosticInfo, fmt.Sprintf("insecure image URL %s on an https page", imageURL))
+	return imageURL
+}
+
+// isSecureImageHost reports whether image is hosted on the registrable domain
+// of the article or on a known image CDN
+func isSecureImageHost(article, image *urls.URL) bool {
+	if image.Domain == "" {
+		return false
+	}
+	if strings.EqualFold(article.Domain, image.Domain) && strings.EqualFold(article.TLD, image.TLD) {
+		return true
+	}
+	return slices.Contains(constants.IMAGE_CDN_HOSTS, strings.ToLower(image.Domain+"."+image.TLD))
+}
+
 // getFavicon extracts the favicon from a website
 func (ie *ImageExtractor) getFavicon(doc *goquery.Document) string {
 	// Look for favicon links using parser's GetTags method
@@ -136,49 +238,171 @@ func (ie *ImageExtractor) getMetaImage(doc *goquery.Document) string {
 	return validCandidates[0].URL
 }
 
-// getImages gets all image sources from img tags
-func (ie *ImageExtractor) getImages(doc *goquery.Document, articleURL string) []string {
-	images := []string{}
+// imageSelector matches the images of a page, AMP pages using <amp-img>
+const imageSelector = "img, amp-img"
+
+// getImages gets all image sources from img and amp-img tags. Beyond Config.MaxImages,
+// only the images closest to the top node are kept, closest first.
+func (ie *ImageExtractor) getImages(doc *goquery.Document, topNode *goquery.Selection, articleURL string) []string {
+	candidates := []ImageCandidate{}
 
-	doc.Find("img").Each(func(i int, s *goquery.Selection) {
+	doc.Find(imageSelector).Each(func(i int, s *goquery.Selection) {
 		src := ie.getImageSrc(s)
 		if src != "" && !strings.HasPrefix(src, "data:") {
 			fullURL := urls.JoinURL(articleURL, src)
 			if fullURL != "" {
-				images = append(images, fullURL)
+				candidates = append(candidates, ImageCandidate{URL: fullURL, Element: s})
 			}
 		}
 	})
 
+	if ie.config.DedupeImages {
+		candidates = dedupeImageVariants(candidates)
+	}
+
+	if ie.config.MaxImages > 0 && len(candidates) > ie.config.MaxImages {
+		if topNode != nil && topNode.Length() > 0 {
+			for i := range candidates {
+				candidates[i].Score = ie.nodeDistance(topNode, candidates[i].Element)
+			}
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return candidates[i].Score < candidates[j].Score
+			})
+		}
+		candidates = candidates[:ie.config.MaxImages]
+	}
+
+	images := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		images[i] = candidate.URL
+	}
 	return images
 }
 
-// getImageSrc gets the src attribute from an img tag, checking multiple possible attributes
-func (ie *ImageExtractor) getImageSrc(img *goquery.Selection) string {
-	// Check for various src attributes in order of preference
-	srcAttrs := []string{"src", "data-src", "data-original", "data-lazy-src"}
+// dedupeImageVariants keeps one candidate per image, the variants of an image
+// differing by their size query parameters only. The largest declared variant
+// takes the place of the first one met.
+func dedupeImageVariants(candidates []ImageCandidate) []ImageCandidate {
+	deduped := make([]ImageCandidate, 0, len(candidates))
+	index := map[string]int{}
+	sizes := map[string]int{}
+	for _, candidate := range candidates {
+		key, size := imageVariant(candidate)
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(deduped)
+			sizes[key] = size
+			deduped = append(deduped, candidate)
+			continue
+		}
+		if size > sizes[key] {
+			sizes[key] = size
+			deduped[i].URL = candidate.URL
+			deduped[i].Element = candidate.Element
+		}
+	}
+	return deduped
+}
 
-	for _, attr := range srcAttrs {
-		if src, exists := img.Attr(attr); exists && src != "" {
+// imageVariant returns the URL of an image candidate without its size query
+// parameters, and its declared size: the product of the width and height
+// parameters of the URL or attributes of the element (a missing dimension
+// counting as 1), 0 when none is declared
+func imageVariant(candidate ImageCandidate) (string, int) {
+	u, err := url.Parse(candidate.URL)
+	if err != nil {
+		return candidate.URL, 0
+	}
+	query := u.Query()
+	dimension := func(params []string, attr string) int {
+		for _, param := range params {
+			if n, err := strconv.Atoi(query.Get(param)); err == nil && n > 0 {
+				return n
+			}
+		}
+		if candidate.Element != nil {
+			if n, err := strconv.Atoi(strings.TrimSpace(candidate.Element.AttrOr(attr, ""))); err == nil && n > 0 {
+				return n
+			}
+		}
+		return 0
+	}
+	width := dimension([]string{"w", "width"}, "width")
+	height := dimension([]string{"h", "height"}, "height")
+	size := 0
+	if width > 0 || height > 0 {
+		size = max(width, 1) * max(height, 1)
+	}
+
+	for _, param := range constants.IMAGE_SIZE_QUERY_PARAMS {
+		query.Del(param)
+	}
+	u.RawQuery = query.Encode()
+	u.Fragment = ""
+	return u.String(), size
+}
+
+// getImageSrc gets the source of an img tag. The URLs of lazy loaders
+// (data-src and similar, then srcset candidates) are preferred over a src
+// that is a data URI or a placeholder.
+func (ie *ImageExtractor) getImageSrc(img *goquery.Selection) string {
+	for _, attr := range []string{"data-src", "data-original", "data-lazy-src"} {
+		if src := strings.TrimSpace(img.AttrOr(attr, "")); src != "" && !strings.HasPrefix(src, "data:") {
 			return src
 		}
 	}
 
-	return ""
+	src := strings.TrimSpace(img.AttrOr("src", ""))
+	if src != "" && !strings.HasPrefix(src, "data:") && !placeholderImageRe.MatchString(src) {
+		return src
+	}
+	for _, attr := range []string{"data-srcset", "data-lazy-srcset", "srcset"} {
+		if candidate := largestSrcsetCandidate(img.AttrOr(attr, "")); candidate != "" {
+			return candidate
+		}
+	}
+	return src
+}
+
+// placeholderImageRe matches the file names of placeholders shown until lazy images load
+var placeholderImageRe = regexp.MustCompile(`(?i)(placeholder|blank|spacer|pixel|transparent|lazy|loading)[^/]*\.(gif|png|svg|jpe?g|webp)(\?|$)`)
+
+// largestSrcsetCandidate returns the URL of the widest (or densest) candidate of a srcset
+func largestSrcsetCandidate(srcset string) string {
+	best, bestSize := "", -1.0
+	for _, candidate := range strings.Split(srcset, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "data:") {
+			continue
+		}
+		size := 1.0
+		if len(fields) > 1 {
+			descriptor := strings.ToLower(fields[1])
+			if n, err := strconv.ParseFloat(strings.TrimRight(descriptor, "wx"), 64); err == nil {
+				size = n
+			}
+		}
+		if size > bestSize {
+			best, bestSize = fields[0], size
+		}
+	}
+	return best
 }
 
 // getTopImage gets the top image for the article
 func (ie *ImageExtractor) getTopImage(doc *goquery.Document, topNode *goquery.Selection, articleURL string) string {
 	// If we have a meta image and don't need to fetch images, use it
 	if ie.metaImage != "" && !ie.config.FetchImages {
+		ie.topImageWidth, ie.topImageHeight = ie.getMetaImageSize(doc)
 		return ie.metaImage
 	}
 
 	imgCandidates := []ImageCandidate{}
 
-	doc.Find("img").Each(func(i int, s *goquery.Selection) {
-		src, exists := s.Attr("src")
-		if !exists || src == "" || strings.HasPrefix(src, "data:") {
+	tooSmall := map[*goquery.Selection]bool{}
+	doc.Find(imageSelector).Each(func(i int, s *goquery.Selection) {
+		src := ie.getImageSrc(s)
+		if src == "" || strings.HasPrefix(src, "data:") {
 			return
 		}
 
@@ -192,14 +416,19 @@ func (ie *ImageExtractor) getTopImage(doc *goquery.Document, topNode *goquery.Se
 			Score:   distance,
 			Element: s,
 		})
+		tooSmall[s] = ie.isTooSmall(s)
 	})
 
 	if len(imgCandidates) == 0 {
 		return ""
 	}
 
-	// Sort by distance (closest to top node first)
-	sort.Slice(imgCandidates, func(i, j int) bool {
+	// Sort by distance (closest to top node first), images declared smaller
+	// than the TopImageSettings last
+	sort.SliceStable(imgCandidates, func(i, j int) bool {
+		if small := tooSmall[imgCandidates[i].Element]; small != tooSmall[imgCandidates[j].Element] {
+			return !small
+		}
 		return imgCandidates[i].Score < imgCandidates[j].Score
 	})
 
@@ -207,6 +436,11 @@ func (ie *ImageExtractor) getTopImage(doc *goquery.Document, topNode *goquery.Se
 	for _, candidate := range imgCandidates {
 		fullURL := urls.JoinURL(articleURL, candidate.URL)
 		if fullURL != "" {
+			ie.topImageWidth = imageDimension(candidate.Element, "width")
+			ie.topImageHeight = imageDimension(candidate.Element, "height")
+			if (ie.topImageWidth == 0 || ie.topImageHeight == 0) && fullURL == ie.metaImage {
+				ie.topImageWidth, ie.topImageHeight = ie.getMetaImageSize(doc)
+			}
 			return fullURL
 		}
 	}
@@ -214,6 +448,69 @@ func (ie *ImageExtractor) getTopImage(doc *goquery.Document, topNode *goquery.Se
 	return ""
 }
 
+// isTooSmall reports whether the width and height attributes of img, which
+// are mandatory on <amp-img>, are below the minimums of the TopImageSettings.
+// Images without declared size are not too small.
+func (ie *ImageExtractor) isTooSmall(img *goquery.Selection) bool {
+	width, height := imageDimension(img, "width"), imageDimension(img, "height")
+	if width == 0 || height == 0 {
+		return false
+	}
+	settings := ie.config.TopImageSettings
+	return width < settings.MinWidth || height < settings.MinHeight || width*height < settings.MinArea
+}
+
+// getMetaImageSize returns the og:image:width and og:image:height declared for
+// the meta image, 0 when missing
+func (ie *ImageExtractor) getMetaImageSize(doc *goquery.Document) (int, int) {
+	size := func(property string) int {
+		for _, m := range parsers.GetMetatags(doc.Selection, property) {
+			if n := parseDimension(getAttrContent(m, "content")); n > 0 {
+				return n
+			}
+		}
+		return 0
+	}
+	return size("og:image:width"), size("og:image:height")
+}
+
+// imageDimension returns the width or height attribute of img in pixels, 0 when missing or relative
+func imageDimension(img *goquery.Selection, attr string) int {
+	if img == nil {
+		return 0
+	}
+	return parseDimension(getAttrContent(img, attr))
+}
+
+// parseDimension parses a pixel size such as "640" or "640px"
+func parseDimension(value string) int {
+	value = strings.TrimSuffix(strings.TrimSpace(strings.ToLower(value)), "px")
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// squareTolerance is the aspect ratio deviation under which an image is square
+const squareTolerance = 0.05
+
+// imageOrientation returns landscape, portrait or square, or "" when a dimension is unknown
+func imageOrientation(width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	ratio := float64(width) / float64(height)
+	switch {
+	case ratio > 1+squareTolerance:
+		return "landscape"
+	case ratio < 1-squareTolerance:
+		return "portrait"
+	default:
+		return "square"
+	}
+}
+
 // nodeDistance calculates the distance between two nodes in the DOM tree
 func (ie *ImageExtractor) nodeDistance(node1, node2 *goquery.Selection) int {
 	if node1 == nil || node2 == nil {
@@ -238,14 +535,11 @@ func (ie *ImageExtractor) nodeDistance(node1, node2 *goquery.Selection) int {
 }
 
 // getNodePath gets the path from root to node
-func (ie *ImageExtractor) getNodePath(node *goquery.Selection) []string {
-	path := []string{}
-	current := node
-
-	for current.Length() > 0 {
-		tagName := current.Get(0).Data
-		path = append([]string{tagName}, path...)
-		current = current.Parent()
+func (ie *ImageExtractor) getNodePath(node *goquery.Selection) []*html.Node {
+	path := []*html.Node{}
+
+	for current := node.Get(0); current != nil; current = current.Parent {
+		path = append([]*html.Node{current}, path...)
 	}
 
 	return path
@@ -1,8 +1,10 @@
 package newspaper4k
 
 import (
+	"slices"
 	"strings"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/tguidoux/newspaper4k-go/internal/languages"
 	"github.com/tguidoux/newspaper4k-go/internal/parsers"
 	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
@@ -11,7 +13,8 @@ import (
 
 // LanguageExtractor detects the article language and sets Article.MetaLang
 // It respects an explicitly configured language (Configuration.Language) and
-// will not override an existing Article.MetaLang.
+// will not override an existing Article.MetaLang, except with the lang
+// attribute declared around the extracted body.
 type LanguageExtractor struct {
 	config *configuration.Configuration
 }
@@ -27,33 +30,93 @@ func (le *LanguageExtractor) Parse(a *newspaper.Article) error {
 	if le.config != nil && le.config.Language() != "" {
 		a.MetaLang = le.config.Language()
 		a.Language = languages.GetTagFromISO639_1(a.MetaLang)
+		a.LanguageSource = "config"
 		return nil
 	}
 
-	// 2) if meta language already present, populate language tag and exit
+	// 2) a lang attribute around the extracted body is the most specific
+	// declaration, it overrides the page level one unless a signal ranking
+	// before "article" was picked
+	priority := languagePriority(le.config)
+	if lang := topNodeLanguage(a.TopNode); lang != "" && lang != a.MetaLang && ranksBefore(priority, "article", a.LanguageSource) {
+		a.MetaLang = lang
+		a.Language = languages.GetTagFromISO639_1(lang)
+		a.LanguageConfidence = 0
+		a.LanguageSource = "top_node"
+		return nil
+	}
+
+	// 3) if meta language already present, populate language tag and exit
 	if a.MetaLang != "" {
 		a.Language = languages.GetTagFromISO639_1(a.MetaLang)
 		return nil
 	}
 
-	// 3) build a short piece of text to detect language from
+	// 4) build a short piece of text to detect language from
 	text := buildDetectionText(a)
 	if text == "" {
+		le.fallbackAfterDetect(a, priority)
 		return nil
 	}
 
-	// 4) detect language and set fields when detection yields a usable code
+	// 5) detect language and set fields when detection yields a usable code
 	info := languages.FromString(text)
 	lang := info.LanguageCode()
 	if lang == "" || lang == "und" {
+		le.fallbackAfterDetect(a, priority)
 		return nil
 	}
 
 	a.MetaLang = lang
 	a.Language = languages.GetTagFromISO639_1(lang)
+	a.LanguageConfidence = info.Confidence()
+	a.LanguageSource = "detected"
 	return nil
 }
 
+// fallbackAfterDetect uses the signals ranked after "detect" when detection
+// yields no language
+func (le *LanguageExtractor) fallbackAfterDetect(a *newspaper.Article, priority []string) {
+	i := slices.Index(priority, "detect")
+	if i < 0 || a.Doc == nil {
+		return
+	}
+	signals := (&MetadataExtractor{config: le.config}).languageSignals(a.Doc, a.JSONLD(maxJSONLDBytes(le.config)).Graph)
+	if lang, source := pickLanguageSignal(signals, priority[i+1:]); lang != "" {
+		a.MetaLang = lang
+		a.Language = languages.GetTagFromISO639_1(lang)
+		a.LanguageSource = source
+	}
+}
+
+// ranksBefore reports whether signal comes before current in priority. Any
+// listed signal ranks before an empty or detected source.
+func ranksBefore(priority []string, signal, current string) bool {
+	i := slices.Index(priority, signal)
+	if i < 0 {
+		return false
+	}
+	j := slices.Index(priority, current)
+	if current == "detected" {
+		j = slices.Index(priority, "detect")
+	}
+	return j < 0 || i < j
+}
+
+// topNodeLanguage returns the lang attribute of the top node or of its
+// nearest ancestor declaring one. The top node built from several siblings
+// has no ancestors: the lang attribute of its first child is used instead.
+func topNodeLanguage(topNode *goquery.Selection) string {
+	if topNode == nil || topNode.Length() == 0 {
+		return ""
+	}
+	// <html> is the page level declaration, already read by the MetadataExtractor
+	if lang := parsers.LangAttribute(topNode.Closest("[lang]:not(html)")); lang != "" {
+		return lang
+	}
+	return parsers.LangAttribute(topNode.Children().Filter("[lang]").First())
+}
+
 // buildDetectionText chooses the best available text to run language detection on.
 // Priority: Title + Text (if available) -> parsed visible text from HTML -> raw HTML fallback.
 func buildDetectionText(a *newspaper.Article) string {
@@ -1,15 +1,19 @@
 package newspaper4k
 
 import (
-	"net/url"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/helpers"
 	"github.com/tguidoux/newspaper4k-go/internal/parsers"
 	"github.com/tguidoux/newspaper4k-go/internal/urls"
 	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
 	"github.com/tguidoux/newspaper4k-go/pkg/constants"
-	"github.com/tguidoux/newspaper4k-go/pkg/languages"
 	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
 )
 
@@ -35,85 +39,220 @@ func (me *MetadataExtractor) Parse(a *newspaper.Article) error {
 		a.Doc = doc
 	}
 	// Extract metadata
-	a.MetaLang = me.getMetaLanguage(a.Doc)
+	jsonLD := a.JSONLD(maxJSONLDBytes(me.config))
+	var languageDiagnostics []string
+	a.MetaLang, a.LanguageSource, languageDiagnostics = me.getMetaLanguage(a.Doc, jsonLD.Graph)
+	for _, diagnostic := range languageDiagnostics {
+		a.AddDiagnostic(newspaper.DiagnosticInfo, diagnostic)
+	}
 	a.CanonicalLink = me.getCanonicalLink(a.URL, a.Doc)
 	a.MetaSiteName = me.getMetaField(a.Doc, "og:site_name")
 	a.MetaDescription = me.getMetaField(a.Doc, "description", "og:description")
-	a.MetaKeywords = me.getMetaKeywords(a.Doc)
 	a.MetaData = me.getMetadata(a.Doc)
+	a.DublinCore = me.getDublinCore(a.Doc)
+	a.MetaKeywords = me.getMetaKeywords(a.Doc)
+	if subjects := splitDublinCoreValue(dublinCoreValue(a.DublinCore, constants.DUBLIN_CORE_KEYWORD_KEYS...), ";,"); len(subjects) > 0 {
+		a.MetaKeywords = helpers.UniqueStrings(append(a.MetaKeywords, subjects...), helpers.UniqueOptions{CaseSensitive: false, PreserveOrder: true})
+	}
+	if a.MetaDescription == "" {
+		a.MetaDescription = dublinCoreValue(a.DublinCore, constants.DUBLIN_CORE_ABSTRACT_KEYS...)
+	}
+	if a.MetaSiteName == "" {
+		a.MetaSiteName = dublinCoreValue(a.DublinCore, constants.DUBLIN_CORE_PUBLICATION_KEYS...)
+	}
+	a.DOI = dublinCoreDOI(a.DublinCore)
+	a.MetaSiteName = parsers.UnescapeText(a.MetaSiteName)
+	a.MetaDescription = parsers.UnescapeText(a.MetaDescription)
+	for i, keyword := range a.MetaKeywords {
+		a.MetaKeywords[i] = parsers.UnescapeText(keyword)
+	}
+	a.OGType = me.getMetaField(a.Doc, "og:type")
+	a.ContentType = newspaper.ContentTypeFromOGType(a.OGType)
+	a.PrevURL = me.getRelLink(a.URL, a.Doc, "prev", "previous")
+	a.NextURL = me.getRelLink(a.URL, a.Doc, "next")
+	a.DiscussionURL = me.getDiscussionURL(a.URL, a.Doc)
+	for _, diagnostic := range me.checkJSONLDSizes(jsonLD.Raw) {
+		a.AddDiagnostic(newspaper.DiagnosticInfo, diagnostic)
+	}
+	if me.config != nil && me.config.KeepRawJSONLD {
+		a.RawJSONLD = slices.Clone(jsonLD.Raw)
+	}
+	me.setRobotsDirectives(a)
+	a.Breadcrumbs = me.getBreadcrumbs(a.Doc, jsonLD.Graph)
+	a.Rating = me.getRating(jsonLD.Graph)
+	a.PartOf, a.PartOfURL = me.getPartOf(a.URL, a.Doc, jsonLD.Graph)
 
 	return nil
 }
 
-// getMetaLanguage extracts the language from meta tags
-func (me *MetadataExtractor) getMetaLanguage(doc *goquery.Document) string {
-	// 1) prefer the `lang` attribute on <html>
-	if raw, exists := doc.Find("html").Attr("lang"); exists {
-		lang := strings.ToLower(strings.TrimSpace(raw))
-		if languages.IsValidLanguageCode(lang) {
-			return lang
+// getMetaLanguage extracts the declared language of the page and the signal
+// it comes from, following the language priority of the configuration. It
+// returns no language when detection ranks before every declared signal, and
+// a diagnostic listing the signals when they disagree.
+func (me *MetadataExtractor) getMetaLanguage(doc *goquery.Document, jsonLD []map[string]any) (string, string, []string) {
+	signals := me.languageSignals(doc, jsonLD)
+	var diagnostics []string
+	if conflict := conflictingLanguageSignals(signals); conflict != "" {
+		diagnostics = append(diagnostics, conflict)
+	}
+	lang, source := pickLanguageSignal(signals, languagePriority(me.config))
+	return lang, source, diagnostics
+}
+
+// languageSignal is a language declared by the page
+type languageSignal struct {
+	source string // article, html, jsonld, og or meta
+	lang   string
+}
+
+// languageSignals returns the first valid language of each signal of the
+// page, in the order of constants.LANGUAGE_PRIORITY
+func (me *MetadataExtractor) languageSignals(doc *goquery.Document, jsonLD []map[string]any) []languageSignal {
+	var signals []languageSignal
+	add := func(source, lang string) {
+		if lang == "" || slices.ContainsFunc(signals, func(s languageSignal) bool { return s.source == source }) {
+			return
 		}
+		signals = append(signals, languageSignal{source: source, lang: lang})
 	}
 
-	// 2) fallback to configured META_LANGUAGE_TAGS (e.g. <meta property/name=item> tags)
+	// content wrappers override the template default of <html>
+	add("article", parsers.LangAttribute(doc.Find("article[lang], main[lang]")))
+	add("html", parsers.LangAttribute(doc.Find("html")))
+
+	// og:locale and the other META_LANGUAGE_TAGS (<meta property/name=item> tags)
 	for _, entry := range constants.META_LANGUAGE_TAGS {
-		tag := entry["tag"]
-		attr := entry["attr"]
-		value := entry["value"]
-
-		sel := doc.Find(tag + "[" + attr + "='" + value + "']").First()
-		if content := getAttrContent(sel, "content"); content != "" {
-			lang := strings.ToLower(strings.TrimSpace(content))
-			if languages.IsValidLanguageCode(lang) {
-				return lang
-			}
+		sels := parsers.GetTags(doc.Selection, entry["tag"], map[string]string{entry["attr"]: entry["value"]}, "exact", false)
+		if len(sels) == 0 {
+			continue
 		}
+		source := "meta"
+		if strings.HasPrefix(entry["value"], "og:") {
+			source = "og"
+		}
+		add(source, parsers.LanguageCode(getAttrContent(sels[0], "content")))
 	}
 
+	add("jsonld", jsonLDLanguage(jsonLD))
+	return signals
+}
+
+// jsonLDLanguage returns the first valid inLanguage of the JSON-LD objects,
+// given as a code or as a Language object with an alternateName
+func jsonLDLanguage(jsonLD []map[string]any) string {
+	for _, obj := range jsonLD {
+		var code string
+		switch v := obj["inLanguage"].(type) {
+		case string:
+			code = v
+		case map[string]any:
+			code, _ = v["alternateName"].(string)
+		}
+		if lang := parsers.LanguageCode(code); lang != "" {
+			return lang
+		}
+	}
 	return ""
 }
 
-// getCanonicalLink extracts the canonical URL
-func (me *MetadataExtractor) getCanonicalLink(articleURL string, doc *goquery.Document) string {
-	// Collect candidate URLs: <link rel="canonical"> and og:url
-	var candidates []string
+// languagePriority returns the order of the language signals of config
+func languagePriority(config *configuration.Configuration) []string {
+	if config != nil && len(config.LanguagePriority) > 0 {
+		return config.LanguagePriority
+	}
+	return constants.LANGUAGE_PRIORITY
+}
 
-	linkElems := parsers.GetTags(doc.Selection, "link", map[string]string{"rel": "canonical"}, "exact", false)
-	for _, el := range linkElems {
-		attr := parsers.GetAttribute(el, "href", nil, "")
-		if hrefStr, ok := attr.(string); ok && strings.TrimSpace(hrefStr) != "" {
-			candidates = append(candidates, strings.TrimSpace(hrefStr))
-			break // prefer first canonical link
+// pickLanguageSignal returns the language of the first signal of priority
+// observed on the page. Nothing is picked once "detect" is reached.
+func pickLanguageSignal(signals []languageSignal, priority []string) (string, string) {
+	for _, source := range priority {
+		if source == "detect" {
+			return "", ""
+		}
+		for _, signal := range signals {
+			if signal.source == source {
+				return signal.lang, signal.source
+			}
 		}
 	}
+	return "", ""
+}
 
-	if og := me.getMetaField(doc, "og:url"); og != "" {
-		candidates = append(candidates, og)
+// conflictingLanguageSignals describes the signals when they declare
+// different languages, or returns ""
+func conflictingLanguageSignals(signals []languageSignal) string {
+	conflict := false
+	observed := make([]string, 0, len(signals))
+	for _, signal := range signals {
+		conflict = conflict || signal.lang != signals[0].lang
+		observed = append(observed, signal.source+"="+signal.lang)
 	}
-
-	if len(candidates) == 0 {
+	if !conflict {
 		return ""
 	}
+	return "conflicting language signals: " + strings.Join(observed, ", ")
+}
 
-	raw := candidates[0]
+// getCanonicalLink extracts the canonical URL
+func (me *MetadataExtractor) getCanonicalLink(articleURL string, doc *goquery.Document) string {
+	return parsers.CanonicalLink(articleURL, doc.Selection)
+}
 
-	// Parse using net/url. If relative, resolve against articleURL.
-	parsedMeta, err := url.Parse(raw)
-	if err != nil {
-		return raw
+// getRelLink extracts the first <link> matching one of the rel values, resolved against the article URL
+func (me *MetadataExtractor) getRelLink(articleURL string, doc *goquery.Document, rels ...string) string {
+	for _, rel := range rels {
+		linkElems := parsers.GetTags(doc.Selection, "link", map[string]string{"rel": rel}, "word", false)
+		for _, el := range linkElems {
+			attr := parsers.GetAttribute(el, "href", nil, "")
+			if hrefStr, ok := attr.(string); ok && strings.TrimSpace(hrefStr) != "" {
+				return urls.JoinURL(articleURL, strings.TrimSpace(hrefStr))
+			}
+		}
 	}
+	return ""
+}
 
-	if parsedMeta.IsAbs() {
-		return parsedMeta.String()
-	}
+// discussionAnchorRe matches the text of the links to the comments of an
+// article, e.g. "Comments", "12 comments" or "Join the discussion"
+var discussionAnchorRe = regexp.MustCompile(`(?i)^(\d+\s+)?(comments?|discuss(ion)?|(join|read|view|see)\s+(the\s+)?(all\s+)?(\d+\s+)?(comments|discussion|conversation))(\s*\(\d+\))?$`)
 
-	parsedArticle, err := urls.Parse(articleURL)
-	if err != nil {
-		return parsedMeta.String()
+// getDiscussionURL returns the absolute URL of the discussion page of the
+// article: the first <link> or <a> with rel="discussion" or, failing that,
+// the first link titled like "Comments". Links to an anchor of the article
+// itself (#comments) are not a separate page and are ignored.
+func (me *MetadataExtractor) getDiscussionURL(articleURL string, doc *goquery.Document) string {
+	discussion := ""
+	accept := func(href string) bool {
+		href = strings.TrimSpace(href)
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			return false
+		}
+		resolved := urls.JoinURL(articleURL, href)
+		page, _, _ := strings.Cut(resolved, "#")
+		self, _, _ := strings.Cut(articleURL, "#")
+		if articleURL != "" && page == self {
+			return false
+		}
+		discussion = resolved
+		return true
 	}
 
-	resolved := parsedArticle.ResolveReference(parsedMeta)
-	return resolved.String()
+	for _, tag := range []string{"link", "a"} {
+		for _, el := range parsers.GetTags(doc.Selection, tag, map[string]string{"rel": "discussion"}, "word", false) {
+			if accept(el.AttrOr("href", "")) {
+				return discussion
+			}
+		}
+	}
+	doc.Find("a[href]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		text := strings.Join(strings.Fields(s.Text()), " ")
+		if !discussionAnchorRe.MatchString(text) {
+			return true
+		}
+		return !accept(s.AttrOr("href", ""))
+	})
+	return discussion
 }
 
 // getMetadata extracts all metadata from meta tags
@@ -131,50 +270,348 @@ func (me *MetadataExtractor) getMetadata(doc *goquery.Document) map[string]strin
 			key = v
 		}
 
-		if key == "" {
+		key = strings.TrimSpace(key)
+		if key == "" || !me.isAllowedMetaData(key) {
 			return
 		}
 
 		if content := getAttrContent(s, "content"); content != "" {
-			out[strings.TrimSpace(key)] = strings.TrimSpace(content)
+			out[key] = strings.TrimSpace(content)
 		}
 	})
 
 	return out
 }
 
+// isAllowedMetaData reports whether the meta tag key is stored in MetaData,
+// following Config.MetaDataAllowlist
+func (me *MetadataExtractor) isAllowedMetaData(key string) bool {
+	if me.config == nil || len(me.config.MetaDataAllowlist) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(me.config.MetaDataAllowlist, func(allowed string) bool {
+		return strings.EqualFold(allowed, key)
+	})
+}
+
 // getMetaField extracts a specific meta field
 func (me *MetadataExtractor) getMetaField(doc *goquery.Document, fields ...string) string {
-	for _, f := range fields {
-		// Use parser helper to find meta tags (handles property/name/itemprop variations)
-		metas := parsers.GetMetatags(doc.Selection, f)
-		for _, m := range metas {
-			content := parsers.GetAttribute(m, "content", nil, "")
-			if contentStr, ok := content.(string); ok && strings.TrimSpace(contentStr) != "" {
-				return strings.TrimSpace(contentStr)
-			}
+	return parsers.MetaField(doc.Selection, fields...)
+}
+
+// getMetaKeywords extracts keywords from the news_keywords and keywords meta tags
+func (me *MetadataExtractor) getMetaKeywords(doc *goquery.Document) []string {
+	return parsers.MetaKeywords(doc.Selection)
+}
+
+// getDublinCore collects the Dublin Core, eprints and PRISM meta tags (DC.*,
+// DCTERMS.*, eprints.*, prism.*) keyed by their lowercased name. Repeated tags such as
+// one DC.creator per author are joined with "; ".
+func (me *MetadataExtractor) getDublinCore(doc *goquery.Document) map[string]string {
+	dc := map[string]string{}
+	doc.Find("meta").Each(func(i int, s *goquery.Selection) {
+		name := strings.ToLower(strings.TrimSpace(getAttrContent(s, "name")))
+		if name == "" {
+			name = strings.ToLower(strings.TrimSpace(getAttrContent(s, "property")))
+		}
+		if !slices.ContainsFunc(constants.DUBLIN_CORE_PREFIXES, func(prefix string) bool { return strings.HasPrefix(name, prefix) }) {
+			return
+		}
+		value := parsers.InnerTrim(getAttrContent(s, "content"))
+		if value == "" {
+			return
+		}
+		if existing, ok := dc[name]; !ok {
+			dc[name] = value
+		} else if !slices.Contains(strings.Split(existing, "; "), value) {
+			dc[name] = existing + "; " + value
+		}
+	})
+	if len(dc) == 0 {
+		return nil
+	}
+	return dc
+}
+
+// dublinCoreValue returns the first non-empty value of keys in dc
+func dublinCoreValue(dc map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := dc[key]; value != "" {
+			return value
 		}
 	}
 	return ""
 }
 
-// getMetaKeywords extracts keywords from meta tags
-func (me *MetadataExtractor) getMetaKeywords(doc *goquery.Document) []string {
-	ks := me.getMetaField(doc, "keywords")
-	if ks == "" {
-		return nil
+// doiPattern matches a bare DOI, e.g. 10.1000/xyz123
+var doiPattern = regexp.MustCompile(`^10\.\d{4,9}/\S+$`)
+
+// dublinCoreDOI returns the first DOI found in the DOI keys of dc, without
+// its doi: or resolver URL prefix. Identifiers that are not DOIs, such as an
+// ISSN in dc.identifier, are skipped.
+func dublinCoreDOI(dc map[string]string) string {
+	for _, key := range constants.DUBLIN_CORE_DOI_KEYS {
+		for _, value := range splitDublinCoreValue(dc[key], ";") {
+			lower := strings.ToLower(value)
+			for _, prefix := range []string{"doi:", "https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/"} {
+				if strings.HasPrefix(lower, prefix) {
+					value = strings.TrimSpace(value[len(prefix):])
+					break
+				}
+			}
+			if doiPattern.MatchString(value) {
+				return value
+			}
+		}
 	}
+	return ""
+}
 
-	parts := strings.Split(ks, ",")
-	out := make([]string, 0, len(parts))
-	for _, p := range parts {
-		if t := strings.TrimSpace(p); t != "" {
+// splitDublinCoreValue splits value on any of the separators and drops empty parts
+func splitDublinCoreValue(value, separators string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return strings.ContainsRune(separators, r) }) {
+		if t := strings.TrimSpace(part); t != "" {
 			out = append(out, t)
 		}
 	}
 	return out
 }
 
+// setRobotsDirectives merges the robots and googlebot meta tags into the
+// directives of the X-Robots-Tag header collected by Download
+func (me *MetadataExtractor) setRobotsDirectives(a *newspaper.Article) {
+	values := a.RobotsDirectives
+	for _, name := range []string{"robots", "googlebot"} {
+		for _, m := range parsers.GetMetatags(a.Doc.Selection, name) {
+			values = append(values, getAttrContent(m, "content"))
+		}
+	}
+	a.RobotsDirectives = parsers.ParseRobotsDirectives(values...)
+	a.NoIndex = slices.Contains(a.RobotsDirectives, "noindex") || slices.Contains(a.RobotsDirectives, "none")
+	a.NoArchive = slices.Contains(a.RobotsDirectives, "noarchive")
+}
+
+// getBreadcrumbs extracts the breadcrumb trail of the page, from the
+// BreadcrumbList JSON-LD, then BreadcrumbList microdata, then breadcrumb links
+func (me *MetadataExtractor) getBreadcrumbs(doc *goquery.Document, jsonLD []map[string]any) []string {
+	for _, obj := range jsonLD {
+		if !hasJSONLDType(obj, "BreadcrumbList") {
+			continue
+		}
+		if trail := breadcrumbsFromJSONLD(obj); len(trail) > 0 {
+			return trail
+		}
+	}
+
+	var items []breadcrumb
+	doc.Find("[itemtype*='BreadcrumbList'] [itemprop='itemListElement']").Each(func(i int, s *goquery.Selection) {
+		nameSel := s.Find("[itemprop='name']").First()
+		name := getAttrContent(nameSel, "content")
+		if name == "" {
+			name = nameSel.Text()
+		}
+		position, err := strconv.Atoi(strings.TrimSpace(getAttrContent(s.Find("[itemprop='position']").First(), "content")))
+		if err != nil {
+			position = i + 1
+		}
+		items = append(items, breadcrumb{name: name, position: position})
+	})
+	if trail := sortBreadcrumbs(items); len(trail) > 0 {
+		return trail
+	}
+
+	items = nil
+	doc.Find(".breadcrumb a, .breadcrumbs a, nav[aria-label='breadcrumb'] a, nav[aria-label='Breadcrumb'] a").Each(func(i int, s *goquery.Selection) {
+		items = append(items, breadcrumb{name: s.Text(), position: i})
+	})
+	return sortBreadcrumbs(items)
+}
+
+// breadcrumb is an entry of a breadcrumb trail
+type breadcrumb struct {
+	name     string
+	position int
+}
+
+// breadcrumbsFromJSONLD returns the names of the itemListElement entries of a
+// BreadcrumbList, ordered by position
+func breadcrumbsFromJSONLD(list map[string]any) []string {
+	elements, _ := list["itemListElement"].([]any)
+	var items []breadcrumb
+	for i, element := range elements {
+		obj, ok := element.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := obj["name"].(string)
+		if item, ok := obj["item"].(map[string]any); ok && name == "" {
+			name, _ = item["name"].(string)
+		}
+		position := i + 1
+		switch v := obj["position"].(type) {
+		case float64:
+			position = int(v)
+		case string:
+			if n, err := strconv.Atoi(v); err == nil {
+				position = n
+			}
+		}
+		items = append(items, breadcrumb{name: name, position: position})
+	}
+	return sortBreadcrumbs(items)
+}
+
+// sortBreadcrumbs orders items by position and returns their non-empty names
+func sortBreadcrumbs(items []breadcrumb) []string {
+	sort.SliceStable(items, func(i, k int) bool { return items[i].position < items[k].position })
+	var trail []string
+	for _, item := range items {
+		if name := parsers.InnerTrim(item.name); name != "" {
+			trail = append(trail, name)
+		}
+	}
+	return trail
+}
+
+// partOfSkippedTypes are the isPartOf targets that are the page or the site
+// the article is published on rather than a series
+var partOfSkippedTypes = []string{"WebPage", "WebSite", "ItemPage", "CollectionPage"}
+
+// getPartOf returns the name and URL of the series or collection the article
+// belongs to, from the isPartOf property of the JSON-LD objects or else the
+// article:series meta tag. Targets only given by their @id are looked up in
+// the @graph, and web pages and sites are ignored.
+func (me *MetadataExtractor) getPartOf(articleURL string, doc *goquery.Document, candidates []map[string]any) (string, string) {
+	byID := map[string]map[string]any{}
+	for _, obj := range candidates {
+		if id, ok := obj["@id"].(string); ok {
+			byID[id] = obj
+		}
+	}
+
+	for _, obj := range candidates {
+		targets, ok := obj["isPartOf"].([]any)
+		if !ok {
+			targets = []any{obj["isPartOf"]}
+		}
+		for _, target := range targets {
+			series, ok := target.(map[string]any)
+			if !ok {
+				continue
+			}
+			if id, ok := series["@id"].(string); ok && series["name"] == nil && byID[id] != nil {
+				series = byID[id]
+			}
+			if slices.ContainsFunc(partOfSkippedTypes, func(typ string) bool { return hasJSONLDType(series, typ) }) {
+				continue
+			}
+			name, _ := series["name"].(string)
+			if name = parsers.InnerTrim(parsers.UnescapeText(name)); name == "" {
+				continue
+			}
+			seriesURL, _ := series["url"].(string)
+			if seriesURL = strings.TrimSpace(seriesURL); seriesURL != "" {
+				seriesURL = urls.JoinURL(articleURL, seriesURL)
+			}
+			return name, seriesURL
+		}
+	}
+
+	return me.getMetaField(doc, "article:series"), ""
+}
+
+// getRating extracts the rating of the page from JSON-LD: the reviewRating
+// of a Review, then the aggregateRating of any object, e.g. a Product, then
+// a standalone AggregateRating. It returns nil if there is none.
+func (me *MetadataExtractor) getRating(candidates []map[string]any) *newspaper.RatingData {
+
+	for _, obj := range candidates {
+		if !hasJSONLDType(obj, "Review") {
+			continue
+		}
+		if rating := ratingFromJSONLD(obj["reviewRating"]); rating != nil {
+			return rating
+		}
+	}
+	for _, obj := range candidates {
+		if rating := ratingFromJSONLD(obj["aggregateRating"]); rating != nil {
+			return rating
+		}
+		if item, ok := obj["itemReviewed"].(map[string]any); ok {
+			if rating := ratingFromJSONLD(item["aggregateRating"]); rating != nil {
+				return rating
+			}
+		}
+	}
+	for _, obj := range candidates {
+		if hasJSONLDType(obj, "AggregateRating") {
+			if rating := ratingFromJSONLD(obj); rating != nil {
+				return rating
+			}
+		}
+	}
+	return nil
+}
+
+// ratingFromJSONLD converts a Rating or AggregateRating object, nil if it has
+// no ratingValue
+func ratingFromJSONLD(value any) *newspaper.RatingData {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	ratingValue, ok := jsonLDNumber(obj["ratingValue"])
+	if !ok {
+		return nil
+	}
+	rating := &newspaper.RatingData{Value: ratingValue}
+	rating.Best, _ = jsonLDNumber(obj["bestRating"])
+	rating.Worst, _ = jsonLDNumber(obj["worstRating"])
+	count, ok := jsonLDNumber(obj["ratingCount"])
+	if !ok {
+		count, _ = jsonLDNumber(obj["reviewCount"])
+	}
+	rating.Count = int(count)
+	return rating
+}
+
+// jsonLDNumber reads a JSON-LD number, which publishers often write as a string
+func jsonLDNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// checkJSONLDSizes reports the JSON-LD blocks that the other extractors skip
+// because they exceed Config.MaxJSONLDBytes
+func (me *MetadataExtractor) checkJSONLDSizes(blocks []string) []string {
+	limit := maxJSONLDBytes(me.config)
+	if limit <= 0 {
+		return nil
+	}
+	var diagnostics []string
+	for _, block := range blocks {
+		if size := len(block); size > limit {
+			diagnostics = append(diagnostics, fmt.Sprintf("skipped JSON-LD block of %d bytes (MaxJSONLDBytes is %d)", size, limit))
+		}
+	}
+	return diagnostics
+}
+
+// maxJSONLDBytes returns the JSON-LD size limit of config, 0 meaning no limit
+func maxJSONLDBytes(config *configuration.Configuration) int {
+	if config == nil {
+		return 0
+	}
+	return config.MaxJSONLDBytes
+}
+
 // getAttrContent returns the value of attr on sel or an empty string if missing.
 func getAttrContent(sel *goquery.Selection, attr string) string {
 	if sel == nil || sel.Length() == 0 {
@@ -7,7 +7,7 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/araddon/dateparse"
+	"github.com/tguidoux/newspaper4k-go/internal/dateparse"
 	"github.com/tguidoux/newspaper4k-go/internal/parsers"
 	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
 	"github.com/tguidoux/newspaper4k-go/pkg/constants"
@@ -24,6 +24,7 @@ type DateMatch struct {
 type PubdateExtractor struct {
 	config  *configuration.Configuration
 	pubdate *time.Time
+	parser  *dateparse.Parser
 }
 
 // NewPubdateExtractor creates a new PubdateExtractor.
@@ -36,6 +37,7 @@ func NewPubdateExtractor(config *configuration.Configuration) *PubdateExtractor
 // Parse extracts the publication date and updates the article in-place
 func (p *PubdateExtractor) Parse(a *newspaper.Article) error {
 	p.pubdate = nil
+	p.parser = dateParser(p.config)
 
 	if a.Doc == nil {
 		doc, err := parsers.FromString(a.HTML)
@@ -46,24 +48,14 @@ func (p *PubdateExtractor) Parse(a *newspaper.Article) error {
 	}
 
 	// Call the existing parsing logic
-	pubdate := p.parseWithDoc(a.URL, a.Doc)
+	pubdate := p.parseWithDoc(a.URL, a.Doc, a.DublinCore, a.JSONLD(maxJSONLDBytes(p.config)))
 	a.PublishDate = pubdate
 	return nil
 }
 
 // parseWithDoc extracts the publication date using multiple strategies.
-func (p *PubdateExtractor) parseWithDoc(articleURL string, doc *goquery.Document) *time.Time {
-	// Helper function to parse date string
-	parseDateStr := func(dateStr string) *time.Time {
-		if dateStr == "" {
-			return nil
-		}
-		t, err := dateparse.ParseAny(dateStr)
-		if err != nil {
-			return nil
-		}
-		return &t
-	}
+func (p *PubdateExtractor) parseWithDoc(articleURL string, doc *goquery.Document, dublinCore map[string]string, jsonLD *parsers.LdJson) *time.Time {
+	parseDateStr := p.parseDateStr
 
 	var dateMatches []DateMatch
 
@@ -75,10 +67,14 @@ func (p *PubdateExtractor) parseWithDoc(articleURL string, doc *goquery.Document
 		}
 	}
 
-	// Strategy 2: Pubdate from JSON-LD or structured data using parser
-	jsonObjects := parsers.GetLdJsonObject(doc.Selection)
-	for _, jsonData := range jsonObjects {
-		dateMatches = p.extractDateFromJSON(jsonData, dateMatches)
+	// Strategy 2: Pubdate from JSON-LD, the members of a @graph scoring higher
+	for _, obj := range jsonLD.Objects {
+		if _, ok := obj["@graph"]; !ok {
+			dateMatches = p.extractDateFromMap(obj, dateMatches, 9)
+		}
+	}
+	for _, obj := range jsonLD.Members {
+		dateMatches = p.extractDateFromMap(obj, dateMatches, 10)
 	}
 
 	// Strategy 3: Pubdate from <time> tags
@@ -117,6 +113,30 @@ func (p *PubdateExtractor) parseWithDoc(articleURL string, doc *goquery.Document
 		}
 	}
 
+	// Strategy 5: Pubdate from Dublin Core and eprints tags, whatever their case
+	for _, key := range constants.DUBLIN_CORE_DATE_KEYS {
+		if dt := parseDateStr(dublinCore[key]); dt != nil {
+			dateMatches = append(dateMatches, DateMatch{date: *dt, score: 7})
+			break
+		}
+	}
+
+	// Strategy 6: Pubdate from microdata and date elements of the body
+	for _, tag := range constants.PUBLISH_DATE_TAGS {
+		for _, el := range parsers.GetTags(doc.Selection, "", map[string]string{tag.Attribute: tag.Value}, "word", false) {
+			if goquery.NodeName(el) == "meta" {
+				continue // Handled with the meta tags
+			}
+			if dt := parseDateStr(publishDateTagValue(el, tag)); dt != nil {
+				score := 6
+				if tag.Attribute == "itemprop" {
+					score = 8
+				}
+				dateMatches = append(dateMatches, DateMatch{date: *dt, score: score})
+			}
+		}
+	}
+
 	// Sort by score descending
 	sort.Slice(dateMatches, func(i, j int) bool {
 		return dateMatches[i].score > dateMatches[j].score
@@ -129,29 +149,25 @@ func (p *PubdateExtractor) parseWithDoc(articleURL string, doc *goquery.Document
 	return nil
 }
 
-// extractDateFromJSON extracts dates from JSON-LD data
-func (p *PubdateExtractor) extractDateFromJSON(data any, dateMatches []DateMatch) []DateMatch {
-	switch v := data.(type) {
-	case map[string]any:
-		if graph, ok := v["@graph"]; ok {
-			if graphSlice, ok := graph.([]any); ok {
-				for _, item := range graphSlice {
-					if itemMap, ok := item.(map[string]any); ok {
-						dateMatches = p.extractDateFromMap(itemMap, dateMatches, 10)
-					}
-				}
-			}
-		} else {
-			dateMatches = p.extractDateFromMap(v, dateMatches, 9)
-		}
-	case []any:
-		for _, item := range v {
-			if itemMap, ok := item.(map[string]any); ok {
-				dateMatches = p.extractDateFromMap(itemMap, dateMatches, 9)
-			}
+// maxPublishDateTextLength bounds the text of a microdata element read as a date
+const maxPublishDateTextLength = 40
+
+// publishDateTagValue returns the date of an element matching tag: its
+// tag.Content attribute, else its datetime or content attribute, else the
+// short text of a microdata element (<span itemprop="datePublished">)
+func publishDateTagValue(el *goquery.Selection, tag constants.PublishDateTag) string {
+	for _, attr := range []string{tag.Content, "datetime", "content"} {
+		if value := strings.TrimSpace(el.AttrOr(attr, "")); value != "" {
+			return value
 		}
 	}
-	return dateMatches
+	if tag.Attribute != "itemprop" {
+		return ""
+	}
+	if text := strings.TrimSpace(el.Text()); len(text) <= maxPublishDateTextLength {
+		return text
+	}
+	return ""
 }
 
 // extractDateFromMap extracts dates from a map
@@ -173,9 +189,20 @@ func (p *PubdateExtractor) parseDateStr(dateStr string) *time.Time {
 	if dateStr == "" {
 		return nil
 	}
-	t, err := dateparse.ParseAny(dateStr)
+	if p.parser == nil {
+		p.parser = dateParser(p.config)
+	}
+	t, err := p.parser.Parse(dateStr)
 	if err != nil {
 		return nil
 	}
 	return &t
 }
+
+// dateParser returns the date parser trying the extra layouts of config
+func dateParser(config *configuration.Configuration) *dateparse.Parser {
+	if config == nil {
+		return dateparse.New()
+	}
+	return dateparse.New(config.ExtraDateLayouts...)
+}
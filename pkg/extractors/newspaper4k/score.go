@@ -0,0 +1,553 @@
+package newspaper4k
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/nlp"
+	"github.com/tguidoux/newspaper4k-go/internal/parsers"
+	"github.com/tguidoux/newspaper4k-go/pkg/constants"
+	"golang.org/x/net/html"
+)
+
+// NodeFeatures holds the values computed for a single node by the gravity model
+type NodeFeatures struct {
+	StopWords       int     // Stop words owned by the node (descendants excluded)
+	WordCount       int     // Words owned by the node (descendants excluded)
+	HighLinkDensity bool    // True if the node is mostly made of links
+	Level           int     // Depth of the node in the document
+	GravityScore    float64 // Accumulated gravity score
+	GravityNodes    int     // Number of scored nodes that contributed to the score
+}
+
+// ScoredNode is a candidate parent node together with its gravity score
+type ScoredNode struct {
+	Node  *goquery.Selection
+	Score float64
+}
+
+// BodyScore is the result of scoring a document with the gravity model.
+// The document itself is left untouched: all computed values live in Features.
+type BodyScore struct {
+	TopNode    *goquery.Selection           // Best candidate for the article body
+	TopScore   float64                      // Gravity score of TopNode
+	Candidates []ScoredNode                 // Candidate parents, highest score first
+	Features   map[*html.Node]*NodeFeatures // Per-node feature values
+}
+
+// FeaturesOf returns the features computed for node, or nil if the node was not scored
+func (bs *BodyScore) FeaturesOf(node *goquery.Selection) *NodeFeatures {
+	if bs == nil || node == nil || node.Length() == 0 {
+		return nil
+	}
+	return bs.Features[node.Get(0)]
+}
+
+// ScoreDocument runs the BodyExtractor gravity model on doc using the stop words of lang
+// and returns the chosen top node, the ranked candidates and the per-node features.
+func ScoreDocument(doc *goquery.Document, lang string) (*BodyScore, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("nil document")
+	}
+	sw, err := nlp.NewStopWords(lang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stop words for %q: %w", lang, err)
+	}
+	return newBodyScorer(sw, "").score(doc), nil
+}
+
+// bodyScorer holds the state of a single gravity scoring pass
+type bodyScorer struct {
+	stopwords    *nlp.StopWords
+	linkLanguage string
+	features     map[*html.Node]*NodeFeatures
+	scored       map[*html.Node]bool
+}
+
+func newBodyScorer(stopwords *nlp.StopWords, linkLanguage string) *bodyScorer {
+	return &bodyScorer{
+		stopwords:    stopwords,
+		linkLanguage: linkLanguage,
+		features:     map[*html.Node]*NodeFeatures{},
+		scored:       map[*html.Node]bool{},
+	}
+}
+
+// score finds the best node representing the article body
+func (bs *bodyScorer) score(doc *goquery.Document) *BodyScore {
+	result := &BodyScore{Features: bs.features}
+
+	bs.boostHighlyLikelyNodes(doc)
+
+	nodesWithText := bs.computeFeatures(doc)
+
+	// sort nodes by level (deepest first)
+	sort.SliceStable(nodesWithText, func(i, j int) bool {
+		return bs.level(nodesWithText[i]) > bs.level(nodesWithText[j])
+	})
+
+	parentNodes := bs.computeGravityScores(nodesWithText)
+
+	sort.SliceStable(parentNodes, func(i, j int) bool {
+		return bs.gravityScore(parentNodes[i]) > bs.gravityScore(parentNodes[j])
+	})
+	for _, p := range parentNodes {
+		result.Candidates = append(result.Candidates, ScoredNode{Node: p, Score: bs.gravityScore(p)})
+	}
+
+	if len(parentNodes) > 0 {
+		result.TopNode = parentNodes[0]
+		result.TopScore = bs.gravityScore(parentNodes[0])
+	}
+
+	// Fallback: if we couldn't find a suitable top node, use the document body
+	if result.TopNode == nil {
+		body := doc.Find("body").First()
+		if body.Length() > 0 {
+			result.TopNode = body
+		}
+	}
+
+	return result
+}
+
+// featuresFor returns the features of node, creating them if needed
+func (bs *bodyScorer) featuresFor(node *goquery.Selection) *NodeFeatures {
+	n := node.Get(0)
+	f, ok := bs.features[n]
+	if !ok {
+		f = &NodeFeatures{}
+		bs.features[n] = f
+	}
+	return f
+}
+
+// lookup returns the features of node without creating them
+func (bs *bodyScorer) lookup(node *goquery.Selection) *NodeFeatures {
+	if node == nil || node.Length() == 0 {
+		return nil
+	}
+	return bs.features[node.Get(0)]
+}
+
+func (bs *bodyScorer) stopWordCount(node *goquery.Selection) int {
+	if f := bs.lookup(node); f != nil {
+		return f.StopWords
+	}
+	return 0
+}
+
+func (bs *bodyScorer) gravityScore(node *goquery.Selection) float64 {
+	if f := bs.lookup(node); f != nil {
+		return f.GravityScore
+	}
+	return 0
+}
+
+func (bs *bodyScorer) level(node *goquery.Selection) int {
+	if f := bs.lookup(node); f != nil {
+		return f.Level
+	}
+	return parsers.GetLevel(node)
+}
+
+// computeGravityScores propagates scores up to parents and grandparents
+func (bs *bodyScorer) computeGravityScores(nodesWithText []*goquery.Selection) []*goquery.Selection {
+	var parents []*goquery.Selection
+	seen := map[*html.Node]bool{}
+	addParent := func(node *goquery.Selection) {
+		if node.Length() == 0 || seen[node.Get(0)] {
+			return
+		}
+		seen[node.Get(0)] = true
+		parents = append(parents, node)
+	}
+
+	nodesCount := len(nodesWithText)
+	negativeScoring := 0.0
+	bottomNegNodes := float64(nodesCount) * scoreWeights.bottomNegativeScoreNodes
+	boostDiscount := 1.0
+
+	for i, node := range nodesWithText {
+		boostScore := 0.0
+		if bs.isBoostable(node) {
+			boostScore = scoreWeights.boostScore / boostDiscount
+			boostDiscount += 1.0
+		}
+
+		if nodesCount > scoreWeights.nodeCountThreshold {
+			distFromEnd := float64(nodesCount - i)
+			if distFromEnd <= bottomNegNodes {
+				booster := bottomNegNodes - distFromEnd
+				boostScore = -(booster * booster)
+				negscore := boostScore
+				if negscore < 0 {
+					negscore = -negscore
+				}
+				negscore = negscore + negativeScoring
+				if negscore > scoreWeights.negativeScoreThreshold {
+					boostScore = scoreWeights.negativeScoreBoost
+				}
+			}
+		}
+
+		upscore := float64(bs.stopWordCount(node)) + boostScore
+
+		parent := node.Parent()
+		bs.updateScore(parent, upscore)
+		bs.updateNodeCount(parent, 1)
+		addParent(parent)
+
+		parentParent := parent.Parent()
+		bs.updateNodeCount(parentParent, 1)
+		bs.updateScore(parentParent, upscore*scoreWeights.parentParentNode)
+		addParent(parentParent)
+	}
+
+	return parents
+}
+
+// computeFeatures finds candidate nodes with reasonable text
+func (bs *bodyScorer) computeFeatures(doc *goquery.Document) []*goquery.Selection {
+	candidates := []*goquery.Selection{}
+
+	nodes := bs.nodesToCheck(doc)
+	levels := make(map[*html.Node]int, len(nodes))
+	for _, node := range nodes {
+		levels[node.Get(0)] = parsers.GetLevel(node)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return levels[nodes[i].Get(0)] > levels[nodes[j].Get(0)] })
+
+	for _, node := range nodes {
+		text := parsers.GetText(node)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		// count stopwords and words
+		stopCount, wordCount := bs.getStopwordStats(text)
+		highLink := parsers.IsHighlinkDensity(node, bs.linkLanguage)
+
+		// compute stats of already scored descendants
+		childrenStop := 0
+		childrenWords := 0
+		node.Find("*").Each(func(i int, s *goquery.Selection) {
+			if !bs.scored[s.Get(0)] {
+				return
+			}
+			f := bs.features[s.Get(0)]
+			if f.StopWords > 0 {
+				childrenStop += f.StopWords
+			}
+			childrenWords += f.WordCount
+		})
+
+		f := bs.featuresFor(node)
+		f.StopWords = stopCount - childrenStop
+		f.WordCount = wordCount - childrenWords
+		f.HighLinkDensity = highLink
+		f.Level = levels[node.Get(0)]
+		bs.scored[node.Get(0)] = true
+
+		if stopCount > 2 && !highLink {
+			candidates = append(candidates, node)
+		}
+	}
+
+	return candidates
+}
+
+// nodesToCheck returns candidate nodes to inspect
+func (bs *bodyScorer) nodesToCheck(doc *goquery.Document) []*goquery.Selection {
+	var nodes []*goquery.Selection
+	seen := map[*html.Node]bool{}
+	add := func(items ...*goquery.Selection) {
+		for _, it := range items {
+			if it.Length() == 0 || seen[it.Get(0)] {
+				continue
+			}
+			seen[it.Get(0)] = true
+			nodes = append(nodes, it)
+		}
+	}
+
+	tags := []string{"p", "pre", "td", "article", "div"}
+	for _, tag := range tags {
+		if tag == "div" {
+			items := []*goquery.Selection{}
+			for _, attr := range []string{"articlebody", "article", "story"} {
+				items = append(items, parsers.GetTags(doc.Selection, tag, map[string]string{"id": attr}, "word", true)...)    // id
+				items = append(items, parsers.GetTags(doc.Selection, tag, map[string]string{"class": attr}, "word", true)...) // class
+			}
+			items = append(items, parsers.GetTagsRegex(doc.Selection, tag, map[string]string{"class": "paragraph"})...)
+			if len(items) == 0 && len(nodes) < 5 {
+				items = parsers.GetTags(doc.Selection, tag, nil, "exact", false)
+			}
+			add(items...)
+		} else {
+			add(parsers.GetTags(doc.Selection, tag, nil, "exact", false)...)
+		}
+	}
+
+	// Do not miss some Article Bodies or Article Sections
+	for _, itemprop := range []string{"articleBody", "articlebody", "articleText", "articleSection"} {
+		add(parsers.GetTags(doc.Selection, "", map[string]string{"itemprop": itemprop}, "word", false)...)
+	}
+
+	return nodes
+}
+
+// isBoostable checks whether node should be boosted
+func (bs *bodyScorer) isBoostable(node *goquery.Selection) bool {
+	for _, s := range walkSiblings(node, scoreWeights.boostMaxStepsFromNode) {
+		if s == nil || s.Length() == 0 {
+			continue
+		}
+		if s.Get(0).Data != node.Get(0).Data {
+			continue
+		}
+		if bs.stopWordCount(s) > scoreWeights.boostMinStopwordCount {
+			return true
+		}
+	}
+	return false
+}
+
+// boostHighlyLikelyNodes biases nodes that look like article containers
+func (bs *bodyScorer) boostHighlyLikelyNodes(doc *goquery.Document) {
+	candidates := []*goquery.Selection{}
+	for _, tag := range []string{"p", "pre", "td", "article", "div"} {
+		candidates = append(candidates, parsers.GetTags(doc.Selection, tag, nil, "exact", false)...)
+	}
+
+	for _, e := range candidates {
+		boost := isHighlyLikely(e)
+		if boost > 0 {
+			bs.updateScore(e, boost*scoreWeights.parentNode)
+		}
+	}
+}
+
+// isHighlyLikely checks tag patterns against ARTICLE_BODY_TAGS
+func isHighlyLikely(node *goquery.Selection) float64 {
+	// helper to match tag dict
+	match := func(node *goquery.Selection, tag constants.ArticleBodyTag) bool {
+		if node.Length() == 0 {
+			return false
+		}
+		ntag := node.Get(0).Data
+		if tag.Tag != "" && ntag != tag.Tag {
+			return false
+		}
+		// check attributes
+		for k, v := range map[string]string{"class": tag.Class, "itemprop": tag.Itemprop, "itemtype": tag.Itemtype, "role": tag.Role} {
+			if v == "" {
+				continue
+			}
+			val, _ := node.Attr(k)
+			if strings.HasPrefix(v, "re:") {
+				pattern := v[3:]
+				if !strings.Contains(strings.ToLower(val), strings.ToLower(pattern)) {
+					return false
+				}
+			} else {
+				if !strings.EqualFold(val, v) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	best := 0
+	for _, t := range constants.ARTICLE_BODY_TAGS {
+		if match(node, t) {
+			if t.ScoreBoost > best {
+				best = t.ScoreBoost
+			}
+		}
+	}
+	return float64(best)
+}
+
+// updateScore adds to the gravity score of node
+func (bs *bodyScorer) updateScore(node *goquery.Selection, add float64) {
+	if node == nil || node.Length() == 0 {
+		return
+	}
+	bs.featuresFor(node).GravityScore += add
+}
+
+// updateNodeCount increases the gravity nodes counter of node
+func (bs *bodyScorer) updateNodeCount(node *goquery.Selection, add int) {
+	if node == nil || node.Length() == 0 {
+		return
+	}
+	bs.featuresFor(node).GravityNodes += add
+}
+
+// walkSiblings returns up to maxSteps preceding and maxSteps following
+// siblings interleaved, nearest first, so that the first paragraphs of a body
+// can be boosted too
+func walkSiblings(node *goquery.Selection, maxSteps int) []*goquery.Selection {
+	var res []*goquery.Selection
+	if node == nil || node.Length() == 0 {
+		return res
+	}
+	prev, next := node.Prev(), node.Next()
+	for step := 0; step < maxSteps && (prev.Length() > 0 || next.Length() > 0); step++ {
+		if prev.Length() > 0 {
+			res = append(res, prev)
+			prev = prev.Prev()
+		}
+		if next.Length() > 0 {
+			res = append(res, next)
+			next = next.Next()
+		}
+	}
+	return res
+}
+
+// complementWithSiblings builds an off-tree node composed of candidates at same level
+func (bs *bodyScorer) complementWithSiblings(doc *goquery.Document, node *goquery.Selection) *goquery.Selection {
+	if node == nil || node.Length() == 0 {
+		return node
+	}
+	// If the chosen node is already the body or an article element,
+	// return it directly to preserve its text and avoid cloning issues.
+	if node.Get(0) != nil {
+		tag := node.Get(0).Data
+		if tag == "body" || tag == "article" {
+			return node
+		}
+	}
+	level := parsers.GetLevel(node)
+	candidates := parsers.GetNodesAtLevel(doc.Children(), level)
+
+	// create a new document body
+	newDoc, _ := parsers.FromString("<html><body></body></html>")
+	body := newDoc.Find("body").First()
+
+	// Prefer a normalized baseline score computed from paragraph children
+	baseScore := bs.getNormalizedScore(node)
+	if math.IsInf(baseScore, 1) {
+		// fallback to node gravity score
+		baseScore = bs.gravityScore(node)
+	}
+
+	for _, n := range candidates {
+		if n.Get(0) == node.Get(0) {
+			body.AppendSelection(n.Clone())
+			continue
+		}
+		// only merge nodes of the same tag type (eg. div with div, article with article)
+		if n.Get(0) == nil || node.Get(0) == nil {
+			continue
+		}
+		if n.Get(0).Data != node.Get(0).Data {
+			continue
+		}
+
+		// accept nodes with comparable gravity score and low link density
+		score := bs.gravityScore(n)
+		if score > baseScore*0.3 && !parsers.IsHighlinkDensity(n, bs.linkLanguage) {
+			body.AppendSelection(n.Clone())
+			continue
+		}
+
+		// try to salvage plausible paragraphs from this sibling node
+		ps := bs.getPlausibleContent(n, baseScore)
+		for _, p := range ps {
+			if p != nil {
+				body.AppendSelection(p)
+			}
+		}
+	}
+
+	return body
+}
+
+// getNormalizedScore returns the average positive gravity score of paragraph children
+func (bs *bodyScorer) getNormalizedScore(top *goquery.Selection) float64 {
+	if top == nil || top.Length() == 0 {
+		return math.Inf(1)
+	}
+	var scores []float64
+	top.Find("p").Each(func(i int, s *goquery.Selection) {
+		sc := bs.gravityScore(s)
+		if sc > 0 {
+			scores = append(scores, sc)
+		}
+	})
+	if len(scores) == 0 {
+		return math.Inf(1)
+	}
+	sum := 0.0
+	for _, v := range scores {
+		sum += v
+	}
+	return sum / float64(len(scores))
+}
+
+// getPlausibleContent extracts paragraphs from a candidate sibling node that
+// look like they belong to the article based on stopword counts and link density.
+func (bs *bodyScorer) getPlausibleContent(node *goquery.Selection, baseline float64) []*goquery.Selection {
+	var res []*goquery.Selection
+	if node == nil || node.Length() == 0 {
+		return res
+	}
+
+	// if this node itself is a paragraph, consider it directly
+	if node.Get(0) != nil && node.Get(0).Data == "p" {
+		txt := parsers.GetText(node)
+		if strings.TrimSpace(txt) != "" && !parsers.IsHighlinkDensity(node, bs.linkLanguage) {
+			// clone paragraph for safety
+			res = append(res, node.Clone())
+		}
+		return res
+	}
+
+	// otherwise inspect paragraph children
+	node.Find("p").Each(func(i int, s *goquery.Selection) {
+		stopCount := bs.stopWordCount(s)
+		if stopCount <= 0 {
+			return
+		}
+		if parsers.IsHighlinkDensity(s, bs.linkLanguage) {
+			return
+		}
+		// baseline can be Inf when not available -> use node gravity score
+		base := baseline
+		if math.IsInf(base, 1) {
+			base = bs.gravityScore(node)
+		}
+		weight := 0.3
+		if float64(stopCount) > base*weight {
+			// create a new paragraph element containing only the text
+			el := parsers.CreateElement("p", parsers.GetText(s), "")
+			if el != nil {
+				res = append(res, el)
+			}
+		}
+	})
+
+	return res
+}
+
+// getStopwordStats returns stop word count and word count for text
+func (bs *bodyScorer) getStopwordStats(text string) (int, int) {
+	if bs.stopwords == nil {
+		return 0, 0
+	}
+	tokens := bs.stopwords.Tokenize(text)
+	stopCount := 0
+	for _, t := range tokens {
+		if bs.stopwords.StopWords[strings.ToLower(t)] {
+			stopCount++
+		}
+	}
+	return stopCount, len(tokens)
+}
@@ -0,0 +1,86 @@
+package newspaper4k
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/parsers"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+// TableExtractor extracts data tables from the article body
+type TableExtractor struct {
+	config *configuration.Configuration
+}
+
+// NewTableExtractor creates a new TableExtractor
+func NewTableExtractor(config *configuration.Configuration) *TableExtractor {
+	return &TableExtractor{config: config}
+}
+
+// Parse extracts the tables of the article top node when Config.ExtractTables is set
+func (te *TableExtractor) Parse(a *newspaper.Article) error {
+	if te.config == nil || !te.config.ExtractTables {
+		return nil
+	}
+	if a.Doc == nil {
+		doc, err := parsers.FromString(a.HTML)
+		if err != nil {
+			return err
+		}
+		a.Doc = doc
+	}
+
+	root := a.TopNode
+	if root == nil || root.Length() == 0 {
+		root = a.Doc.Selection
+	}
+
+	var tables []newspaper.Table
+	root.Find("table").Each(func(i int, table *goquery.Selection) {
+		if t, ok := te.parseTable(table); ok {
+			tables = append(tables, t)
+		}
+	})
+	if len(tables) > 0 {
+		a.Tables = tables
+	}
+	return nil
+}
+
+// parseTable converts a table element into headers and rows, ignoring nested tables
+func (te *TableExtractor) parseTable(table *goquery.Selection) (newspaper.Table, bool) {
+	var t newspaper.Table
+
+	table.Find("tr").Each(func(i int, row *goquery.Selection) {
+		if row.Closest("table").Get(0) != table.Get(0) {
+			return
+		}
+
+		var cells []string
+		allHeaders := true
+		row.Children().Each(func(j int, cell *goquery.Selection) {
+			switch goquery.NodeName(cell) {
+			case "th":
+			case "td":
+				allHeaders = false
+			default:
+				return
+			}
+			cells = append(cells, strings.Join(strings.Fields(cell.Text()), " "))
+		})
+		if len(cells) == 0 {
+			return
+		}
+
+		inHead := goquery.NodeName(row.Parent()) == "thead"
+		if t.Headers == nil && len(t.Rows) == 0 && (inHead || allHeaders) {
+			t.Headers = cells
+			return
+		}
+		t.Rows = append(t.Rows, cells)
+	})
+
+	return t, len(t.Headers) > 0 || len(t.Rows) > 0
+}
@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/tguidoux/newspaper4k-go/internal/languages"
@@ -40,19 +41,26 @@ func (te *TitleExtractor) Parse(a *newspaper.Article) error {
 		a.Doc = doc
 	}
 
-	titleElement := a.Doc.Find("title").First()
-	if titleElement.Length() == 0 {
-		return nil
-	}
-
-	titleText := strings.TrimSpace(titleElement.Text())
+	titleText := parsers.UnescapeText(strings.TrimSpace(a.Doc.Find("title").First().Text()))
 	usedDelimiter := false
 
 	// title from h1
-	titleTextH1 := te.getTitleFromH1(a.Doc)
+	titleTextH1 := parsers.UnescapeText(te.getTitleFromH1(a.Doc))
 
 	// title from og:title and similar meta tags
 	titleTextFB := te.getTitleFromMeta(a.Doc)
+	if titleTextFB == "" {
+		titleTextFB = dublinCoreValue(a.DublinCore, constants.DUBLIN_CORE_TITLE_KEYS...)
+	}
+	titleTextFB = parsers.UnescapeText(titleTextFB)
+
+	// without <title>, start from the other candidates
+	if titleText == "" {
+		titleText = te.getFallbackTitle(a.Doc, a.JSONLD(maxJSONLDBytes(te.config)).Graph, titleTextFB)
+		if titleText == "" {
+			return nil
+		}
+	}
 
 	// create filtered versions for comparison
 	langTag := a.GetLanguage()
@@ -114,6 +122,16 @@ func (te *TitleExtractor) Parse(a *newspaper.Article) error {
 	}
 
 	te.title = strings.TrimSpace(title)
+	a.RawTitle = te.title
+
+	if te.config != nil && te.config.NormalizeTitle {
+		prefixes := te.config.TitleLabelPrefixes
+		if len(prefixes) == 0 {
+			prefixes = constants.TITLE_LABEL_PREFIXES
+		}
+		base, _ := langTag.Base()
+		te.title = normalizeTitle(te.title, base.String(), prefixes, documentAcronyms(a.Doc))
+	}
 	a.Title = te.title
 
 	return nil
@@ -150,6 +168,20 @@ func (te *TitleExtractor) getTitleFromH1(doc *goquery.Document) string {
 	return strings.Join(words, " ")
 }
 
+// getFallbackTitle returns the title candidate used when the document has no
+// <title>: the meta title, the JSON-LD headline or the text of the first h1
+func (te *TitleExtractor) getFallbackTitle(doc *goquery.Document, jsonLD []map[string]any, metaTitle string) string {
+	if metaTitle != "" {
+		return metaTitle
+	}
+	for _, data := range jsonLD {
+		if headline, ok := data["headline"].(string); ok && strings.TrimSpace(headline) != "" {
+			return strings.TrimSpace(headline)
+		}
+	}
+	return strings.Join(strings.Fields(doc.Find("h1").First().Text()), " ")
+}
+
 // getTitleFromMeta extracts title from meta tags
 func (te *TitleExtractor) getTitleFromMeta(doc *goquery.Document) string {
 	for _, metaName := range constants.TITLE_META_INFO {
@@ -195,3 +227,132 @@ func (te *TitleExtractor) splitTitle(title, delimiter, hint string) string {
 	result := pieces[largestIndex]
 	return strings.ReplaceAll(result, constants.TITLE_REPLACEMENTS[0], constants.TITLE_REPLACEMENTS[1])
 }
+
+// titleSeparators may follow a label prefix such as "VIDEO." or "LIVE -"
+const titleSeparators = ".:|-–—/"
+
+// titleTrailers are stripped from the end of a normalized title
+const titleTrailers = "…| "
+
+// nounCapitalizingLanguages capitalize every noun, so lowercasing an ALL-CAPS
+// title would lose information
+var nounCapitalizingLanguages = map[string]bool{"de": true, "lb": true}
+
+// normalizeTitle collapses whitespace, strips leading labels followed by a
+// separator, trims trailing ellipses and pipes and turns ALL-CAPS titles
+// into sentence case, keeping the given acronyms
+func normalizeTitle(title, lang string, prefixes []string, acronyms map[string]bool) string {
+	title = strings.Join(strings.Fields(title), " ")
+
+	for stripped := true; stripped; {
+		stripped = false
+		for _, prefix := range prefixes {
+			if rest, ok := stripTitleLabel(title, prefix); ok {
+				title = rest
+				stripped = true
+			}
+		}
+	}
+
+	for {
+		trimmed := strings.TrimSuffix(strings.TrimRight(title, titleTrailers), "...")
+		if trimmed == title {
+			break
+		}
+		title = trimmed
+	}
+
+	if !nounCapitalizingLanguages[lang] && isShoutedTitle(title) {
+		title = sentenceCase(title, acronyms)
+	}
+	return title
+}
+
+// stripTitleLabel removes prefix from the start of title when it is followed
+// by one of the title separators; the match is case-insensitive
+func stripTitleLabel(title, prefix string) (string, bool) {
+	if len(title) <= len(prefix) || !strings.EqualFold(title[:len(prefix)], prefix) {
+		return title, false
+	}
+	rest := strings.TrimLeft(title[len(prefix):], " ")
+	afterSep := strings.TrimLeft(rest, titleSeparators)
+	if len(afterSep) == len(rest) || !strings.HasPrefix(afterSep, " ") {
+		return title, false
+	}
+	if rest = strings.TrimSpace(afterSep); rest == "" {
+		return title, false
+	}
+	return rest, true
+}
+
+// isShoutedTitle reports whether more than 80% of the letters of a
+// Latin-script title are uppercase
+func isShoutedTitle(title string) bool {
+	letters, upper := 0, 0
+	for _, r := range title {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+		letters++
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	return letters > 0 && float64(upper) > 0.8*float64(letters)
+}
+
+// sentenceCase lowercases a shouted title, capitalizing the first word of
+// each sentence. Words of at most 4 letters found in acronyms are left alone.
+func sentenceCase(title string, acronyms map[string]bool) string {
+	words := strings.Fields(title)
+	startOfSentence := true
+	for i, word := range words {
+		core := strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) })
+
+		switch {
+		case acronyms[core]:
+		case startOfSentence:
+			words[i] = capitalizeFirst(strings.ToLower(word))
+		default:
+			words[i] = strings.ToLower(word)
+		}
+		if core != "" {
+			startOfSentence = false
+		}
+		if strings.ContainsAny(word[len(word)-1:], ".!?") {
+			startOfSentence = true
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// documentAcronyms collects the uppercase words of 2 to 4 letters used in the
+// paragraphs of the document. A shouted title gives no hint of which of its
+// words are acronyms, the article text usually does.
+func documentAcronyms(doc *goquery.Document) map[string]bool {
+	acronyms := make(map[string]bool)
+	if doc == nil {
+		return acronyms
+	}
+	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+		for _, word := range strings.FieldsFunc(s.Text(), func(r rune) bool { return !unicode.IsLetter(r) }) {
+			if n := len([]rune(word)); n >= 2 && n <= 4 && word == strings.ToUpper(word) {
+				acronyms[word] = true
+			}
+		}
+	})
+	return acronyms
+}
+
+// capitalizeFirst uppercases the first letter of s
+func capitalizeFirst(s string) string {
+	for i, r := range s {
+		if unicode.IsLetter(r) {
+			return s[:i] + string(unicode.ToUpper(r)) + s[i+len(string(r)):]
+		}
+	}
+	return s
+}
@@ -0,0 +1,278 @@
+package newspaper4k
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/dateparse"
+	"github.com/tguidoux/newspaper4k-go/internal/parsers"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+	"golang.org/x/net/html"
+)
+
+var (
+	// updateHistoryRe matches class/id values of revision history containers
+	updateHistoryRe = regexp.MustCompile(`(?i)update[s]?[-_ ]?(history|log|list)|revision|change[-_ ]?log`)
+	// updatePrefixRe matches the label preceding a timestamp in an entry
+	updatePrefixRe = regexp.MustCompile(`(?i)^\s*(last\s+)?(updated|update|revised|edited)\s*(at|on)?\s*:?\s*`)
+	// updateSeparatorRe splits an entry between its timestamp and its note
+	updateSeparatorRe = regexp.MustCompile(`\s+[—–-]\s+|\s*[—–|]\s*`)
+	// clockRe matches a bare time of day such as 14:02
+	clockRe = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+	// relativeUpdateRe matches an English update banner with a relative time
+	// such as "Updated 3 hours ago" or "Last updated: an hour ago"
+	relativeUpdateRe = regexp.MustCompile(`(?i)^(?:last\s+)?(?:updated|modified)\s*:?\s*(\d{1,3}|an?|one)\s+(minute|min|hour|hr|day|week)s?\s+ago\b`)
+)
+
+// relativeUpdateUnits are the durations of the units of relativeUpdateRe
+var relativeUpdateUnits = map[string]time.Duration{
+	"minute": time.Minute,
+	"min":    time.Minute,
+	"hour":   time.Hour,
+	"hr":     time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+}
+
+// maxRelativeUpdateBanner is the length beyond which an element is body text
+// rather than an update banner
+const maxRelativeUpdateBanner = 80
+
+// UpdateHistoryExtractor extracts the revision history listed by some outlets
+type UpdateHistoryExtractor struct {
+	config *configuration.Configuration
+}
+
+// NewUpdateHistoryExtractor creates a new UpdateHistoryExtractor
+func NewUpdateHistoryExtractor(config *configuration.Configuration) *UpdateHistoryExtractor {
+	return &UpdateHistoryExtractor{config: config}
+}
+
+// Parse extracts the update history of the article
+func (ue *UpdateHistoryExtractor) Parse(a *newspaper.Article) error {
+	if a.Doc == nil {
+		doc, err := parsers.FromString(a.HTML)
+		if err != nil {
+			return err
+		}
+		a.Doc = doc
+	}
+
+	updates := ue.getUpdates(a.Doc, a.PublishDate)
+
+	// Drop a bare entry repeating the modified date of the metadata, read from
+	// the page since Config.MetaDataAllowlist may leave it out of MetaData
+	if modified, err := dateParser(ue.config).Parse(parsers.MetaField(a.Doc.Selection, "article:modified_time")); err == nil {
+		filtered := updates[:0]
+		for _, u := range updates {
+			if u.Note == "" && u.Time.Equal(modified) {
+				continue
+			}
+			filtered = append(filtered, u)
+		}
+		updates = filtered
+	}
+
+	// A single "Updated" label is not a history
+	if len(updates) >= 2 {
+		a.UpdateHistory = updates
+	}
+
+	a.ModifiedDate = ue.getModifiedDate(a)
+	return nil
+}
+
+// getModifiedDate returns the modification date declared by the metadata
+// (meta tags then JSON-LD dateModified) or, failing that, the relative time
+// of an "Updated 2 hours ago" banner resolved against the fetch time
+func (ue *UpdateHistoryExtractor) getModifiedDate(a *newspaper.Article) *time.Time {
+	parser := dateParser(ue.config)
+	if value := parsers.MetaField(a.Doc.Selection, "article:modified_time", "og:updated_time", "dateModified"); value != "" {
+		if t, err := parser.Parse(value); err == nil {
+			return &t
+		}
+	}
+	for _, obj := range a.JSONLD(maxJSONLDBytes(ue.config)).Graph {
+		if value, ok := obj["dateModified"].(string); ok {
+			if t, err := parser.Parse(value); err == nil {
+				return &t
+			}
+		}
+	}
+
+	// A relative time is only meaningful against the time of the download
+	body := a.Doc.Find("body")
+	if a.FetchedAt.IsZero() || body.Length() == 0 {
+		return nil
+	}
+	m := relativeUpdateBanner(body.Nodes[0])
+	if m == nil {
+		return nil
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		n = 1 // a, an or one
+	}
+	t := a.FetchedAt.Add(-time.Duration(n) * relativeUpdateUnits[strings.ToLower(m[2])])
+	return &t
+}
+
+// relativeUpdateBanner returns the submatches of relativeUpdateRe in the text
+// of the first element of body short enough to be an update banner. The text
+// sizes are counted in a single pass, so that the text of the long elements
+// is never built.
+func relativeUpdateBanner(body *html.Node) []string {
+	sizes := map[*html.Node]int{}
+	var count func(n *html.Node) int
+	count = func(n *html.Node) int {
+		if n.Type == html.TextNode {
+			return len(n.Data) - spaceBytes(n.Data)
+		}
+		size := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			size += count(c)
+		}
+		sizes[n] = size
+		return size
+	}
+	count(body)
+
+	var match func(n *html.Node) []string
+	match = func(n *html.Node) []string {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || sizes[c] == 0 {
+				continue
+			}
+			// The size without spaces is a lower bound of the length of the text
+			if sizes[c] <= maxRelativeUpdateBanner {
+				selection := goquery.NewDocumentFromNode(c).Selection
+				if text := strings.Join(strings.Fields(selection.Text()), " "); len(text) <= maxRelativeUpdateBanner {
+					if m := relativeUpdateRe.FindStringSubmatch(text); m != nil {
+						return m
+					}
+				}
+			}
+			if m := match(c); m != nil {
+				return m
+			}
+		}
+		return nil
+	}
+	return match(body)
+}
+
+// spaceBytes returns the number of bytes of the white space characters of s
+func spaceBytes(s string) int {
+	size := 0
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			size += utf8.RuneLen(r)
+		}
+	}
+	return size
+}
+
+// getUpdates collects entries from revision history containers, oldest first
+func (ue *UpdateHistoryExtractor) getUpdates(doc *goquery.Document, publishDate *time.Time) []newspaper.Update {
+	var updates []newspaper.Update
+	seen := map[int64]int{}
+
+	add := func(u newspaper.Update) {
+		key := u.Time.Unix()
+		if i, ok := seen[key]; ok {
+			if updates[i].Note == "" {
+				updates[i].Note = u.Note
+			}
+			return
+		}
+		seen[key] = len(updates)
+		updates = append(updates, u)
+	}
+
+	doc.Find("[class], [id]").Each(func(i int, container *goquery.Selection) {
+		class, _ := container.Attr("class")
+		id, _ := container.Attr("id")
+		if !updateHistoryRe.MatchString(class) && !updateHistoryRe.MatchString(id) {
+			return
+		}
+
+		entries := container.Find("li")
+		if entries.Length() == 0 {
+			entries = container.Find("time").Parent()
+		}
+		entries.Each(func(j int, entry *goquery.Selection) {
+			if u, ok := ue.parseEntry(entry, publishDate); ok {
+				add(u)
+			}
+		})
+	})
+
+	sort.SliceStable(updates, func(i, j int) bool { return updates[i].Time.Before(updates[j].Time) })
+	return updates
+}
+
+// parseEntry reads the timestamp and note of a single history entry
+func (ue *UpdateHistoryExtractor) parseEntry(entry *goquery.Selection, publishDate *time.Time) (newspaper.Update, bool) {
+	parser := dateParser(ue.config)
+	text := strings.Join(strings.Fields(entry.Text()), " ")
+
+	var timestamp time.Time
+	var note string
+
+	if timeEl := entry.Find("time").First(); timeEl.Length() > 0 {
+		timeText := strings.TrimSpace(timeEl.Text())
+		value := timeEl.AttrOr("datetime", timeText)
+		t, ok := parseUpdateTime(parser, value, publishDate)
+		if !ok {
+			return newspaper.Update{}, false
+		}
+		timestamp = t
+		note = strings.Replace(text, strings.Join(strings.Fields(timeText), " "), "", 1)
+		note = updatePrefixRe.ReplaceAllString(note, "")
+	} else {
+		text = updatePrefixRe.ReplaceAllString(text, "")
+		parts := updateSeparatorRe.Split(text, 2)
+		t, ok := parseUpdateTime(parser, parts[0], publishDate)
+		if !ok {
+			return newspaper.Update{}, false
+		}
+		timestamp = t
+		if len(parts) == 2 {
+			note = parts[1]
+		}
+	}
+
+	note = strings.TrimSpace(strings.Trim(strings.TrimSpace(note), "—–-:|"))
+	return newspaper.Update{Time: timestamp, Note: note}, true
+}
+
+// parseUpdateTime parses a full date or a bare time of day on the publish date
+func parseUpdateTime(parser *dateparse.Parser, value string, publishDate *time.Time) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	if m := clockRe.FindStringSubmatch(value); m != nil {
+		if publishDate == nil {
+			return time.Time{}, false
+		}
+		t, err := time.Parse("15:04", m[1]+":"+m[2])
+		if err != nil {
+			return time.Time{}, false
+		}
+		y, mo, d := publishDate.Date()
+		return time.Date(y, mo, d, t.Hour(), t.Minute(), 0, 0, publishDate.Location()), true
+	}
+	t, err := parser.Parse(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
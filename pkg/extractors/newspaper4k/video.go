@@ -1,22 +1,45 @@
 package newspaper4k
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/helpers"
 	"github.com/tguidoux/newspaper4k-go/internal/parsers"
 	"github.com/tguidoux/newspaper4k-go/internal/urls"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
 	"github.com/tguidoux/newspaper4k-go/pkg/constants"
 	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
 )
 
+// maxOEmbedBytes bounds the size of an oEmbed response
+const maxOEmbedBytes = 1 << 20
+
+// adContainerRe matches class/id values of ad wrappers and outstream players
+var adContainerRe = regexp.MustCompile(`(?i)(^|[\s_-])ads?($|[\s_-])|advert|sponsor|outstream|preroll`)
+
+// ampPlayers maps the AMP video player components to the embed URL their
+// data-videoid is appended to
+var ampPlayers = []struct{ tag, embedURL string }{
+	{"amp-youtube", "https://www.youtube.com/embed/"},
+	{"amp-vimeo", "https://player.vimeo.com/video/"},
+	{"amp-dailymotion", "https://www.dailymotion.com/embed/video/"},
+}
+
 // VideoExtractor extracts videos from HTML content
-type VideoExtractor struct{}
+type VideoExtractor struct {
+	config *configuration.Configuration
+}
 
 // NewVideoExtractor creates a new VideoExtractor
-func NewVideoExtractor() *VideoExtractor {
-	return &VideoExtractor{}
+func NewVideoExtractor(config *configuration.Configuration) *VideoExtractor {
+	return &VideoExtractor{config: config}
 }
 
 // Parse extracts videos from the article
@@ -28,103 +51,200 @@ func (ve *VideoExtractor) Parse(a *newspaper.Article) error {
 		}
 		a.Doc = doc
 	}
-	videos := ve.getVideos(a.Doc, a.URL)
+	videos := ve.getVideos(a.Doc, a.URL, a.JSONLD(maxJSONLDBytes(ve.config)).Graph)
 	if len(videos) > 0 {
 		a.Movies = videos
 	}
+	if ve.config != nil && ve.config.ResolveOEmbed {
+		if infos := ve.resolveOEmbed(a.Doc, a.URL, videos); len(infos) > 0 {
+			a.Videos = infos
+		}
+	}
 	return nil
 }
 
+// oEmbedResponse holds the oEmbed fields we use
+type oEmbedResponse struct {
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// resolveOEmbed fetches oEmbed details for the videos of recognized providers
+// and for the page itself when it declares a video oEmbed alternate link
+func (ve *VideoExtractor) resolveOEmbed(doc *goquery.Document, articleURL string, videos []string) []newspaper.VideoInfo {
+	var infos []newspaper.VideoInfo
+
+	for _, videoURL := range videos {
+		provider := ve.getProvider(videoURL)
+		endpoint := ve.config.OEmbedEndpoints[provider]
+		if endpoint == "" {
+			endpoint = constants.OEMBED_ENDPOINTS[provider]
+		}
+		if endpoint == "" {
+			continue
+		}
+		query := url.Values{"url": {videoURL}, "format": {"json"}}
+		separator := "?"
+		if strings.Contains(endpoint, "?") {
+			separator = "&"
+		}
+		if info, ok := ve.fetchOEmbed(endpoint+separator+query.Encode(), videoURL); ok {
+			infos = append(infos, info)
+		}
+	}
+
+	links := parsers.GetTags(doc.Selection, "link", map[string]string{"type": "application/json+oembed"}, "exact", false)
+	for _, link := range links {
+		href := urls.JoinURL(articleURL, link.AttrOr("href", ""))
+		if href == "" {
+			continue
+		}
+		if info, ok := ve.fetchOEmbed(href, articleURL); ok {
+			infos = append(infos, info)
+		}
+	}
+
+	return infos
+}
+
+// fetchOEmbed calls an oEmbed endpoint and converts a video response into a VideoInfo
+func (ve *VideoExtractor) fetchOEmbed(endpoint, videoURL string) (newspaper.VideoInfo, bool) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return newspaper.VideoInfo{}, false
+	}
+	for k, v := range ve.config.RequestsParams.Headers {
+		req.Header.Set(k, v)
+	}
+
+	var client configuration.HTTPClient = helpers.CreateHTTPClient(ve.config.RequestsParams.Timeout, ve.config.RequestsParams.Proxies)
+	if ve.config.HTTPClient != nil {
+		client = ve.config.HTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return newspaper.VideoInfo{}, false
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return newspaper.VideoInfo{}, false
+	}
+
+	var data oEmbedResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxOEmbedBytes)).Decode(&data); err != nil {
+		return newspaper.VideoInfo{}, false
+	}
+	if data.Type != "" && data.Type != "video" {
+		return newspaper.VideoInfo{}, false
+	}
+
+	return newspaper.VideoInfo{
+		URL:          videoURL,
+		Provider:     data.ProviderName,
+		Title:        data.Title,
+		Author:       data.AuthorName,
+		ThumbnailURL: data.ThumbnailURL,
+	}, true
+}
+
 // getVideos extracts all videos from the document
-func (ve *VideoExtractor) getVideos(doc *goquery.Document, articleURL string) []string {
+func (ve *VideoExtractor) getVideos(doc *goquery.Document, articleURL string, jsonLD []map[string]any) []string {
 	var videos []string
+	seen := map[string]bool{}
 
-	// Extract from video tags using parser's GetElementsByTagslist
-	videoElements := parsers.GetElementsByTagslist(doc.Selection, []string{"video"})
-	for _, element := range videoElements {
-		src := parsers.GetAttribute(element, "src", nil, "")
-		if srcStr, ok := src.(string); ok && srcStr != "" {
-			videoURL := urls.JoinURL(articleURL, srcStr)
-			if videoURL != "" {
-				videos = append(videos, videoURL)
-			}
+	add := func(rawURL string) {
+		videoURL := urls.JoinURL(articleURL, rawURL)
+		if videoURL == "" {
+			return
 		}
+		key := ve.videoKey(videoURL)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		videos = append(videos, videoURL)
 	}
 
-	// Extract from iframe tags
-	iframeElements := parsers.GetElementsByTagslist(doc.Selection, []string{"iframe"})
-	for _, element := range iframeElements {
+	// Extract from video tags using parser's GetElementsByTagslist, the
+	// sources of a video without src being its <source> children
+	videoElements := parsers.GetElementsByTagslist(doc.Selection, []string{"video", "amp-video"})
+	for _, element := range videoElements {
+		if ve.isInAdContainer(element) {
+			continue
+		}
 		src := parsers.GetAttribute(element, "src", nil, "")
 		if srcStr, ok := src.(string); ok && srcStr != "" {
-			videoURL := urls.JoinURL(articleURL, srcStr)
-			if videoURL != "" && ve.isVideoProvider(ve.getProvider(srcStr)) {
-				videos = append(videos, videoURL)
-			}
+			add(srcStr)
+			continue
 		}
+		element.Find("source[src]").Each(func(i int, source *goquery.Selection) {
+			add(source.AttrOr("src", ""))
+		})
 	}
 
-	// Extract from embed tags
-	embedElements := parsers.GetElementsByTagslist(doc.Selection, []string{"embed"})
-	for _, element := range embedElements {
-		src := parsers.GetAttribute(element, "src", nil, "")
-		if srcStr, ok := src.(string); ok && srcStr != "" {
-			videoURL := urls.JoinURL(articleURL, srcStr)
-			if videoURL != "" && ve.isVideoProvider(ve.getProvider(srcStr)) {
-				videos = append(videos, videoURL)
+	// Extract from iframe, embed and object tags
+	for _, tag := range []struct{ name, attr string }{{"iframe", "src"}, {"amp-iframe", "src"}, {"embed", "src"}, {"object", "data"}} {
+		elements := parsers.GetElementsByTagslist(doc.Selection, []string{tag.name})
+		for _, element := range elements {
+			src := parsers.GetAttribute(element, tag.attr, nil, "")
+			srcStr, ok := src.(string)
+			if !ok || srcStr == "" {
+				continue
+			}
+			if ve.isInAdContainer(element) || !ve.isAcceptedPlayer(srcStr) {
+				continue
 			}
+			add(srcStr)
 		}
 	}
 
-	// Extract from object tags
-	objectElements := parsers.GetElementsByTagslist(doc.Selection, []string{"object"})
-	for _, element := range objectElements {
-		data := parsers.GetAttribute(element, "data", nil, "")
-		if dataStr, ok := data.(string); ok && dataStr != "" {
-			videoURL := urls.JoinURL(articleURL, dataStr)
-			if videoURL != "" && ve.isVideoProvider(ve.getProvider(dataStr)) {
-				videos = append(videos, videoURL)
+	// Extract from AMP players, which only carry the video identifier
+	for _, player := range ampPlayers {
+		for _, element := range parsers.GetElementsByTagslist(doc.Selection, []string{player.tag}) {
+			id := strings.TrimSpace(element.AttrOr("data-videoid", ""))
+			if id == "" || ve.isInAdContainer(element) || !ve.isAcceptedPlayer(player.embedURL+id) {
+				continue
 			}
+			add(player.embedURL + id)
 		}
 	}
 
 	// Extract from JSON-LD VideoObject
-	videos = append(videos, ve.getVideosFromJSONLD(doc, articleURL)...)
+	for _, videoURL := range ve.getVideosFromJSONLD(jsonLD, articleURL) {
+		add(videoURL)
+	}
 
 	return videos
 }
 
-// getVideosFromJSONLD extracts videos from JSON-LD structured data
-func (ve *VideoExtractor) getVideosFromJSONLD(doc *goquery.Document, articleURL string) []string {
+// getVideosFromJSONLD extracts videos from the VideoObject JSON-LD objects,
+// @graph members included
+func (ve *VideoExtractor) getVideosFromJSONLD(objects []map[string]any, articleURL string) []string {
 	var videos []string
-
-	// Use parser's GetLdJsonObject method
-	jsonObjects := parsers.GetLdJsonObject(doc.Selection)
-
-	for _, data := range jsonObjects {
-		// Handle both single object and array of objects
-		var objects []map[string]any
-		if obj, ok := data["@type"]; ok && obj == "VideoObject" {
-			objects = []map[string]any{data}
-		} else if graph, ok := data["@graph"].([]any); ok {
-			for _, item := range graph {
-				if obj, ok := item.(map[string]any); ok {
-					if objType, ok := obj["@type"]; ok && objType == "VideoObject" {
-						objects = append(objects, obj)
-					}
-				}
-			}
+	for _, obj := range objects {
+		if obj["@type"] != "VideoObject" {
+			continue
 		}
-
-		for _, obj := range objects {
-			if contentURL, ok := obj["contentUrl"].(string); ok && contentURL != "" {
-				videoURL := urls.JoinURL(articleURL, contentURL)
-				if videoURL != "" {
-					videos = append(videos, videoURL)
-				}
+		for _, key := range []string{"contentUrl", "embedUrl"} {
+			rawURL, ok := obj[key].(string)
+			if !ok || rawURL == "" {
+				continue
+			}
+			if ve.getProvider(rawURL) != "" && !ve.isAcceptedPlayer(rawURL) {
+				continue
+			}
+			videoURL := urls.JoinURL(articleURL, rawURL)
+			if videoURL != "" {
+				videos = append(videos, videoURL)
+				break
 			}
 		}
 	}
-
 	return videos
 }
 
@@ -157,3 +277,119 @@ func (ve *VideoExtractor) isVideoProvider(provider string) bool {
 	}
 	return false
 }
+
+// isAllowedProvider applies the configured provider allowlist and denylist
+func (ve *VideoExtractor) isAllowedProvider(provider string) bool {
+	if ve.config == nil {
+		return true
+	}
+	if slices.Contains(ve.config.VideoProviderDenylist, provider) {
+		return false
+	}
+	if len(ve.config.VideoProviderAllowlist) > 0 {
+		return slices.Contains(ve.config.VideoProviderAllowlist, provider)
+	}
+	return true
+}
+
+// isAcceptedPlayer checks that a player URL belongs to an allowed provider
+// and actually references a video rather than a bare embed endpoint
+func (ve *VideoExtractor) isAcceptedPlayer(videoURL string) bool {
+	provider := ve.getProvider(videoURL)
+	if !ve.isVideoProvider(provider) || !ve.isAllowedProvider(provider) {
+		return false
+	}
+	return ve.getVideoID(provider, videoURL) != ""
+}
+
+// getVideoID extracts the provider specific video identifier from a player URL
+func (ve *VideoExtractor) getVideoID(provider, videoURL string) string {
+	parsedURL, err := url.Parse(videoURL)
+	if err != nil {
+		return ""
+	}
+
+	chunks := []string{}
+	for _, chunk := range strings.Split(parsedURL.Path, "/") {
+		if chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	switch provider {
+	case "youtube", "youtu.be":
+		if v := parsedURL.Query().Get("v"); v != "" {
+			return v
+		}
+		if strings.Contains(parsedURL.Host, "youtu.be") && len(chunks) > 0 {
+			return chunks[0]
+		}
+		for i, chunk := range chunks {
+			if (chunk == "embed" || chunk == "v" || chunk == "shorts" || chunk == "live") && i+1 < len(chunks) {
+				if chunks[i+1] == "videoseries" {
+					return parsedURL.Query().Get("list")
+				}
+				return chunks[i+1]
+			}
+		}
+		return ""
+	case "vimeo":
+		for _, chunk := range chunks {
+			if strings.Trim(chunk, "0123456789") == "" {
+				return chunk
+			}
+		}
+		return ""
+	case "dailymotion":
+		if v := parsedURL.Query().Get("video"); v != "" {
+			return v
+		}
+		for i, chunk := range chunks {
+			if chunk == "video" && i+1 < len(chunks) {
+				return strings.SplitN(chunks[i+1], "_", 2)[0]
+			}
+		}
+		return ""
+	}
+
+	// Generic providers: accept anything that goes beyond a bare player endpoint
+	if len(chunks) == 0 {
+		return ""
+	}
+	last := chunks[len(chunks)-1]
+	if last == "embed" || last == "player" || last == "video" {
+		return ""
+	}
+	return last
+}
+
+// videoKey returns a key used to deduplicate the same video referenced in several places
+func (ve *VideoExtractor) videoKey(videoURL string) string {
+	provider := ve.getProvider(videoURL)
+	if provider != "" {
+		if id := ve.getVideoID(provider, videoURL); id != "" {
+			if provider == "youtu.be" {
+				provider = "youtube"
+			}
+			return provider + ":" + id
+		}
+	}
+	return videoURL
+}
+
+// adContainerBoundary matches the elements ending the search of an ad slot:
+// the classes of the article and the page describe the site, not the video
+const adContainerBoundary = "article, main, body, html"
+
+// isInAdContainer checks whether the element or one of its ancestors within
+// the article looks like an ad slot
+func (ve *VideoExtractor) isInAdContainer(element *goquery.Selection) bool {
+	for current := element; current.Length() > 0 && !current.Is(adContainerBoundary); current = current.Parent() {
+		for _, attr := range []string{"class", "id"} {
+			if val, ok := current.Attr(attr); ok && adContainerRe.MatchString(val) {
+				return true
+			}
+		}
+	}
+	return false
+}
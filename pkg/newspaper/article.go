@@ -1,18 +1,28 @@
 package newspaper
 
 import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/tguidoux/newspaper4k-go/internal/cleaner"
+	"github.com/tguidoux/newspaper4k-go/internal/helpers"
 	"github.com/tguidoux/newspaper4k-go/internal/nlp"
 	"github.com/tguidoux/newspaper4k-go/internal/parsers"
 	"github.com/tguidoux/newspaper4k-go/internal/resources/text"
@@ -29,58 +39,274 @@ const (
 	NotStarted     ArticleDownloadState = 0
 	FailedResponse ArticleDownloadState = 1
 	Success        ArticleDownloadState = 2
+	// SoftError is a page answering 200 OK whose content is an error page,
+	// e.g. a "page not found" template
+	SoftError ArticleDownloadState = 3
+	// NotHTML is a response whose content type is not HTML, e.g. JSON
+	NotHTML ArticleDownloadState = 4
 )
 
+// downloadStateNames are the string forms of the download states
+var downloadStateNames = map[ArticleDownloadState]string{
+	NotStarted:     "NotStarted",
+	FailedResponse: "FailedResponse",
+	Success:        "Success",
+	SoftError:      "SoftError",
+	NotHTML:        "NotHTML",
+}
+
+// String returns the name of the state, e.g. "Success"
+func (s ArticleDownloadState) String() string {
+	if name, ok := downloadStateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("ArticleDownloadState(%d)", int(s))
+}
+
+// MarshalJSON serializes the state as its name
+func (s ArticleDownloadState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON reads a state from its name or, for data serialized by
+// older versions, from its integer value
+func (s *ArticleDownloadState) UnmarshalJSON(data []byte) error {
+	var value int
+	if err := json.Unmarshal(data, &value); err == nil {
+		*s = ArticleDownloadState(value)
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("invalid download state %s: %w", data, err)
+	}
+	for state, stateName := range downloadStateNames {
+		if strings.EqualFold(name, stateName) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown download state %q", name)
+}
+
+// DiagnosticSeverity tells whether a Diagnostic is a notice or a failure of
+// an extractor
+type DiagnosticSeverity int
+
+const (
+	// DiagnosticInfo is an expected situation worth reporting, e.g. a JSON-LD
+	// block skipped because of MaxJSONLDBytes
+	DiagnosticInfo DiagnosticSeverity = 0
+	// DiagnosticError is a failure losing data, e.g. a malformed JSON-LD block
+	DiagnosticError DiagnosticSeverity = 1
+)
+
+// String returns the name of the severity, "info" or "error"
+func (s DiagnosticSeverity) String() string {
+	switch s {
+	case DiagnosticInfo:
+		return "info"
+	case DiagnosticError:
+		return "error"
+	}
+	return fmt.Sprintf("DiagnosticSeverity(%d)", int(s))
+}
+
+// MarshalJSON serializes the severity as its name
+func (s DiagnosticSeverity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// Diagnostic is a non-fatal issue met while extracting an article
+type Diagnostic struct {
+	Severity DiagnosticSeverity `json:"severity"`
+	Message  string             `json:"message"`
+}
+
+// String returns the message of the diagnostic
+func (d Diagnostic) String() string {
+	return d.Message
+}
+
+// AudioInfo describes an audio file (podcast episode, audio article) attached to an article.
+type AudioInfo struct {
+	URL      string        `json:"url"`       // Absolute URL of the audio file
+	MimeType string        `json:"mime_type"` // Declared or guessed MIME type, e.g. audio/mpeg
+	Duration time.Duration `json:"duration"`  // Duration of the audio, 0 if unknown, serialized in seconds
+}
+
+// audioInfoJSON is the serialized form of AudioInfo
+type audioInfoJSON struct {
+	URL      string  `json:"url"`
+	MimeType string  `json:"mime_type"`
+	Duration float64 `json:"duration"`
+}
+
+// MarshalJSON serializes the audio with its duration in seconds
+func (ai AudioInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(audioInfoJSON{URL: ai.URL, MimeType: ai.MimeType, Duration: ai.Duration.Seconds()})
+}
+
+// UnmarshalJSON reads an audio with its duration in seconds
+func (ai *AudioInfo) UnmarshalJSON(data []byte) error {
+	var value audioInfoJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*ai = AudioInfo{URL: value.URL, MimeType: value.MimeType, Duration: time.Duration(value.Duration * float64(time.Second))}
+	return nil
+}
+
+// VideoInfo holds the oEmbed details of an embedded video (Config.ResolveOEmbed).
+type VideoInfo struct {
+	URL          string `json:"url"`           // Video or player URL
+	Provider     string `json:"provider"`      // Provider name as reported by oEmbed
+	Title        string `json:"title"`         // Video title
+	Author       string `json:"author"`        // Author or channel name
+	ThumbnailURL string `json:"thumbnail_url"` // Thumbnail image URL
+}
+
+// Table is a data table found in the article body.
+type Table struct {
+	Headers []string   `json:"headers"` // Header cells, empty if the table has none
+	Rows    [][]string `json:"rows"`    // Body rows, one slice of cell texts per row
+}
+
+// Update is one entry of the revision history displayed by the article.
+type Update struct {
+	Time time.Time `json:"time"` // When the article was updated
+	Note string    `json:"note"` // Description of the change, may be empty
+}
+
+// RatingData is a review or aggregate rating declared by the page
+type RatingData struct {
+	Value float64 `json:"value"` // Rating value, e.g. 4.5
+	Best  float64 `json:"best"`  // Highest possible value, 0 if not declared
+	Worst float64 `json:"worst"` // Lowest possible value, 0 if not declared
+	Count int     `json:"count"` // Number of ratings or reviews aggregated, 0 if not declared
+}
+
+// ContentType is the kind of page declared by the og:type meta tag
+type ContentType string
+
+const (
+	ContentTypeArticle ContentType = "article"
+	ContentTypeVideo   ContentType = "video"
+	ContentTypeWebsite ContentType = "website"
+	ContentTypeProfile ContentType = "profile"
+	ContentTypeOther   ContentType = "other"
+)
+
+// ContentTypeFromOGType normalizes an og:type value such as "article" or
+// "video.other". It returns "" when ogType is empty.
+func ContentTypeFromOGType(ogType string) ContentType {
+	ogType = strings.ToLower(strings.TrimSpace(ogType))
+	kind, _, _ := strings.Cut(ogType, ".")
+	switch {
+	case ogType == "":
+		return ""
+	case kind == "article" || kind == "blog" || kind == "newsarticle":
+		return ContentTypeArticle
+	case kind == "video":
+		return ContentTypeVideo
+	case kind == "website":
+		return ContentTypeWebsite
+	case kind == "profile":
+		return ContentTypeProfile
+	default:
+		return ContentTypeOther
+	}
+}
+
 // Article abstraction for
 // This object fetches and holds information for a single article.
 type Article struct {
-	SourceURL            string               // URL to the main page of the news source
-	URL                  string               // The article link (may differ from original URL)
-	Title                string               // Parsed title of the article
-	TopImage             string               // Top image URL of the article
-	MetaImg              string               // Image URL provided by metadata
-	Images               []string             // List of all image URLs in the article
-	Movies               []string             // List of video links in the article body
-	Text                 string               // Parsed version of the article body
-	Keywords             []string             // Inferred list of keywords for this article
-	KeywordScores        map[string]float64   // Dictionary of keywords and their scores
-	MetaKeywords         []string             // List of keywords provided by the meta data
-	Tags                 map[string]string    // Extracted tag set from the article body
-	Authors              []string             // Author list parsed from the article
-	PublishDate          *time.Time           // Parsed publishing date from the article
-	Summary              string               // Summarization of the article
-	HTML                 string               // Raw HTML of the article page
-	ArticleHTML          string               // Raw HTML of the article body
-	IsParsed             bool                 // True if parse() has been called
-	DownloadState        ArticleDownloadState // Download state
-	DownloadExceptionMsg string               // Exception message if download() failed
-	MetaDescription      string               // Description extracted from meta data
-	MetaLang             string               // Language extracted from meta data
-	MetaFavicon          string               // Website's favicon URL
-	MetaSiteName         string               // Website's name
-	MetaData             map[string]string    // Additional meta data from meta tags
-	CanonicalLink        string               // Canonical URL for the article
-	Categories           []*urls.URL          // Extracted category URLs from the source
-	TopNode              *goquery.Selection   // Top node of the original DOM tree (HTML element)
-	Doc                  *goquery.Document    // Full DOM of the downloaded HTML
-	CleanDoc             *goquery.Document    // Cleaned version of the DOM tree
-	Language             language.Tag         // Detected language of the article
-	Config               *configuration.Configuration
-	Bitcoins             []string
-	MD5s                 []string
-	SHA1s                []string
-	SHA256s              []string
-	SHA512s              []string
-	Domains              []string
-	Emails               []string
-	IPv4s                []string
-	IPv6s                []string
-	OtherURLs            []string
-	Files                []string
-	CVEs                 []string
-	CAPECs               []string
-	CWEs                 []string
-	CPEs                 []string
+	SourceURL             string               // URL to the main page of the news source
+	URL                   string               // The article link (may differ from original URL)
+	ViaURL                string               // Aggregator page the article was reached from, see Config.FollowOutboundCanonical
+	Title                 string               // Parsed title of the article
+	RawTitle              string               // Title before Config.NormalizeTitle post-processing
+	TopImage              string               // Top image URL of the article
+	TopImageWidth         int                  // Declared width of the top image in pixels, 0 if unknown
+	TopImageHeight        int                  // Declared height of the top image in pixels, 0 if unknown
+	TopImageOrientation   string               // landscape, portrait or square, empty if the size is unknown
+	MetaImg               string               // Image URL provided by metadata
+	Images                []string             // List of all image URLs in the article
+	Movies                []string             // List of video links in the article body
+	Videos                []VideoInfo          // oEmbed details of the videos (Config.ResolveOEmbed)
+	Audio                 []AudioInfo          // List of audio files (podcast enclosures, <audio> sources)
+	Tables                []Table              // Data tables of the article body (Config.ExtractTables)
+	Text                  string               // Parsed version of the article body
+	TextTruncated         bool                 // True if Text was cut to Config.MaxTextLength
+	TextTruncatedForNLP   bool                 // True if only the head and tail of Text were used for NLP
+	NLPSkippedNoStopwords bool                 // True if the language has no stop words, see Config.NoStopwordsNLP
+	Keywords              []string             // Inferred list of keywords for this article
+	KeywordScores         map[string]float64   // Dictionary of keywords and their scores
+	MetaKeywords          []string             // List of keywords provided by the meta data
+	Tags                  map[string]string    // Extracted tag set from the article body
+	Authors               []string             // Author list parsed from the article
+	PublishDate           *time.Time           // Parsed publishing date from the article
+	ModifiedDate          *time.Time           // Last modification date, from the metadata or an "Updated 3 hours ago" banner
+	FetchedAt             time.Time            // When Download got the page, relative dates of the page are resolved against it
+	UpdateHistory         []Update             // Revision history listed on the page, oldest first
+	Summary               string               // Summarization of the article
+	HTML                  string               // Raw HTML of the article page
+	ArticleHTML           string               // Raw HTML of the article body
+	IsParsed              bool                 // True if parse() has been called
+	DownloadState         ArticleDownloadState // Download state
+	DownloadExceptionMsg  string               // Exception message if download() failed
+	MetaDescription       string               // Description extracted from meta data
+	MetaLang              string               // Language extracted from meta data
+	MetaFavicon           string               // Website's favicon URL
+	MetaSiteName          string               // Website's name
+	MetaData              map[string]string    // Additional meta data from meta tags
+	DublinCore            map[string]string    // Dublin Core, eprints and PRISM meta tags keyed by lowercased name, repeated values joined with "; "
+	DOI                   string               // Digital Object Identifier from the PRISM or Dublin Core tags, e.g. 10.1000/xyz123
+	RawJSONLD             []string             // Text of every JSON-LD block of the page, parsed or not, see Config.KeepRawJSONLD
+	OGType                string               // Raw og:type meta tag, e.g. article or video.other
+	Breadcrumbs           []string             // Breadcrumb trail of the page, from the home page to the article section
+	Rating                *RatingData          // Review or aggregate rating from JSON-LD, nil if none
+	PartOf                string               // Name of the series or collection the article belongs to (JSON-LD isPartOf, article:series)
+	PartOfURL             string               // URL of the series or collection, empty if not declared
+	ContentType           ContentType          // Normalized OGType, empty if the page has none
+	CanonicalLink         string               // Canonical URL for the article
+	DuplicateOf           string               // URL of the article of the batch this one duplicates, see newspaper4k.BuildArticles
+	PrevURL               string               // Previous part of the series (<link rel="prev">)
+	NextURL               string               // Next part of the series (<link rel="next">)
+	DiscussionURL         string               // Separate page of the comments of the article (rel="discussion" or a "Comments" link)
+	Categories            []*urls.URL          // Sections and tags the article is filed under, see Config.SiteWideArticleCategories
+	TopNode               *goquery.Selection   // Top node of the original DOM tree (HTML element)
+	Doc                   *goquery.Document    // Full DOM of the downloaded HTML
+	CleanDoc              *goquery.Document    // Cleaned version of the DOM tree
+	Language              language.Tag         // Detected language of the article
+	LanguageConfidence    float64              // Confidence of the detected language (0 when taken from metadata)
+	LanguageSource        string               // Where Language comes from: config, top_node, article, html, og, meta, jsonld or detected
+	Diagnostics           []Diagnostic         // Non-fatal issues met while extracting the article
+	RobotsDirectives      []string             // Directives of the robots meta tags and X-Robots-Tag header
+	NoIndex               bool                 // True if the publisher asked not to index the page
+	NoArchive             bool                 // True if the publisher asked not to store the page, see Config.DropNoArchiveContent
+	Config                *configuration.Configuration
+	Bitcoins              []string
+	MD5s                  []string
+	SHA1s                 []string
+	SHA256s               []string
+	SHA512s               []string
+	Domains               []string
+	Emails                []string
+	IPv4s                 []string
+	IPv6s                 []string
+	OtherURLs             []string
+	Files                 []string
+	CVEs                  []string
+	CAPECs                []string
+	CWEs                  []string
+	CPEs                  []string
+
+	// jsonLD caches the JSON-LD blocks of jsonLDDoc decoded with the
+	// jsonLDMaxBytes size limit, see JSONLD
+	jsonLD         *parsers.LdJson
+	jsonLDDoc      *goquery.Document
+	jsonLDMaxBytes int
 }
 
 // ParseRequest represents parameters for creating and parsing an Article.
@@ -93,7 +319,12 @@ type ParseRequest struct {
 
 // Build builds a lone article from a URL. Calls Download(), Parse(), and NLP() in succession.
 func (a *Article) Build(extractors []Extractor) error {
-	err := a.Download()
+	return a.BuildWithContext(context.Background(), extractors)
+}
+
+// BuildWithContext is like Build but the download is canceled with ctx
+func (a *Article) BuildWithContext(ctx context.Context, extractors []Extractor) error {
+	err := a.DownloadWithContext(ctx)
 	if err != nil {
 		return fmt.Errorf("error downloading article: %w", err)
 	}
@@ -111,6 +342,11 @@ func (a *Article) Build(extractors []Extractor) error {
 
 // Download downloads the link's HTML content.
 func (a *Article) Download() error {
+	return a.DownloadWithContext(context.Background())
+}
+
+// DownloadWithContext is like Download but the request is canceled with ctx
+func (a *Article) DownloadWithContext(ctx context.Context) error {
 
 	inputHTML := a.Config.DownloadOptions.InputHTML
 
@@ -119,29 +355,33 @@ func (a *Article) Download() error {
 	}
 
 	if inputHTML == "" {
-		// Implement HTTP request logic
-		resp, err := http.Get(a.URL)
+		// Concurrent downloads of the same URL share a single request
+		resp, err := helpers.DefaultFetcher.Get(a.httpClient(), a.URL, helpers.FetchOptions{
+			RecentCacheSize: a.Config.RecentDownloadCacheSize,
+			RecentCacheTTL:  time.Duration(a.Config.RecentDownloadCacheTTLSeconds) * time.Second,
+			UserAgent:       a.Config.RequestsParams.NextUserAgent(),
+			Headers:         a.Config.RequestsParams.Headers,
+			MaxRetries:      a.Config.RequestsParams.MaxRetries,
+			RetryBackoff:    a.Config.RequestsParams.RetryBackoff,
+			Context:         ctx,
+		})
 		if err != nil {
 			a.DownloadState = FailedResponse
 			a.DownloadExceptionMsg = err.Error()
 			return fmt.Errorf("error performing HTTP GET request: %w", err)
 		}
-		defer func() {
-			err = resp.Body.Close()
 
-			if err != nil {
-				fmt.Println("Error closing response body:", err)
-			}
-		}()
-
-		// Read HTML
-		htmlBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
+		if err := checkResponseContent(resp); err != nil {
 			a.DownloadState = FailedResponse
+			if errors.Is(err, ErrNotHTML) {
+				a.DownloadState = NotHTML
+			}
 			a.DownloadExceptionMsg = err.Error()
-			return fmt.Errorf("error reading response body: %w", err)
+			return err
 		}
-		htmlContent := string(htmlBytes)
+
+		htmlContent := parsers.GetUnicodeHTMLWithContentType(string(resp.Body), resp.Header.Get("Content-Type"))
+		a.RobotsDirectives = parsers.ParseRobotsDirectives(resp.Header.Values("X-Robots-Tag")...)
 
 		// Use goquery to parse
 		doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
@@ -152,10 +392,10 @@ func (a *Article) Download() error {
 		}
 		a.Doc = doc
 		a.HTML = htmlContent
-		a.DownloadState = Success
-		a.HTML = inputHTML // Keep the original HTML
+		a.FetchedAt = time.Now()
 		a.DownloadState = Success
 	} else {
+		inputHTML = parsers.GetUnicodeHTML(inputHTML)
 		a.HTML = inputHTML
 		doc, err := goquery.NewDocumentFromReader(strings.NewReader(inputHTML))
 		if err != nil {
@@ -164,22 +404,85 @@ func (a *Article) Download() error {
 			return fmt.Errorf("error parsing provided HTML: %w", err)
 		}
 		a.Doc = doc
+		a.FetchedAt = time.Now()
 		a.DownloadState = Success
 	}
 
 	return nil
 }
 
+// httpClient returns the client sending the requests of the article,
+// Config.HTTPClient when set
+func (a *Article) httpClient() configuration.HTTPClient {
+	if a.Config.HTTPClient != nil {
+		return a.Config.HTTPClient
+	}
+	return helpers.SharedHTTPClient(a.Config.RequestsParams.Timeout, -1, a.Config.RequestsParams.Proxies)
+}
+
+// Errors reported by Download for responses without an article page, match them
+// with errors.Is
+var (
+	ErrEmptyResponse = errors.New("empty response body")
+	ErrNotHTML       = errors.New("response is not HTML")
+	ErrEmptyDocument = errors.New("document has no body")
+	ErrHTTPStatus    = errors.New("HTTP error status")
+)
+
+// checkResponseContent rejects error statuses, whose pages are not the
+// article, and the responses that would parse into an empty document: 204 No
+// Content, empty bodies, and JSON or plain text content. Redirects are
+// followed by the client before.
+func checkResponseContent(resp *helpers.FetchResult) error {
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: %s returned %d %s", ErrHTTPStatus, resp.URL, resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return fmt.Errorf("%w: %s returned %d No Content", ErrEmptyResponse, resp.URL, resp.StatusCode)
+	}
+	if len(bytes.TrimSpace(resp.Body)) == 0 {
+		return fmt.Errorf("%w: %s returned no content", ErrEmptyResponse, resp.URL)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || mediaType == "text/plain" {
+		return fmt.Errorf("%w: %s has content type %s", ErrNotHTML, resp.URL, mediaType)
+	}
+	return nil
+}
+
+// ExtractorObserver is called by ParseObserved after each extractor run, with
+// its duration and error
+type ExtractorObserver func(ext Extractor, elapsed time.Duration, err error)
+
 // Parse parses the previously downloaded article.
 func (a *Article) Parse(extractors []Extractor) error {
+	return a.ParseObserved(extractors, nil)
+}
+
+// ParseObserved is like Parse but reports every extractor run to observe,
+// which may be nil
+func (a *Article) ParseObserved(extractors []Extractor, observe ExtractorObserver) error {
 	if err := a.ThrowIfNotDownloadedVerbose(); err != nil {
 		// Handle error, perhaps log or return
 		return fmt.Errorf("article not downloaded: %w", err)
 	}
 
+	// A document without body content would parse into an empty article
+	// indistinguishable from a real one
+	if a.Doc != nil && a.Text == "" {
+		body := a.Doc.Find("body")
+		if body.Children().Length() == 0 && strings.TrimSpace(body.Text()) == "" {
+			return fmt.Errorf("%w: nothing to parse on URL %s", ErrEmptyDocument, a.URL)
+		}
+	}
+
 	// Run extractors
 	for _, ext := range extractors {
+		start := time.Now()
 		err := ext.Parse(a)
+		if observe != nil {
+			observe(ext, time.Since(start), err)
+		}
 		if err != nil {
 			return fmt.Errorf("error in extractor %T: %w", ext, err)
 		}
@@ -187,17 +490,49 @@ func (a *Article) Parse(extractors []Extractor) error {
 
 	// Clean the top node if it exists
 	if a.TopNode != nil {
-		documentCleaner := cleaner.NewDocumentCleaner()
+		documentCleaner := a.newDocumentCleaner()
 		a.TopNode = documentCleaner.Clean(a.TopNode)
 		// Update article HTML and text from cleaned node
 		a.ArticleHTML = parsers.OuterHTML(a.TopNode)
 		a.Text = parsers.GetText(a.TopNode)
 	}
 
+	if a.Config.StripEmoji {
+		a.Text = parsers.StripEmoji(a.Text)
+	}
+	if a.Config.DedupeSentences {
+		a.Text = parsers.DedupeConsecutiveSentences(a.Text)
+	}
+
+	if a.Config.TruncateText && a.Config.MaxTextLength > 0 && len(a.Text) > a.Config.MaxTextLength {
+		a.Text = truncateHeadTail(a.Text, a.Config.MaxTextLength, a.Config.MaxTextHeadRatio)
+		a.TextTruncated = true
+	}
+
+	if a.Config.DropNoArchiveContent && a.NoArchive {
+		a.dropContent()
+	}
+
 	a.IsParsed = true
+
+	if a.Config.FollowOutboundCanonical {
+		a.followOutboundStory(extractors, observe)
+	}
 	return nil
 }
 
+// dropContent clears the page and body content of the article, keeping the
+// extracted metadata so that it can still be cited
+func (a *Article) dropContent() {
+	a.HTML = ""
+	a.ArticleHTML = ""
+	a.Text = ""
+	a.Tables = nil
+	a.TopNode = nil
+	a.Doc = nil
+	a.CleanDoc = nil
+}
+
 // NLP performs keyword extraction and summarization.
 func (a *Article) NLP() error {
 	if err := a.ThrowIfNotParsedVerbose(); err != nil {
@@ -206,29 +541,90 @@ func (a *Article) NLP() error {
 	}
 
 	// Get language for stop words
-	language := a.GetLanguage().String()
+	language := a.NLPLanguage()
+
+	// Very long pages are reduced to their head and tail to bound NLP runtime
+	text := a.Text
+	a.TextTruncatedForNLP = false
+	if maxLength := a.Config.MaxTextLength; maxLength > 0 && len(text) > maxLength {
+		text = truncateHeadTail(text, maxLength, a.Config.MaxTextHeadRatio)
+		a.TextTruncatedForNLP = true
+	}
+
+	// English stop words would be meaningless for another language
+	a.NLPSkippedNoStopwords = false
+	if len(nlp.GetStopWordsForLanguage(language)) == 0 {
+		a.nlpWithoutStopwords(text)
+		return nil
+	}
 
 	// Create StopWords instance
 	stopwords, err := nlp.NewStopWords(language)
 	if err != nil {
 		// Fallback to basic method if StopWords creation fails
-		a.extractKeywordsBasic()
-		a.generateSummaryBasic()
+		a.extractKeywordsBasic(text)
+		a.generateSummaryBasic(text)
 		return nil
 	}
 
 	// Extract keywords using NLP package
-	a.extractKeywordsWithNLP(stopwords)
+	a.extractKeywordsWithNLP(text, stopwords)
 
 	// Generate summary using NLP package
-	a.generateSummaryWithNLP(stopwords)
+	a.generateSummaryWithNLP(text, stopwords)
 
 	return nil
 }
 
+// noStopwordsMaxKeywords caps the number of keywords ranked by frequency alone
+const noStopwordsMaxKeywords = 10
+
+// nlpWithoutStopwords handles a language without stop words according to
+// Config.NoStopwordsNLP: nothing is generated, or a few keywords are ranked
+// by frequency alone. The summary is left empty in both cases.
+func (a *Article) nlpWithoutStopwords(text string) {
+	a.NLPSkippedNoStopwords = true
+	a.Keywords = []string{}
+	a.KeywordScores = map[string]float64{}
+	a.Summary = ""
+
+	if a.Config.NoStopwordsNLP != configuration.NoStopwordsFrequency {
+		return
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r)
+	})
+	wordFreq := make(map[string]int)
+	for _, word := range words {
+		if utf8.RuneCountInString(word) >= 2 {
+			wordFreq[word]++
+		}
+	}
+
+	ranked := make([]string, 0, len(wordFreq))
+	for word := range wordFreq {
+		ranked = append(ranked, word)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if wordFreq[ranked[i]] != wordFreq[ranked[j]] {
+			return wordFreq[ranked[i]] > wordFreq[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+
+	maxKeywords := min(a.Config.MaxKeywords, noStopwordsMaxKeywords)
+	if maxKeywords <= 0 {
+		maxKeywords = noStopwordsMaxKeywords
+	}
+	for _, word := range ranked[:min(len(ranked), maxKeywords)] {
+		a.Keywords = append(a.Keywords, word)
+		a.KeywordScores[word] = float64(wordFreq[word]) / float64(len(words))
+	}
+}
+
 // extractKeywordsWithNLP extracts keywords using the NLP package
-func (a *Article) extractKeywordsWithNLP(stopwords *nlp.StopWords) {
-	text := a.Text
+func (a *Article) extractKeywordsWithNLP(text string, stopwords *nlp.StopWords) {
 	if text == "" {
 		return
 	}
@@ -337,9 +733,8 @@ func (a *Article) combineTitleKeywords() {
 }
 
 // generateSummaryWithNLP generates summary using the NLP package
-func (a *Article) generateSummaryWithNLP(stopwords *nlp.StopWords) {
+func (a *Article) generateSummaryWithNLP(text string, stopwords *nlp.StopWords) {
 	title := a.Title
-	text := a.Text
 	if text == "" {
 		return
 	}
@@ -350,13 +745,76 @@ func (a *Article) generateSummaryWithNLP(stopwords *nlp.StopWords) {
 		maxSentences = 5
 	}
 
-	summarySentences := nlp.Summarize(title, text, stopwords, maxSentences)
-	a.Summary = strings.Join(summarySentences, " ")
+	summarySentences := nlp.Summarize(title, text, stopwords, maxSentences, a.summaryWeights())
+	a.Summary = joinSummary(summarySentences, a.Config.MaxSummaryChars)
 }
 
-// extractKeywordsBasic is a fallback keyword extraction without gse
-func (a *Article) extractKeywordsBasic() {
+// joinSummary joins the sentences of a summary, dropping the sentences
+// which would take it over maxChars characters. A first sentence longer than
+// maxChars is cut on whitespace. maxChars <= 0 keeps every sentence.
+func joinSummary(sentences []string, maxChars int) string {
+	summary := strings.Join(sentences, " ")
+	if maxChars <= 0 || utf8.RuneCountInString(summary) <= maxChars {
+		return summary
+	}
+
+	summary = ""
+	for _, sentence := range sentences {
+		joined := sentence
+		if summary != "" {
+			joined = summary + " " + sentence
+		}
+		if utf8.RuneCountInString(joined) > maxChars {
+			break
+		}
+		summary = joined
+	}
+	if summary != "" || len(sentences) == 0 {
+		return summary
+	}
+
+	runes := []rune(sentences[0])[:maxChars]
+	cut := string(runes)
+	if i := strings.LastIndexAny(cut, " \t\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut)
+}
+
+// summaryWeights returns Config.SummaryWeights, the defaults when unset
+func (a *Article) summaryWeights() nlp.SummaryWeights {
+	weights := nlp.SummaryWeights(a.Config.SummaryWeights)
+	if weights == (nlp.SummaryWeights{}) {
+		weights = nlp.DefaultSummaryWeights
+	}
+	return weights
+}
+
+// KeySentences returns the n sentences of the text ranked best by the
+// summarizer, with their index in the text and their score, in the order of
+// the text. It returns nil when the article has no text or its language has
+// no stop words.
+func (a *Article) KeySentences(n int) []nlp.SentenceRank {
+	if a.Text == "" || n <= 0 {
+		return nil
+	}
+	language := a.NLPLanguage()
+	if len(nlp.GetStopWordsForLanguage(language)) == 0 {
+		return nil
+	}
+	stopwords, err := nlp.NewStopWords(language)
+	if err != nil {
+		return nil
+	}
 	text := a.Text
+	if maxLength := a.Config.MaxTextLength; maxLength > 0 && len(text) > maxLength {
+		text = truncateHeadTail(text, maxLength, a.Config.MaxTextHeadRatio)
+	}
+	return nlp.KeySentences(a.Title, text, stopwords, n, a.summaryWeights())
+}
+
+// extractKeywordsBasic is a fallback keyword extraction without gse
+func (a *Article) extractKeywordsBasic(text string) {
 	if text == "" {
 		return
 	}
@@ -413,8 +871,7 @@ func (a *Article) extractKeywordsBasic() {
 }
 
 // generateSummaryBasic generates a basic summary from the article text
-func (a *Article) generateSummaryBasic() {
-	text := a.Text
+func (a *Article) generateSummaryBasic(text string) {
 	if text == "" {
 		return
 	}
@@ -479,13 +936,10 @@ func (a *Article) generateSummaryBasic() {
 		if i >= maxSentences {
 			break
 		}
-		summarySentences = append(summarySentences, ss.sentence)
+		summarySentences = append(summarySentences, ss.sentence+".")
 	}
 
-	a.Summary = strings.Join(summarySentences, ". ")
-	if a.Summary != "" && !strings.HasSuffix(a.Summary, ".") {
-		a.Summary += "."
-	}
+	a.Summary = joinSummary(summarySentences, a.Config.MaxSummaryChars)
 }
 
 // getStopWords returns a language-specific set of stop words
@@ -493,7 +947,7 @@ func (a *Article) getStopWords() map[string]bool {
 	stopWords := make(map[string]bool)
 
 	// Try to load stop words from the text package based on article's language
-	language := a.GetLanguage().String()
+	language := a.NLPLanguage()
 
 	stopWordsSlice := nlp.GetStopWordsForLanguage(language)
 
@@ -514,6 +968,40 @@ func (a *Article) getStopWords() map[string]bool {
 	return stopWords
 }
 
+// truncateHeadTail keeps about maxLength bytes of text: headRatio of it from the
+// beginning and the rest from the end, cutting on whitespace
+func truncateHeadTail(text string, maxLength int, headRatio float64) string {
+	if len(text) <= maxLength {
+		return text
+	}
+	if headRatio <= 0 || headRatio > 1 {
+		headRatio = 0.8
+	}
+
+	headLength := int(float64(maxLength) * headRatio)
+	for headLength > 0 && !utf8.RuneStart(text[headLength]) {
+		headLength--
+	}
+	head := text[:headLength]
+	if i := strings.LastIndexAny(head, " \t\n"); i > 0 {
+		head = head[:i]
+	}
+
+	tail := ""
+	if tailLength := maxLength - headLength; tailLength > 0 {
+		start := len(text) - tailLength
+		for start < len(text) && !utf8.RuneStart(text[start]) {
+			start++
+		}
+		tail = text[start:]
+		if i := strings.IndexAny(tail, " \t\n"); i >= 0 {
+			tail = tail[i+1:]
+		}
+	}
+
+	return strings.TrimSpace(head) + "\n\n" + strings.TrimSpace(tail)
+}
+
 // isStopWord checks if a word is a stop word
 func (a *Article) isStopWord(word string, stopWords map[string]bool) bool {
 	return stopWords[strings.ToLower(word)]
@@ -534,9 +1022,98 @@ func (a *Article) GetHTML() string {
 	return a.HTML
 }
 
-// GetSummary returns the summary of the article.
+// GetSummary returns the summary of the article. When NLP() has not run,
+// it falls back, without running it, to the first non-empty of:
+//   - MetaDescription
+//   - the description of the JSON-LD objects of the page
+//   - the lead, see FirstParagraph
 func (a *Article) GetSummary() string {
-	return a.Summary
+	if a.Summary != "" {
+		return a.Summary
+	}
+	if a.MetaDescription != "" {
+		return a.MetaDescription
+	}
+	if description := a.jsonLDDescription(); description != "" {
+		return description
+	}
+	return a.FirstParagraph()
+}
+
+// jsonLDDescription returns the first description found in the JSON-LD
+// objects of the page, @graph members included
+func (a *Article) jsonLDDescription() string {
+	for _, obj := range a.jsonLDObjects() {
+		if description, ok := obj["description"].(string); ok && strings.TrimSpace(description) != "" {
+			return strings.TrimSpace(description)
+		}
+	}
+	return ""
+}
+
+// jsonLDObjects returns the JSON-LD objects of the page followed by the
+// members of their @graph, skipping the blocks over Config.MaxJSONLDBytes
+func (a *Article) jsonLDObjects() []map[string]any {
+	maxBytes := 0
+	if a.Config != nil {
+		maxBytes = a.Config.MaxJSONLDBytes
+	}
+	return a.JSONLD(maxBytes).Graph
+}
+
+// JSONLD returns the JSON-LD blocks of Doc, the ones larger than maxBytes
+// left undecoded. The blocks are decoded once and shared by the extractors,
+// until Doc or maxBytes changes.
+func (a *Article) JSONLD(maxBytes int) *parsers.LdJson {
+	if a.Doc == nil {
+		return &parsers.LdJson{}
+	}
+	if a.jsonLD == nil || a.jsonLDDoc != a.Doc || a.jsonLDMaxBytes != maxBytes {
+		a.jsonLD = parsers.ParseLdJson(a.Doc.Selection, maxBytes)
+		a.jsonLDDoc = a.Doc
+		a.jsonLDMaxBytes = maxBytes
+	}
+	return a.jsonLD
+}
+
+// IsArticleType reports whether the page declares itself an article: its
+// og:type is article (ContentType is ContentTypeArticle), or one of its
+// JSON-LD objects has an article type such as NewsArticle or BlogPosting (see
+// constants.ARTICLE_JSONLD_TYPES)
+func (a *Article) IsArticleType() bool {
+	if a.ContentType == ContentTypeArticle || ContentTypeFromOGType(a.OGType) == ContentTypeArticle {
+		return true
+	}
+	for _, obj := range a.jsonLDObjects() {
+		var types []any
+		switch t := obj["@type"].(type) {
+		case string:
+			types = []any{t}
+		case []any:
+			types = t
+		}
+		for _, t := range types {
+			if name, ok := t.(string); ok && isArticleJSONLDType(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isArticleJSONLDType reports whether a schema.org type, possibly written as
+// a URL, is Article or one of its subtypes
+func isArticleJSONLDType(name string) bool {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, articleType := range constants.ARTICLE_JSONLD_TYPES {
+		if strings.EqualFold(name, articleType) {
+			return true
+		}
+	}
+	return false
 }
 
 // ThrowIfNotDownloadedVerbose checks if the article has been downloaded.
@@ -544,7 +1121,7 @@ func (a *Article) ThrowIfNotDownloadedVerbose() error {
 	switch a.DownloadState {
 	case NotStarted:
 		return fmt.Errorf("you must download() an article first")
-	case FailedResponse:
+	case FailedResponse, SoftError, NotHTML:
 		return fmt.Errorf("article download() failed with %s on URL %s", a.DownloadExceptionMsg, a.URL)
 	}
 	return nil
@@ -558,6 +1135,11 @@ func (a *Article) ThrowIfNotParsedVerbose() error {
 	return nil
 }
 
+// IsCoAuthored reports whether the article has more than one author
+func (a *Article) IsCoAuthored() bool {
+	return len(a.Authors) > 1
+}
+
 // IsValidURL checks if the URL is valid.
 func (a *Article) IsValidURL() bool {
 	// Implement URL validation
@@ -600,19 +1182,248 @@ func (a *Article) GetLanguage() language.Tag {
 	return a.Language
 }
 
+// NLPLanguage returns the language used to select stop words for NLP.
+// Detected languages below Config.LangDetectMinConfidence fall back to the
+// configured language, or English when none is configured.
+func (a *Article) NLPLanguage() string {
+	lang := a.GetLanguage().String()
+	if a.LanguageConfidence == 0 || a.Config == nil || a.LanguageConfidence >= a.Config.LangDetectMinConfidence {
+		return lang
+	}
+	if forced := a.Config.Language(); forced != "" {
+		return forced
+	}
+	return language.English.String()
+}
+
+// Entities returns the distinct capitalized phrases of the text, in order of
+// appearance, as a lightweight stand-in for named entity recognition. A phrase
+// is a run of at least two consecutive capitalized words that is not broken
+// by punctuation, once leading and trailing stop words are dropped
+// ("The International Research Institute" gives "International Research
+// Institute"). Single capitalized words are ignored since they cannot be told
+// apart from sentence-initial words. Text in scripts without letter case
+// returns nil.
+func (a *Article) Entities() []string {
+	if !hasLetterCase(a.Text) {
+		return nil
+	}
+
+	stopwords := make(map[string]bool)
+	for _, w := range nlp.GetStopWordsForLanguage(a.NLPLanguage()) {
+		stopwords[strings.ToLower(w)] = true
+	}
+
+	var entities []string
+	seen := make(map[string]bool)
+	var phrase []string
+
+	flush := func() {
+		for len(phrase) > 0 && stopwords[strings.ToLower(phrase[0])] {
+			phrase = phrase[1:]
+		}
+		for len(phrase) > 0 && stopwords[strings.ToLower(phrase[len(phrase)-1])] {
+			phrase = phrase[:len(phrase)-1]
+		}
+		if len(phrase) >= 2 {
+			entity := strings.Join(phrase, " ")
+			if !seen[entity] {
+				seen[entity] = true
+				entities = append(entities, entity)
+			}
+		}
+		phrase = nil
+	}
+
+	for _, token := range strings.Fields(a.Text) {
+		word := strings.TrimLeftFunc(token, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		core := strings.TrimRightFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if len(word) != len(token) {
+			// Opening quotes or brackets start a new phrase
+			flush()
+		}
+
+		first, _ := utf8.DecodeRuneInString(core)
+		if core == "" || !unicode.IsUpper(first) {
+			flush()
+			continue
+		}
+		phrase = append(phrase, core)
+		if len(core) != len(word) {
+			// Trailing punctuation ends the phrase
+			flush()
+		}
+	}
+	flush()
+
+	return entities
+}
+
+// hasLetterCase reports whether most letters of text belong to a script with
+// upper and lower case
+func hasLetterCase(text string) bool {
+	letters, cased := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.IsUpper(r) || unicode.IsLower(r) {
+			cased++
+		}
+	}
+	return letters > 0 && cased*2 >= letters
+}
+
+// SectionFromURL returns the first chunk of the URL path that is a known
+// section (Config.SectionVocabulary or constants.URL_SECTIONS), such as
+// "sport" for /sport/football/match-report-123. It is a fallback for pages
+// without an article:section meta tag and returns "" when nothing matches.
+func (a *Article) SectionFromURL() string {
+	u, err := url.Parse(a.URL)
+	if err != nil {
+		return ""
+	}
+	vocabulary := constants.URL_SECTIONS
+	if a.Config != nil && len(a.Config.SectionVocabulary) > 0 {
+		vocabulary = a.Config.SectionVocabulary
+	}
+	for _, chunk := range strings.Split(u.Path, "/") {
+		chunk = strings.ToLower(chunk)
+		if chunk != "" && slices.Contains(vocabulary, chunk) {
+			return chunk
+		}
+	}
+	return ""
+}
+
+// datelinePattern matches paragraphs that are only a dateline, e.g.
+// "WASHINGTON (Reuters) —" or "PARIS, March 3 -"
+var datelinePattern = regexp.MustCompile(`^[\p{Lu}][\p{Lu}\s.,'-]*(\([^)]*\))?(,\s*\p{L}+\.?\s+\d{1,2}(,\s*\d{4})?)?\s*[-–—:]?$`)
+
+// FirstParagraph returns the text of the first substantive paragraph of the
+// article body, a teaser for pages without description. Paragraphs shorter
+// than Config.FirstParagraphMinWords words, datelines and image captions are
+// skipped. It returns "" before parsing or when no paragraph qualifies.
+func (a *Article) FirstParagraph() string {
+	if a.TopNode == nil {
+		return ""
+	}
+	minWords := 12
+	if a.Config != nil && a.Config.FirstParagraphMinWords > 0 {
+		minWords = a.Config.FirstParagraphMinWords
+	}
+	var paragraph string
+	a.TopNode.Find("p").EachWithBreak(func(i int, p *goquery.Selection) bool {
+		if p.Closest("figure, figcaption").Length() > 0 || isCaptionNode(p) {
+			return true
+		}
+		text := parsers.InnerTrim(p.Text())
+		if len(strings.Fields(text)) < minWords || datelinePattern.MatchString(text) {
+			return true
+		}
+		paragraph = text
+		return false
+	})
+	return paragraph
+}
+
+// isCaptionNode tells whether p is a caption kept by the cleaner, or whether
+// its class marks a caption, credit or dateline
+func isCaptionNode(p *goquery.Selection) bool {
+	if p.AttrOr("data-role", "") == "caption" {
+		return true
+	}
+	class := strings.ToLower(p.AttrOr("class", ""))
+	return strings.Contains(class, "caption") || strings.Contains(class, "credit") || strings.Contains(class, "dateline")
+}
+
+// ID returns a stable identifier for the article: a hash of its canonical
+// URL, or of its URL before parsing, normalized so that re-crawls of the same
+// page get the same ID whatever the scheme, "www." prefix, fragment, tracking
+// parameters or trailing slash. Articles without URL are identified by their
+// title and text instead. It returns "" when there is nothing to hash.
+func (a *Article) ID() string {
+	var key string
+	if link := cmp.Or(a.CanonicalLink, a.URL); link != "" {
+		key = "url:" + normalizeIDURL(link)
+	} else if words := strings.Fields(strings.ToLower(a.Title + "\n" + a.Text)); len(words) > 0 {
+		key = "content:" + strings.Join(words, " ")
+	} else {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// normalizeIDURL reduces link to the host, path and sorted query that
+// identify the page
+func normalizeIDURL(link string) string {
+	link = urls.PrepareURL(strings.TrimSpace(link), "")
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return link
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	normalized := host + u.EscapedPath()
+	if u.RawQuery != "" {
+		normalized += "?" + u.RawQuery
+	}
+	return normalized
+}
+
 func (a *Article) SetLanguage(lang language.Tag) {
 	a.Language = lang
 }
 
-// GetCleanDoc returns the cleaned version of the document
+// newDocumentCleaner creates the document cleaner configured by a.Config
+func (a *Article) newDocumentCleaner() *cleaner.DocumentCleaner {
+	if a.Config == nil {
+		return cleaner.NewDocumentCleaner()
+	}
+	patterns := a.Config.CaptionCreditPatterns
+	if len(patterns) == 0 {
+		patterns = constants.CAPTION_CREDIT_PATTERNS
+	}
+	return cleaner.NewDocumentCleanerWithOptions(cleaner.Options{
+		KeepCaptions:          a.Config.KeepImageCaptions,
+		CaptionCreditPatterns: patterns,
+		StripEmptyBlocks:      a.Config.StripEmptyBlocks,
+	})
+}
+
+// AddDiagnostic records a diagnostic on the article, unless the same one was
+// already recorded
+func (a *Article) AddDiagnostic(severity DiagnosticSeverity, message string) {
+	diagnostic := Diagnostic{Severity: severity, Message: message}
+	if !slices.Contains(a.Diagnostics, diagnostic) {
+		a.Diagnostics = append(a.Diagnostics, diagnostic)
+	}
+}
+
+// CleanNode cleans node in place with the document cleaner configured by
+// Config, as for the article body, and returns it
+func (a *Article) CleanNode(node *goquery.Selection) *goquery.Selection {
+	return a.newDocumentCleaner().Clean(node)
+}
+
+// GetCleanDoc returns the cleaned version of the document. Its <head> is
+// kept unless Config.CleanDocKeepHead is false. The result is cached in
+// CleanDoc.
 func (a *Article) GetCleanDoc() *goquery.Document {
 	if a.CleanDoc == nil && a.Doc != nil {
-		documentCleaner := cleaner.NewDocumentCleaner()
+		documentCleaner := a.newDocumentCleaner()
 		// Clone the document for cleaning
 		docHTML := parsers.OuterHTML(a.Doc.Find("html").First())
 		var err error
 		a.CleanDoc, err = goquery.NewDocumentFromReader(strings.NewReader(docHTML))
 		if err == nil {
+			if a.Config != nil && !a.Config.CleanDocKeepHead {
+				a.CleanDoc.Find("head").Remove()
+			}
 			// Convert document to selection for cleaning
 			rootSelection := a.CleanDoc.Find("html")
 			if rootSelection.Length() == 0 {
@@ -636,6 +1447,41 @@ func (a *Article) ToFullJSON() (string, error) {
 		return "", fmt.Errorf("you must parse() an article first: %w", err)
 	}
 
+	b, err := json.Marshal(a.fullJSONData())
+	if err != nil {
+		return "", fmt.Errorf("error marshaling article to JSON: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// ToJSONFields serializes only the requested fields. Field names are the keys
+// produced by ToFullJSON; unknown names are rejected.
+func (a *Article) ToJSONFields(fields ...string) (string, error) {
+	if err := a.ThrowIfNotParsedVerbose(); err != nil {
+		return "", fmt.Errorf("you must parse() an article first: %w", err)
+	}
+
+	fullData := a.fullJSONData()
+	articleData := make(map[string]any, len(fields))
+	for _, field := range fields {
+		value, ok := fullData[field]
+		if !ok {
+			return "", fmt.Errorf("unknown article JSON field %q", field)
+		}
+		articleData[field] = value
+	}
+
+	b, err := json.Marshal(articleData)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling article to JSON: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// fullJSONData builds the map of all serialized Article fields
+func (a *Article) fullJSONData() map[string]any {
 	// Prepare serializable representations for complex fields
 	var topNodeHTML string
 	if a.TopNode != nil {
@@ -667,68 +1513,85 @@ func (a *Article) ToFullJSON() (string, error) {
 		}
 	}
 
-	// Format publish date
-	var publishDate any
-	if a.PublishDate != nil {
-		publishDate = a.PublishDate.Format(time.RFC3339)
-	} else {
-		publishDate = nil
-	}
+	publishDate, publishDateEpoch := a.publishDateJSON()
 
 	// Build a full map containing all Article fields (serialized)
 	articleData := map[string]any{
-		"source_url":       a.SourceURL,
-		"url":              a.URL,
-		"title":            a.Title,
-		"top_image":        a.TopImage,
-		"meta_img":         a.MetaImg,
-		"images":           a.Images,
-		"movies":           a.Movies,
-		"text":             a.Text,
-		"keywords":         a.Keywords,
-		"keyword_scores":   a.KeywordScores,
-		"meta_keywords":    a.MetaKeywords,
-		"tags":             a.Tags,
-		"authors":          a.Authors,
-		"publish_date":     publishDate,
-		"summary":          a.Summary,
-		"html":             a.HTML,
-		"article_html":     a.ArticleHTML,
-		"is_parsed":        a.IsParsed,
-		"meta_description": a.MetaDescription,
-		"meta_lang":        a.MetaLang,
-		"meta_favicon":     a.MetaFavicon,
-		"meta_site_name":   a.MetaSiteName,
-		"meta_data":        a.MetaData,
-		"canonical_link":   a.CanonicalLink,
-		"categories":       categories,
-		"top_node_html":    topNodeHTML,
-		"doc_html":         docHTML,
-		"clean_doc_html":   cleanDocHTML,
-		"language":         a.GetLanguage().String(),
-		"bitcoins":         a.Bitcoins,
-		"md5s":             a.MD5s,
-		"sha1s":            a.SHA1s,
-		"sha256s":          a.SHA256s,
-		"sha512s":          a.SHA512s,
-		"domains":          a.Domains,
-		"emails":           a.Emails,
-		"ipv4s":            a.IPv4s,
-		"ipv6s":            a.IPv6s,
-		"other_urls":       a.OtherURLs,
-		"files":            a.Files,
-		"cves":             a.CVEs,
-		"capecs":           a.CAPECs,
-		"cwes":             a.CWEs,
-		"cpes":             a.CPEs,
-	}
-
-	b, err := json.Marshal(articleData)
-	if err != nil {
-		return "", fmt.Errorf("error marshaling article to JSON: %w", err)
-	}
-
-	return string(b), nil
+		"source_url":            a.SourceURL,
+		"url":                   a.URL,
+		"via_url":               a.ViaURL,
+		"title":                 a.Title,
+		"raw_title":             a.RawTitle,
+		"top_image":             a.TopImage,
+		"top_image_width":       a.TopImageWidth,
+		"top_image_height":      a.TopImageHeight,
+		"top_image_orientation": a.TopImageOrientation,
+		"meta_img":              a.MetaImg,
+		"images":                a.Images,
+		"movies":                a.Movies,
+		"videos":                a.Videos,
+		"audio":                 a.Audio,
+		"tables":                a.Tables,
+		"text":                  a.Text,
+		"keywords":              a.Keywords,
+		"keyword_scores":        a.KeywordScores,
+		"meta_keywords":         a.MetaKeywords,
+		"tags":                  a.Tags,
+		"authors":               a.Authors,
+		"author_count":          len(a.Authors),
+		"publish_date":          publishDate,
+		"publish_date_epoch":    publishDateEpoch,
+		"update_history":        a.UpdateHistory,
+		"modified_date":         a.modifiedDateJSON(),
+		"summary":               a.Summary,
+		"html":                  a.HTML,
+		"article_html":          a.ArticleHTML,
+		"is_parsed":             a.IsParsed,
+		"download_state":        a.DownloadState,
+		"meta_description":      a.MetaDescription,
+		"meta_lang":             a.MetaLang,
+		"language_source":       a.LanguageSource,
+		"meta_favicon":          a.MetaFavicon,
+		"meta_site_name":        a.MetaSiteName,
+		"meta_data":             a.MetaData,
+		"og_type":               a.OGType,
+		"breadcrumbs":           a.Breadcrumbs,
+		"rating":                a.Rating,
+		"part_of":               a.PartOf,
+		"part_of_url":           a.PartOfURL,
+		"dublin_core":           a.DublinCore,
+		"doi":                   a.DOI,
+		"raw_json_ld":           a.RawJSONLD,
+		"content_type":          a.ContentType,
+		"canonical_link":        a.CanonicalLink,
+		"duplicate_of":          a.DuplicateOf,
+		"prev_url":              a.PrevURL,
+		"next_url":              a.NextURL,
+		"discussion_url":        a.DiscussionURL,
+		"diagnostics":           a.Diagnostics,
+		"categories":            categories,
+		"top_node_html":         topNodeHTML,
+		"doc_html":              docHTML,
+		"clean_doc_html":        cleanDocHTML,
+		"language":              a.GetLanguage().String(),
+		"bitcoins":              a.Bitcoins,
+		"md5s":                  a.MD5s,
+		"sha1s":                 a.SHA1s,
+		"sha256s":               a.SHA256s,
+		"sha512s":               a.SHA512s,
+		"domains":               a.Domains,
+		"emails":                a.Emails,
+		"ipv4s":                 a.IPv4s,
+		"ipv6s":                 a.IPv6s,
+		"other_urls":            a.OtherURLs,
+		"files":                 a.Files,
+		"cves":                  a.CVEs,
+		"capecs":                a.CAPECs,
+		"cwes":                  a.CWEs,
+		"cpes":                  a.CPEs,
+	}
+
+	return articleData
 }
 
 func (a *Article) ToJSON() (string, error) {
@@ -736,35 +1599,39 @@ func (a *Article) ToJSON() (string, error) {
 		return "", fmt.Errorf("you must parse() an article first: %w", err)
 	}
 
+	publishDate, publishDateEpoch := a.publishDateJSON()
+
 	// Build a simplified map containing key Article fields
 	articleData := map[string]any{
-		"title":            a.Title,
-		"url":              a.URL,
-		"authors":          a.Authors,
-		"publish_date":     a.PublishDate,
-		"top_image":        a.TopImage,
-		"meta_description": a.MetaDescription,
-		"keywords":         a.Keywords,
-		"summary":          a.Summary,
-		"text":             a.Text,
-		"language":         a.GetLanguage().String(),
-		"bitcoins":         a.Bitcoins,
-		"md5s":             a.MD5s,
-		"sha1s":            a.SHA1s,
-		"sha256s":          a.SHA256s,
-		"sha512s":          a.SHA512s,
-		"domains":          a.Domains,
-		"emails":           a.Emails,
-		"ipv4s":            a.IPv4s,
-		"ipv6s":            a.IPv6s,
-		"other_urls":       a.OtherURLs,
-		"files":            a.Files,
-		"cves":             a.CVEs,
-		"capecs":           a.CAPECs,
-		"cwes":             a.CWEs,
-		"cpes":             a.CPEs,
-		"images":           a.Images,
-		"movies":           a.Movies,
+		"title":              a.Title,
+		"url":                a.URL,
+		"authors":            a.Authors,
+		"author_count":       len(a.Authors),
+		"publish_date":       publishDate,
+		"publish_date_epoch": publishDateEpoch,
+		"top_image":          a.TopImage,
+		"meta_description":   a.MetaDescription,
+		"keywords":           a.Keywords,
+		"summary":            a.Summary,
+		"text":               a.Text,
+		"language":           a.GetLanguage().String(),
+		"bitcoins":           a.Bitcoins,
+		"md5s":               a.MD5s,
+		"sha1s":              a.SHA1s,
+		"sha256s":            a.SHA256s,
+		"sha512s":            a.SHA512s,
+		"domains":            a.Domains,
+		"emails":             a.Emails,
+		"ipv4s":              a.IPv4s,
+		"ipv6s":              a.IPv6s,
+		"other_urls":         a.OtherURLs,
+		"files":              a.Files,
+		"cves":               a.CVEs,
+		"capecs":             a.CAPECs,
+		"cwes":               a.CWEs,
+		"cpes":               a.CPEs,
+		"images":             a.Images,
+		"movies":             a.Movies,
 	}
 
 	b, err := json.Marshal(articleData)
@@ -775,6 +1642,24 @@ func (a *Article) ToJSON() (string, error) {
 	return string(b), nil
 }
 
+// publishDateJSON returns the publish date as an RFC3339 string and as Unix
+// seconds, both nil when the date is unknown, so that every serializer
+// formats it the same way
+func (a *Article) publishDateJSON() (any, any) {
+	if a.PublishDate == nil {
+		return nil, nil
+	}
+	return a.PublishDate.Format(time.RFC3339), a.PublishDate.Unix()
+}
+
+// modifiedDateJSON returns the RFC 3339 form of ModifiedDate, nil when unknown
+func (a *Article) modifiedDateJSON() any {
+	if a.ModifiedDate == nil {
+		return nil
+	}
+	return a.ModifiedDate.Format(time.RFC3339)
+}
+
 // cleanKeyword filters keywords to ensure they are simple words with no special characters and minimum 3 characters
 func (a *Article) cleanKeyword(keyword string) string {
 	// Remove special characters and keep only letters
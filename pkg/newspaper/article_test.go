@@ -1 +1,553 @@
 package newspaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"golang.org/x/text/language"
+)
+
+func TestAudioInfoJSON(t *testing.T) {
+	audio := AudioInfo{URL: "https://cdn.example.com/episode-42.mp3", MimeType: "audio/mpeg", Duration: 32*time.Minute + 15500*time.Millisecond}
+	out, err := json.Marshal(audio)
+	if err != nil {
+		t.Fatalf("Error serializing the audio: %v", err)
+	}
+	if expected := `{"url":"https://cdn.example.com/episode-42.mp3","mime_type":"audio/mpeg","duration":1935.5}`; string(out) != expected {
+		t.Errorf("Expected %s, got %s", expected, out)
+	}
+	var decoded AudioInfo
+	if err := json.Unmarshal(out, &decoded); err != nil || decoded != audio {
+		t.Errorf("Expected %+v after a round trip, got %+v, %v", audio, decoded, err)
+	}
+}
+
+func TestArticleDownloadStateJSON(t *testing.T) {
+	for _, state := range []ArticleDownloadState{NotStarted, FailedResponse, Success, SoftError, NotHTML} {
+		out, err := json.Marshal(state)
+		if err != nil {
+			t.Fatalf("%v: error serializing the state: %v", state, err)
+		}
+		if expected := `"` + state.String() + `"`; string(out) != expected {
+			t.Errorf("Expected %s, got %s", expected, out)
+		}
+		var decoded ArticleDownloadState
+		if err := json.Unmarshal(out, &decoded); err != nil || decoded != state {
+			t.Errorf("Expected %v after a round trip, got %v, %v", state, decoded, err)
+		}
+		// Integers serialized by older versions are still accepted
+		decoded = NotStarted
+		if err := json.Unmarshal([]byte(fmt.Sprint(int(state))), &decoded); err != nil || decoded != state {
+			t.Errorf("Expected %v from its integer value, got %v, %v", state, decoded, err)
+		}
+	}
+	if Success.String() != "Success" || ArticleDownloadState(42).String() != "ArticleDownloadState(42)" {
+		t.Errorf("Unexpected names %q and %q", Success, ArticleDownloadState(42))
+	}
+	var decoded ArticleDownloadState
+	if err := json.Unmarshal([]byte(`"Downloading"`), &decoded); err == nil {
+		t.Error("Expected an error for an unknown state name")
+	}
+
+	a := &Article{Config: configuration.NewConfiguration(), IsParsed: true, DownloadState: Success}
+	out, err := a.ToFullJSON()
+	if err != nil {
+		t.Fatalf("Error serializing the article: %v", err)
+	}
+	if !strings.Contains(out, `"download_state":"Success"`) {
+		t.Errorf("Expected the download state in %s", out)
+	}
+}
+
+func TestArticleJSONLD(t *testing.T) {
+	parse := func(html string) *goquery.Document {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+		if err != nil {
+			t.Fatalf("Error parsing HTML: %v", err)
+		}
+		return doc
+	}
+	a := &Article{Doc: parse(`<script type="application/ld+json">{"headline": "Storm"}</script>`)}
+
+	first := a.JSONLD(0)
+	if len(first.Objects) != 1 || first.Objects[0]["headline"] != "Storm" {
+		t.Fatalf("Expected the JSON-LD object of the page, got %v", first.Objects)
+	}
+	if a.JSONLD(0) != first {
+		t.Error("Expected the decoded blocks to be shared by the following calls")
+	}
+	if a.JSONLD(10) == first {
+		t.Error("Expected another size limit to decode the blocks again")
+	}
+	a.Doc = parse(`<script type="application/ld+json">{"headline": "Flood"}</script>`)
+	if objects := a.JSONLD(0).Objects; len(objects) != 1 || objects[0]["headline"] != "Flood" {
+		t.Errorf("Expected the blocks of the new document, got %v", objects)
+	}
+}
+
+func TestArticleToJSONFields(t *testing.T) {
+	a := &Article{
+		Config:        configuration.NewConfiguration(),
+		URL:           "https://example.com/news/story",
+		Title:         "Example story",
+		Text:          "Body of the example story.",
+		Authors:       []string{"Jane Doe"},
+		IsParsed:      true,
+		DownloadState: Success,
+	}
+
+	out, err := a.ToJSONFields("title", "text")
+	if err != nil {
+		t.Fatalf("ToJSONFields returned an error: %v", err)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(out), &data); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(data) != 2 {
+		t.Errorf("Expected only the requested keys, got %v", data)
+	}
+	if data["title"] != a.Title || data["text"] != a.Text {
+		t.Errorf("Unexpected values %v", data)
+	}
+
+	if _, err := a.ToJSONFields("title", "not_a_field"); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+}
+
+func TestArticleJSONPublishDate(t *testing.T) {
+	published := time.Date(2024, 3, 5, 14, 30, 0, 0, time.FixedZone("CET", 3600))
+	a := &Article{
+		Config:        configuration.NewConfiguration(),
+		URL:           "https://example.com/news/story",
+		Title:         "Example story",
+		PublishDate:   &published,
+		IsParsed:      true,
+		DownloadState: Success,
+	}
+
+	decode := func(serialize func() (string, error)) map[string]any {
+		t.Helper()
+		out, err := serialize()
+		if err != nil {
+			t.Fatalf("Serialization returned an error: %v", err)
+		}
+		var data map[string]any
+		if err := json.Unmarshal([]byte(out), &data); err != nil {
+			t.Fatalf("Output is not valid JSON: %v", err)
+		}
+		return data
+	}
+
+	short, full := decode(a.ToJSON), decode(a.ToFullJSON)
+	if short["publish_date"] != "2024-03-05T14:30:00+01:00" || full["publish_date"] != short["publish_date"] {
+		t.Errorf("Expected identical RFC3339 dates, got %v and %v", short["publish_date"], full["publish_date"])
+	}
+	if short["publish_date_epoch"] != float64(published.Unix()) || full["publish_date_epoch"] != short["publish_date_epoch"] {
+		t.Errorf("Expected identical epochs, got %v and %v", short["publish_date_epoch"], full["publish_date_epoch"])
+	}
+
+	a.PublishDate = nil
+	short, full = decode(a.ToJSON), decode(a.ToFullJSON)
+	for _, data := range []map[string]any{short, full} {
+		for _, key := range []string{"publish_date", "publish_date_epoch"} {
+			if value, ok := data[key]; !ok || value != nil {
+				t.Errorf("Expected %s to be null, got %v", key, value)
+			}
+		}
+	}
+}
+
+// longArticleText builds a live-blog style text of at least size bytes dominated by "volcano"
+func longArticleText(size int) string {
+	sentences := []string{
+		"The volcano erupted again on %s morning, sending ash high above the island.",
+		"Residents living near the volcano were told to leave their homes before %s.",
+		"Scientists monitoring the volcano recorded hundreds of small earthquakes on %s.",
+		"Airlines cancelled flights on %s as the ash cloud from the volcano drifted north.",
+		"Officials said on %s that the lava flow from the volcano had slowed down.",
+	}
+	days := []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}
+
+	var b strings.Builder
+	for i := 0; b.Len() < size; i++ {
+		fmt.Fprintf(&b, sentences[i%len(sentences)], days[(i/len(sentences))%len(days)])
+		b.WriteString(" ")
+		if i%5 == 4 {
+			b.WriteString("\n\n")
+		}
+	}
+	return b.String()
+}
+
+func newParsedArticle(text string) *Article {
+	return &Article{
+		Config:        configuration.NewConfiguration(),
+		Title:         "Volcano eruption: live updates",
+		Text:          text,
+		IsParsed:      true,
+		DownloadState: Success,
+	}
+}
+
+func TestArticleNLPMaxTextLength(t *testing.T) {
+	text := longArticleText(300000)
+	a := newParsedArticle(text)
+	a.Config.MaxTextLength = 20000
+
+	if err := a.NLP(); err != nil {
+		t.Fatalf("NLP returned an error: %v", err)
+	}
+
+	if !a.TextTruncatedForNLP {
+		t.Error("Expected TextTruncatedForNLP to be set")
+	}
+	if a.Text != text || a.TextTruncated {
+		t.Error("Text should be kept in full unless TruncateText is set")
+	}
+	if !slices.Contains(a.Keywords, "volcano") {
+		t.Errorf("Expected keywords to contain the dominant term, got %v", a.Keywords)
+	}
+	if a.Summary == "" {
+		t.Error("Expected a summary from the retained text")
+	}
+
+	short := newParsedArticle(longArticleText(1000))
+	short.Config.MaxTextLength = 20000
+	if err := short.NLP(); err != nil {
+		t.Fatalf("NLP returned an error: %v", err)
+	}
+	if short.TextTruncatedForNLP {
+		t.Error("Short texts should not be truncated")
+	}
+}
+
+func TestTruncateHeadTail(t *testing.T) {
+	text := "alpha beta gamma delta epsilon zeta eta theta iota kappa"
+	out := truncateHeadTail(text, 30, 0.5)
+	if !strings.HasPrefix(out, "alpha beta") || !strings.HasSuffix(out, "iota kappa") {
+		t.Errorf("Expected head and tail to be kept, got %q", out)
+	}
+	if len(out) > 32 {
+		t.Errorf("Expected output close to the limit, got %d bytes", len(out))
+	}
+}
+
+func BenchmarkArticleNLPLongText(b *testing.B) {
+	text := longArticleText(300000)
+	for _, maxLength := range []int{0, 20000} {
+		b.Run(fmt.Sprintf("MaxTextLength=%d", maxLength), func(b *testing.B) {
+			for b.Loop() {
+				a := newParsedArticle(text)
+				a.Config.MaxTextLength = maxLength
+				if err := a.NLP(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestArticleEntities(t *testing.T) {
+	a := newParsedArticle("The International Research Institute published its annual report on Monday. " +
+		"According to the report, Maria Gonzalez and the European Space Agency will lead the new programme. " +
+		"Researchers said the International Research Institute would share its data. Yesterday the results were published.")
+
+	entities := a.Entities()
+	expected := []string{"International Research Institute", "Maria Gonzalez", "European Space Agency"}
+	if !slices.Equal(entities, expected) {
+		t.Errorf("Expected entities %v, got %v", expected, entities)
+	}
+
+	caseless := newParsedArticle("東京大学の研究チームは月曜日に新しい報告書を発表した。")
+	if entities := caseless.Entities(); entities != nil {
+		t.Errorf("Expected no entities for a script without case, got %v", entities)
+	}
+}
+
+// khmerText is a short news paragraph in Khmer, a language without stop words
+const khmerText = "រដ្ឋាភិបាល កម្ពុជា បាន ប្រកាស ថា ផ្លូវ ថ្មី នឹង បើក នៅ ខែ ក្រោយ។ " +
+	"ផ្លូវ ថ្មី នេះ ភ្ជាប់ ទីក្រុង ភ្នំពេញ ទៅ ខេត្ត សៀមរាប។ " +
+	"រដ្ឋាភិបាល និយាយ ថា ផ្លូវ ថ្មី នឹង ជួយ ដល់ ការ ធ្វើ ដំណើរ និង ពាណិជ្ជកម្ម។"
+
+func TestArticleNLPNoStopwords(t *testing.T) {
+	a := newParsedArticle(khmerText)
+	a.Title = "ផ្លូវ ថ្មី"
+	a.SetLanguage(language.Khmer)
+
+	if err := a.NLP(); err != nil {
+		t.Fatalf("NLP returned an error: %v", err)
+	}
+	if !a.NLPSkippedNoStopwords {
+		t.Error("Expected NLPSkippedNoStopwords to be set")
+	}
+	if len(a.Keywords) != 0 || a.Summary != "" {
+		t.Errorf("Expected no keywords and summary, got %v and %q", a.Keywords, a.Summary)
+	}
+
+	a = newParsedArticle(khmerText)
+	a.Title = "ផ្លូវ ថ្មី"
+	a.SetLanguage(language.Khmer)
+	a.Config.NoStopwordsNLP = configuration.NoStopwordsFrequency
+
+	if err := a.NLP(); err != nil {
+		t.Fatalf("NLP returned an error: %v", err)
+	}
+	if !a.NLPSkippedNoStopwords {
+		t.Error("Expected NLPSkippedNoStopwords to be set")
+	}
+	if len(a.Keywords) == 0 || len(a.Keywords) > noStopwordsMaxKeywords {
+		t.Fatalf("Expected up to %d frequency keywords, got %v", noStopwordsMaxKeywords, a.Keywords)
+	}
+	if a.Keywords[0] != "ផ្លូវ" && a.Keywords[0] != "ថ្មី" {
+		t.Errorf("Expected the most frequent word first, got %v", a.Keywords)
+	}
+	if a.Summary != "" {
+		t.Errorf("Expected no summary, got %q", a.Summary)
+	}
+
+	english := newParsedArticle(longArticleText(2000))
+	if err := english.NLP(); err != nil {
+		t.Fatalf("NLP returned an error: %v", err)
+	}
+	if english.NLPSkippedNoStopwords || len(english.Keywords) == 0 {
+		t.Error("Expected English NLP to be unaffected")
+	}
+}
+
+func TestArticleSectionFromURL(t *testing.T) {
+	tests := []struct {
+		url        string
+		vocabulary []string
+		expected   string
+	}{
+		{"https://example.com/sport/football/match-report-123", nil, "sport"},
+		{"https://example.com/2025/01/06/Politics/budget-vote.html", nil, "politics"},
+		{"https://example.com/story/match-report-123", nil, ""},
+		{"https://example.com/rugby/six-nations/report", []string{"rugby"}, "rugby"},
+		{"https://example.com/sport/rugby/report", []string{"rugby"}, "rugby"},
+	}
+
+	for _, tt := range tests {
+		config := configuration.NewConfiguration()
+		config.SectionVocabulary = tt.vocabulary
+		a := &Article{URL: tt.url, Config: config}
+		if got := a.SectionFromURL(); got != tt.expected {
+			t.Errorf("SectionFromURL(%s) = %q, expected %q", tt.url, got, tt.expected)
+		}
+	}
+}
+
+func TestContentTypeFromOGType(t *testing.T) {
+	tests := map[string]ContentType{
+		"article":     ContentTypeArticle,
+		" Article ":   ContentTypeArticle,
+		"video.other": ContentTypeVideo,
+		"website":     ContentTypeWebsite,
+		"profile":     ContentTypeProfile,
+		"music.song":  ContentTypeOther,
+		"":            "",
+	}
+	for ogType, expected := range tests {
+		if got := ContentTypeFromOGType(ogType); got != expected {
+			t.Errorf("ContentTypeFromOGType(%q) = %q, expected %q", ogType, got, expected)
+		}
+	}
+}
+
+func TestArticleIsArticleType(t *testing.T) {
+	tests := []struct {
+		ogType   string
+		jsonLD   string
+		expected bool
+	}{
+		{ogType: "website", expected: false},
+		{ogType: "article", expected: true},
+		{ogType: "video.other", expected: false},
+		{ogType: "website", jsonLD: `{"@type": "NewsArticle", "headline": "Storm hits the coast"}`, expected: true},
+		{jsonLD: `{"@graph": [{"@type": "WebPage"}, {"@type": ["Thing", "https://schema.org/BlogPosting"]}]}`, expected: true},
+		{jsonLD: `{"@type": "WebSite", "name": "Local News"}`, expected: false},
+	}
+	for _, tt := range tests {
+		html := `<html><head><script type="application/ld+json">` + tt.jsonLD + `</script></head><body></body></html>`
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+		if err != nil {
+			t.Fatalf("Error parsing HTML: %v", err)
+		}
+		a := &Article{Config: configuration.NewConfiguration(), Doc: doc, OGType: tt.ogType, ContentType: ContentTypeFromOGType(tt.ogType)}
+		if got := a.IsArticleType(); got != tt.expected {
+			t.Errorf("og:type %q, JSON-LD %s: IsArticleType() = %v, expected %v", tt.ogType, tt.jsonLD, got, tt.expected)
+		}
+	}
+}
+
+func TestArticleID(t *testing.T) {
+	id := func(a *Article) string {
+		t.Helper()
+		id := a.ID()
+		if len(id) != 32 {
+			t.Fatalf("Expected a 32 character ID, got %q", id)
+		}
+		return id
+	}
+
+	story := id(&Article{URL: "https://www.example.com/news/story?id=4&utm_source=feed#comments"})
+	for _, variant := range []string{
+		"https://www.example.com/news/story?id=4",
+		"http://example.com/news/story/?id=4",
+		"HTTPS://EXAMPLE.COM/news/story?utm_medium=rss&id=4",
+	} {
+		if got := id(&Article{URL: variant}); got != story {
+			t.Errorf("Expected %s to have the ID %s, got %s", variant, story, got)
+		}
+	}
+	if got := id(&Article{URL: "https://example.com/amp/story", CanonicalLink: "https://example.com/news/story?id=4"}); got != story {
+		t.Errorf("Expected the canonical link to give the ID %s, got %s", story, got)
+	}
+	for _, other := range []string{
+		"https://www.example.com/news/story?id=5",
+		"https://www.example.com/news/other-story?id=4",
+		"https://www.example.org/news/story?id=4",
+	} {
+		if got := id(&Article{URL: other}); got == story {
+			t.Errorf("Expected %s to have a different ID", other)
+		}
+	}
+
+	text := id(&Article{Title: "Storm hits the coast", Text: "The storm reached the coast on Monday."})
+	if got := id(&Article{Title: "Storm hits the coast", Text: "The storm  reached the coast\non Monday."}); got != text {
+		t.Errorf("Expected the same text to give the ID %s, got %s", text, got)
+	}
+	if got := id(&Article{Title: "Storm leaves the coast", Text: "The storm left the coast on Tuesday."}); got == text {
+		t.Errorf("Expected a different text to give a different ID")
+	}
+	if got := (&Article{}).ID(); got != "" {
+		t.Errorf("Expected no ID for an empty article, got %s", got)
+	}
+}
+
+func TestArticleCleanDocHead(t *testing.T) {
+	const html = `<html><head><title>Storm</title><meta name="description" content="A storm hit the coast"></head>
+<body><article><p>The storm hit the coast on Monday.</p></article></body></html>`
+
+	for _, keepHead := range []bool{true, false} {
+		config := configuration.NewConfiguration()
+		config.CleanDocKeepHead = keepHead
+		a := &Article{Config: config, HTML: html}
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+		if err != nil {
+			t.Fatalf("Error parsing HTML: %v", err)
+		}
+		a.Doc = doc
+
+		cleanDoc := a.GetCleanDoc()
+		if cleanDoc == nil {
+			t.Fatal("Expected a clean document")
+		}
+		if kept := cleanDoc.Find(`head meta[name="description"]`).Length() == 1; kept != keepHead {
+			t.Errorf("CleanDocKeepHead=%v: expected the meta description kept=%v, got %v", keepHead, keepHead, kept)
+		}
+		if !strings.Contains(cleanDoc.Find("body").Text(), "The storm hit the coast") {
+			t.Errorf("CleanDocKeepHead=%v: expected the body to be kept", keepHead)
+		}
+	}
+}
+
+func TestArticleSummaryWeights(t *testing.T) {
+	text := "The city council voted the budget for schools and the budget for transport on Monday evening. " +
+		"Council members said the budget for schools would grow while the budget for transport would stay flat. " +
+		"The budget vote followed weeks of debate between council members about schools and transport. " +
+		"Several residents attended the council meeting to speak about the schools budget. " +
+		"Fishing boats returned to the harbour on Tuesday after the storm. "
+	summarize := func(weights configuration.SummaryWeights) string {
+		a := &Article{
+			Config:        configuration.NewConfiguration(),
+			Title:         "Harbour bridge reopens after repairs",
+			Text:          text,
+			IsParsed:      true,
+			DownloadState: Success,
+		}
+		a.Config.MaxSummarySent = 1
+		a.Config.SummaryWeights = weights
+		if err := a.NLP(); err != nil {
+			t.Fatalf("NLP returned an error: %v", err)
+		}
+		return a.Summary
+	}
+
+	const harbour = "Fishing boats returned to the harbour on Tuesday after the storm"
+	if summary := summarize(configuration.NewConfiguration().SummaryWeights); summary == harbour {
+		t.Errorf("Expected the default weights to favor the keyword-dense sentences, got %q", summary)
+	}
+	if summary := summarize(configuration.SummaryWeights{Title: 10, Frequency: 0.5, Length: 1, Position: 1}); summary != harbour {
+		t.Errorf("Expected the title-heavy weights to pick the sentence about the title, got %q", summary)
+	}
+}
+
+func TestArticleMaxSummaryChars(t *testing.T) {
+	a := newParsedArticle(longArticleText(2000))
+	a.Config.MaxSummarySent = 5
+	if err := a.NLP(); err != nil {
+		t.Fatalf("NLP returned an error: %v", err)
+	}
+	full := a.Summary
+	if utf8.RuneCountInString(full) < 200 {
+		t.Fatalf("Expected a long summary, got %q", full)
+	}
+
+	maxChars := utf8.RuneCountInString(full) - 20
+	a.Config.MaxSummaryChars = maxChars
+	if err := a.NLP(); err != nil {
+		t.Fatalf("NLP returned an error: %v", err)
+	}
+	if utf8.RuneCountInString(a.Summary) > maxChars {
+		t.Errorf("Expected at most %d characters, got %d", maxChars, utf8.RuneCountInString(a.Summary))
+	}
+	if a.Summary == "" || !strings.HasPrefix(full, a.Summary+" ") {
+		t.Errorf("Expected the summary to be cut at a sentence boundary, got %q", a.Summary)
+	}
+
+	if got := joinSummary([]string{"A single sentence far longer than the cap."}, 20); got != "A single sentence" {
+		t.Errorf("Expected a sentence longer than the cap to be cut on whitespace, got %q", got)
+	}
+}
+
+func TestArticleKeySentences(t *testing.T) {
+	a := newParsedArticle(longArticleText(2000))
+	sentences := a.KeySentences(3)
+	if len(sentences) != 3 {
+		t.Fatalf("Expected 3 key sentences, got %d: %+v", len(sentences), sentences)
+	}
+	for i, s := range sentences {
+		if i > 0 && s.Index <= sentences[i-1].Index {
+			t.Errorf("Expected the key sentences in the order of the text, got %+v", sentences)
+		}
+		if s.Score <= 0 || !strings.Contains(a.Text, s.Sentence) {
+			t.Errorf("Expected a scored sentence of the text, got %+v", s)
+		}
+	}
+
+	a.Config.MaxSummarySent = 3
+	if err := a.NLP(); err != nil {
+		t.Fatalf("NLP returned an error: %v", err)
+	}
+	var joined []string
+	for _, s := range sentences {
+		joined = append(joined, s.Sentence)
+	}
+	if a.Summary != strings.Join(joined, " ") {
+		t.Errorf("Expected the summary to join the key sentences, got %q", a.Summary)
+	}
+	if a.KeySentences(0) != nil || (&Article{Config: a.Config}).KeySentences(3) != nil {
+		t.Error("Expected no key sentences without text or count")
+	}
+}
@@ -27,7 +27,8 @@ func IsValidCategoryURL(urlStr string) bool {
 		return false
 	}
 
-	if parsedURL.Host == "" || parsedURL.TLD == "" || parsedURL.Domain == "" {
+	// IP and intranet hosts have no TLD
+	if parsedURL.Host == "" || parsedURL.Domain == "" {
 		return false
 	}
 
@@ -1,7 +1,150 @@
 package newspaper
 
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/parsers"
+)
+
 // Feed represents an RSS feed from a news source
 type Feed struct {
 	URL string
 	RSS string
+	// RedirectChain lists the URLs the feed URL redirected through, from URL
+	// to the URL the feed was served at, empty when it was not redirected
+	RedirectChain []string
+	// PollingHints are the polling directives declared by the feed, nil when
+	// it declares none
+	PollingHints *PollingHints
+}
+
+// PollingHints are the channel-level directives of a feed telling how often
+// it should be polled
+type PollingHints struct {
+	TTL             time.Duration // <ttl>, how long the feed can be cached
+	UpdatePeriod    string        // <sy:updatePeriod>: hourly, daily, weekly, monthly or yearly
+	UpdateFrequency int           // <sy:updateFrequency>, updates per UpdatePeriod
+	SkipHours       []int         // <skipHours>, GMT hours (0-23) during which the feed is not updated
+	SkipDays        []string      // <skipDays>, days during which the feed is not updated, e.g. "Sunday"
+}
+
+// updatePeriods are the durations of the sy:updatePeriod values
+var updatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// ParsePollingHints reads the polling directives of an RSS, RDF or Atom
+// feed: <ttl>, <sy:updatePeriod>, <sy:updateFrequency>, <skipHours> and
+// <skipDays>. Item-level elements are ignored. It returns nil when the feed
+// declares none.
+func ParsePollingHints(rss string) *PollingHints {
+	if rss == "" {
+		return nil
+	}
+	doc, err := parsers.FromString(rss)
+	if err != nil {
+		return nil
+	}
+	channelText := func(selector string) []string {
+		var values []string
+		doc.Find(selector).Each(func(i int, s *goquery.Selection) {
+			if s.ParentsFiltered("item, entry").Length() > 0 {
+				return
+			}
+			if value := strings.TrimSpace(s.Text()); value != "" {
+				values = append(values, value)
+			}
+		})
+		return values
+	}
+
+	hints := &PollingHints{}
+	found := false
+	if values := channelText("ttl"); len(values) > 0 {
+		if minutes, err := strconv.Atoi(values[0]); err == nil && minutes > 0 {
+			hints.TTL = time.Duration(minutes) * time.Minute
+			found = true
+		}
+	}
+	if values := channelText(`sy\:updateperiod`); len(values) > 0 {
+		if period := strings.ToLower(values[0]); updatePeriods[period] > 0 {
+			hints.UpdatePeriod = period
+			found = true
+		}
+	}
+	if values := channelText(`sy\:updatefrequency`); len(values) > 0 {
+		if frequency, err := strconv.Atoi(values[0]); err == nil && frequency > 0 {
+			hints.UpdateFrequency = frequency
+			found = true
+		}
+	}
+	for _, value := range channelText("skiphours hour") {
+		if hour, err := strconv.Atoi(value); err == nil && hour >= 0 && hour <= 24 {
+			hints.SkipHours = append(hints.SkipHours, hour%24)
+			found = true
+		}
+	}
+	for _, value := range channelText("skipdays day") {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(value, day.String()) {
+				hints.SkipDays = append(hints.SkipDays, day.String())
+				found = true
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	return hints
+}
+
+// SuggestedPollInterval returns how often the feed should be polled from its
+// polling hints:
+//   - sy:updatePeriod divided by sy:updateFrequency (1 when missing) when
+//     the feed declares an update period
+//   - else the TTL when the feed declares one
+//   - else defaultInterval
+//
+// The TTL being how long the feed may be cached, the interval is never
+// shorter than it. Skipped hours and days do not change the interval, see
+// PollingHints.Skips.
+func (f Feed) SuggestedPollInterval(defaultInterval time.Duration) time.Duration {
+	h := f.PollingHints
+	if h == nil {
+		return defaultInterval
+	}
+	interval := defaultInterval
+	if period, ok := updatePeriods[h.UpdatePeriod]; ok {
+		interval = period / time.Duration(max(h.UpdateFrequency, 1))
+	} else if h.TTL > 0 {
+		interval = h.TTL
+	}
+	return max(interval, h.TTL)
+}
+
+// Skips reports whether t falls in the skipped hours or days of the feed,
+// which are expressed in GMT
+func (h *PollingHints) Skips(t time.Time) bool {
+	if h == nil {
+		return false
+	}
+	t = t.UTC()
+	for _, hour := range h.SkipHours {
+		if t.Hour() == hour {
+			return true
+		}
+	}
+	for _, day := range h.SkipDays {
+		if t.Weekday().String() == day {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,89 @@
+package newspaper
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFeedPollingHints(t *testing.T) {
+	const defaultInterval = 15 * time.Minute
+	rss := func(channel string) string {
+		return `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:sy="http://purl.org/rss/1.0/modules/syndication/">
+<channel>
+<title>Example News</title>
+<link>https://www.example.com/</link>
+` + channel + `
+<item><title>Story</title><link>https://www.example.com/story</link><ttl>5</ttl></item>
+</channel>
+</rss>`
+	}
+
+	for _, tc := range []struct {
+		name     string
+		channel  string
+		hints    *PollingHints
+		interval time.Duration
+	}{
+		{
+			name:     "ttl",
+			channel:  `<ttl>120</ttl>`,
+			hints:    &PollingHints{TTL: 2 * time.Hour},
+			interval: 2 * time.Hour,
+		},
+		{
+			name:     "update period",
+			channel:  `<sy:updatePeriod>hourly</sy:updatePeriod><sy:updateFrequency>2</sy:updateFrequency>`,
+			hints:    &PollingHints{UpdatePeriod: "hourly", UpdateFrequency: 2},
+			interval: 30 * time.Minute,
+		},
+		{
+			name:     "update period shorter than ttl",
+			channel:  `<ttl>60</ttl><sy:updatePeriod>Hourly</sy:updatePeriod><sy:updateFrequency>2</sy:updateFrequency>`,
+			hints:    &PollingHints{TTL: time.Hour, UpdatePeriod: "hourly", UpdateFrequency: 2},
+			interval: time.Hour,
+		},
+		{
+			name:     "skip hours",
+			channel:  `<skipHours><hour>0</hour><hour>1</hour><hour>2</hour></skipHours><skipDays><day>sunday</day></skipDays>`,
+			hints:    &PollingHints{SkipHours: []int{0, 1, 2}, SkipDays: []string{"Sunday"}},
+			interval: defaultInterval,
+		},
+		{
+			name:     "no hints",
+			interval: defaultInterval,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			feed := Feed{URL: "https://www.example.com/rss", RSS: rss(tc.channel)}
+			feed.PollingHints = ParsePollingHints(feed.RSS)
+			if tc.hints == nil {
+				if feed.PollingHints != nil {
+					t.Fatalf("Expected no polling hints, got %+v", feed.PollingHints)
+				}
+			} else if h := feed.PollingHints; h == nil || h.TTL != tc.hints.TTL || h.UpdatePeriod != tc.hints.UpdatePeriod ||
+				h.UpdateFrequency != tc.hints.UpdateFrequency || !slices.Equal(h.SkipHours, tc.hints.SkipHours) || !slices.Equal(h.SkipDays, tc.hints.SkipDays) {
+				t.Fatalf("Expected polling hints %+v, got %+v", tc.hints, h)
+			}
+			if interval := feed.SuggestedPollInterval(defaultInterval); interval != tc.interval {
+				t.Errorf("Expected a poll interval of %v, got %v", tc.interval, interval)
+			}
+		})
+	}
+
+	hints := &PollingHints{SkipHours: []int{0, 1, 2}, SkipDays: []string{"Sunday"}}
+	for _, tc := range []struct {
+		at    time.Time
+		skips bool
+	}{
+		{time.Date(2025, 1, 6, 1, 30, 0, 0, time.UTC), true},
+		{time.Date(2025, 1, 6, 3, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 1, 6, 3, 30, 0, 0, time.FixedZone("CET", 3600)), true},
+		{time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC), true},
+	} {
+		if skips := hints.Skips(tc.at); skips != tc.skips {
+			t.Errorf("Expected Skips(%v) to be %v", tc.at, tc.skips)
+		}
+	}
+}
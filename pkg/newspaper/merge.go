@@ -0,0 +1,166 @@
+package newspaper
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/internal/helpers"
+	"github.com/tguidoux/newspaper4k-go/internal/urls"
+)
+
+// MergedArticle is the consolidated record of a cluster of articles about
+// the same story, see MergeArticles
+type MergedArticle struct {
+	Title       string         `json:"title"`
+	Text        string         `json:"text"`
+	PublishDate *time.Time     `json:"publish_date"`
+	Authors     []string       `json:"authors"`
+	Images      []string       `json:"images"`
+	Keywords    []string       `json:"keywords"`
+	SourceURLs  []MergedSource `json:"source_urls"`
+}
+
+// MergedSource is a member of a merged cluster
+type MergedSource struct {
+	URL    string `json:"url"`
+	Outlet string `json:"outlet"` // Registrable domain of URL, e.g. example.co.uk
+}
+
+// MergeArticles consolidates a cluster of articles about the same story:
+//   - PublishDate is the earliest non-nil publication date
+//   - Title comes from the most complete article, the one with the longest
+//     body and the most metadata, the first one on ties
+//   - Text is the longest valid body (see IsValidBody), or the longest body
+//     when none is valid
+//   - Authors, Images (top image included) and Keywords are the unions of
+//     those of the members, without duplicates, authors and keywords being
+//     compared case-insensitively
+//   - SourceURLs lists the URL and outlet of every member
+//
+// Nil members are ignored. It returns nil for an empty cluster.
+func MergeArticles(cluster []*Article) *MergedArticle {
+	var members []*Article
+	for _, a := range cluster {
+		if a != nil {
+			members = append(members, a)
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	merged := &MergedArticle{
+		Authors:    []string{},
+		Images:     []string{},
+		Keywords:   []string{},
+		SourceURLs: []MergedSource{},
+	}
+	var best *Article
+	var longest, longestValid string
+	for _, a := range members {
+		if a.PublishDate != nil && (merged.PublishDate == nil || a.PublishDate.Before(*merged.PublishDate)) {
+			date := *a.PublishDate
+			merged.PublishDate = &date
+		}
+		if best == nil || qualityScore(a) > qualityScore(best) {
+			best = a
+		}
+		if len(a.Text) > len(longest) {
+			longest = a.Text
+		}
+		if isValidBody(a) && len(a.Text) > len(longestValid) {
+			longestValid = a.Text
+		}
+
+		merged.Authors = append(merged.Authors, a.Authors...)
+		if a.TopImage != "" {
+			merged.Images = append(merged.Images, a.TopImage)
+		}
+		merged.Images = append(merged.Images, a.Images...)
+		merged.Keywords = append(merged.Keywords, a.Keywords...)
+		merged.SourceURLs = append(merged.SourceURLs, MergedSource{URL: a.URL, Outlet: outlet(a.URL)})
+	}
+
+	merged.Title = best.Title
+	merged.Text = longestValid
+	if merged.Text == "" {
+		merged.Text = longest
+	}
+	caseInsensitive := helpers.UniqueOptions{CaseSensitive: false, PreserveOrder: true}
+	merged.Authors = helpers.UniqueStrings(merged.Authors, caseInsensitive)
+	merged.Images = helpers.UniqueStringsSimple(merged.Images)
+	merged.Keywords = helpers.UniqueStrings(merged.Keywords, caseInsensitive)
+	return merged
+}
+
+// MergeFrom enriches a with the extracted fields of other, e.g. a stub built
+// from a feed item (URL, title, date) with the fully parsed page. The empty
+// fields of a (zero values, empty slices and maps) take the value of other.
+// When both articles have a value, a keeps its own unless
+// preferOtherOnConflict is set. The configuration of a is kept, and slices,
+// maps and documents are shared with other, not copied.
+func (a *Article) MergeFrom(other *Article, preferOtherOnConflict bool) {
+	if other == nil || other == a {
+		return
+	}
+	dst := reflect.ValueOf(a).Elem()
+	src := reflect.ValueOf(other).Elem()
+	for i := range dst.NumField() {
+		field := dst.Type().Field(i)
+		if !field.IsExported() || field.Name == "Config" {
+			continue
+		}
+		value := src.Field(i)
+		if isEmptyValue(value) {
+			continue
+		}
+		if isEmptyValue(dst.Field(i)) || preferOtherOnConflict {
+			dst.Field(i).Set(value)
+		}
+	}
+}
+
+// isEmptyValue reports whether v is a zero value or an empty slice or map
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// qualityScore rates the completeness of an article between 0 and 1: half
+// for a body of Config.MinWordCount words, the rest for the title, authors,
+// publication date, top image and description
+func qualityScore(a *Article) float64 {
+	minWords := 300
+	if a.Config != nil && a.Config.MinWordCount > 0 {
+		minWords = a.Config.MinWordCount
+	}
+	score := 0.5 * min(float64(len(strings.Fields(a.Text)))/float64(minWords), 1)
+	for _, present := range []bool{a.Title != "", len(a.Authors) > 0, a.PublishDate != nil, a.TopImage != "", a.MetaDescription != ""} {
+		if present {
+			score += 0.1
+		}
+	}
+	return score
+}
+
+// isValidBody is IsValidBody for articles that may have no configuration
+func isValidBody(a *Article) bool {
+	return a.Config != nil && a.IsValidBody()
+}
+
+// outlet returns the registrable domain of rawURL, or its host when it
+// cannot be determined
+func outlet(rawURL string) string {
+	if u, err := urls.Parse(rawURL); err == nil && u.Domain != "" && u.TLD != "" {
+		return strings.ToLower(u.Domain + "." + u.TLD)
+	}
+	if u, err := url.Parse(rawURL); err == nil {
+		return strings.ToLower(u.Hostname())
+	}
+	return ""
+}
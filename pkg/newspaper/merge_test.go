@@ -0,0 +1,144 @@
+package newspaper
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+)
+
+func TestMergeArticles(t *testing.T) {
+	config := configuration.NewConfiguration()
+	config.MinWordCount = 20
+	date := func(day int) *time.Time {
+		d := time.Date(2025, 3, day, 9, 0, 0, 0, time.UTC)
+		return &d
+	}
+	wire := &Article{
+		Config:      config,
+		URL:         "https://www.wire.example.com/world/storm-hits-the-coast",
+		Title:       "Storm hits the coast",
+		Text:        strings.Repeat("The storm reached the coast on Monday and damaged the harbour. ", 4),
+		Authors:     []string{"Jane Doe"},
+		PublishDate: date(14),
+		TopImage:    "https://cdn.example.com/storm.jpg",
+		Images:      []string{"https://cdn.example.com/storm.jpg", "https://cdn.example.com/harbour.jpg"},
+		Keywords:    []string{"storm", "coast"},
+		IsParsed:    true,
+	}
+	local := &Article{
+		Config:          config,
+		URL:             "https://news.example.co.uk/2025/03/13/storm.html",
+		Title:           "STORM!!! Coast battered",
+		Text:            strings.Repeat("Residents of the coast woke up to a storm that flooded the streets near the harbour. ", 3),
+		Authors:         []string{"jane doe", "John Smith"},
+		PublishDate:     date(13),
+		TopImage:        "https://cdn.example.com/harbour.jpg",
+		Images:          []string{"https://cdn.example.com/harbour.jpg"},
+		Keywords:        []string{"Storm", "flood"},
+		IsParsed:        true,
+		MetaDescription: "A storm battered the coast",
+	}
+	teaser := &Article{
+		URL:      "https://aggregator.example.org/s/123",
+		Title:    "Storm hits the coast, harbour damaged and many other details in a very long title",
+		Text:     "A storm hit the coast.",
+		Keywords: []string{"weather"},
+	}
+
+	merged := MergeArticles([]*Article{teaser, wire, nil, local})
+	if merged == nil {
+		t.Fatal("Expected a merged article")
+	}
+	if merged.PublishDate == nil || !merged.PublishDate.Equal(*date(13)) {
+		t.Errorf("Expected the earliest publication date, got %v", merged.PublishDate)
+	}
+	if merged.Title != "STORM!!! Coast battered" {
+		t.Errorf("Expected the title of the most complete article, got %q", merged.Title)
+	}
+	if merged.Text != local.Text {
+		t.Errorf("Expected the longest valid body, got %q", merged.Text)
+	}
+	if expected := []string{"Jane Doe", "John Smith"}; !slices.Equal(merged.Authors, expected) {
+		t.Errorf("Expected authors %v, got %v", expected, merged.Authors)
+	}
+	if expected := []string{"https://cdn.example.com/storm.jpg", "https://cdn.example.com/harbour.jpg"}; !slices.Equal(merged.Images, expected) {
+		t.Errorf("Expected images %v, got %v", expected, merged.Images)
+	}
+	if expected := []string{"weather", "storm", "coast", "flood"}; !slices.Equal(merged.Keywords, expected) {
+		t.Errorf("Expected keywords %v, got %v", expected, merged.Keywords)
+	}
+	expectedSources := []MergedSource{
+		{URL: teaser.URL, Outlet: "example.org"},
+		{URL: wire.URL, Outlet: "example.com"},
+		{URL: local.URL, Outlet: "example.co.uk"},
+	}
+	if !slices.Equal(merged.SourceURLs, expectedSources) {
+		t.Errorf("Expected sources %v, got %v", expectedSources, merged.SourceURLs)
+	}
+
+	out, err := json.Marshal(merged)
+	if err != nil {
+		t.Fatalf("Error serializing the merged article: %v", err)
+	}
+	if !strings.Contains(string(out), `"publish_date":"2025-03-13T09:00:00Z"`) || !strings.Contains(string(out), `"outlet":"example.co.uk"`) {
+		t.Errorf("Unexpected JSON %s", out)
+	}
+
+	if MergeArticles([]*Article{nil}) != nil {
+		t.Error("Expected no merged article for an empty cluster")
+	}
+}
+
+func TestArticleMergeFrom(t *testing.T) {
+	feedDate := time.Date(2025, 3, 14, 6, 0, 0, 0, time.UTC)
+	pageDate := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)
+	stub := func() *Article {
+		date := feedDate
+		return &Article{
+			Config:      configuration.NewConfiguration(),
+			URL:         "https://news.example.com/2025/03/14/storm.html",
+			Title:       "Storm hits the coast",
+			PublishDate: &date,
+			Images:      []string{},
+		}
+	}
+	parsed := &Article{
+		Config:        configuration.NewConfiguration(),
+		URL:           "https://news.example.com/2025/03/14/storm.html",
+		Title:         "Storm hits the coast, harbour damaged",
+		Text:          "The storm reached the coast on Monday and damaged the harbour.",
+		ArticleHTML:   "<p>The storm reached the coast on Monday and damaged the harbour.</p>",
+		Authors:       []string{"Jane Doe"},
+		PublishDate:   &pageDate,
+		Images:        []string{"https://cdn.example.com/storm.jpg"},
+		IsParsed:      true,
+		DownloadState: Success,
+	}
+
+	a := stub()
+	config := a.Config
+	a.MergeFrom(parsed, false)
+	if !a.PublishDate.Equal(feedDate) || a.Title != "Storm hits the coast" {
+		t.Errorf("Expected the date and title of the feed to be kept, got %v and %q", a.PublishDate, a.Title)
+	}
+	if a.Text != parsed.Text || a.ArticleHTML != parsed.ArticleHTML {
+		t.Errorf("Expected the body of the parsed page, got %q", a.Text)
+	}
+	if !slices.Equal(a.Authors, parsed.Authors) || !slices.Equal(a.Images, parsed.Images) {
+		t.Errorf("Expected the empty lists to be filled, got %v and %v", a.Authors, a.Images)
+	}
+	if !a.IsParsed || a.DownloadState != Success || a.Config != config {
+		t.Errorf("Expected the parse state of the page and the configuration of the stub, got %v, %v", a.IsParsed, a.DownloadState)
+	}
+
+	a = stub()
+	a.MergeFrom(parsed, true)
+	if !a.PublishDate.Equal(pageDate) || a.Title != parsed.Title {
+		t.Errorf("Expected the values of the page to win, got %v and %q", a.PublishDate, a.Title)
+	}
+	a.MergeFrom(nil, true)
+}
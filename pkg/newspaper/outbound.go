@@ -0,0 +1,137 @@
+package newspaper
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/helpers"
+	"github.com/tguidoux/newspaper4k-go/internal/urls"
+	"github.com/tguidoux/newspaper4k-go/pkg/constants"
+)
+
+// followOutboundStory replaces the teaser of an aggregator page by the full
+// story it links to, see Config.FollowOutboundCanonical. The aggregator URL
+// is kept in ViaURL. Articles reached this way are not followed any further,
+// and the teaser is kept when the story cannot be downloaded or parsed, or
+// when the robots.txt or the robots directives of the story forbid it.
+func (a *Article) followOutboundStory(extractors []Extractor, observe ExtractorObserver) {
+	if a.ViaURL != "" || len(strings.Fields(a.Text)) >= a.Config.MinWordCount || slices.Contains(a.RobotsDirectives, "nofollow") {
+		return
+	}
+	target := a.outboundStoryURL()
+	if target == "" {
+		return
+	}
+
+	if !a.robotsAllowed(target) {
+		a.AddDiagnostic(DiagnosticInfo, fmt.Sprintf("outbound story %s not followed: disallowed by robots.txt", target))
+		return
+	}
+
+	config := *a.Config
+	config.DownloadOptions.InputHTML = ""
+	story := &Article{
+		Config:        &config,
+		SourceURL:     a.SourceURL,
+		URL:           target,
+		ViaURL:        a.URL,
+		DownloadState: NotStarted,
+	}
+	if err := story.Download(); err != nil {
+		a.AddDiagnostic(DiagnosticError, fmt.Sprintf("outbound story %s not followed: %v", target, err))
+		return
+	}
+	if err := story.ParseObserved(extractors, observe); err != nil {
+		a.AddDiagnostic(DiagnosticError, fmt.Sprintf("outbound story %s not followed: %v", target, err))
+		return
+	}
+
+	if story.NoIndex {
+		a.AddDiagnostic(DiagnosticInfo, fmt.Sprintf("outbound story %s not followed: the publisher asked not to index it", target))
+		return
+	}
+
+	story.Config = a.Config
+	if story.CanonicalLink == "" {
+		story.CanonicalLink = target
+	}
+	*a = *story
+}
+
+// robotsAllowed tells whether the robots.txt of the site of target lets the
+// article crawler fetch it. A missing robots.txt allows everything, an
+// unreachable one nothing.
+func (a *Article) robotsAllowed(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	userAgent := a.Config.RequestsParams.NextUserAgent()
+	resp, err := helpers.DefaultFetcher.Get(a.httpClient(), u.Scheme+"://"+u.Host+"/robots.txt", helpers.FetchOptions{
+		RecentCacheSize: a.Config.RecentDownloadCacheSize,
+		RecentCacheTTL:  time.Duration(a.Config.RecentDownloadCacheTTLSeconds) * time.Second,
+		UserAgent:       userAgent,
+		Headers:         a.Config.RequestsParams.Headers,
+		MaxRetries:      a.Config.RequestsParams.MaxRetries,
+		RetryBackoff:    a.Config.RequestsParams.RetryBackoff,
+	})
+	switch {
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		return false
+	case resp.StatusCode >= http.StatusBadRequest:
+		return true
+	}
+	return helpers.RobotsAllowed(string(resp.Body), userAgent, u.RequestURI())
+}
+
+// outboundStoryURL returns the URL of the full story an aggregator page
+// links to: its canonical link when it is on another site, else the single
+// off-site target of the links introduced by OUTBOUND_STORY_PATTERNS. It
+// returns "" when there is none or several, or when the target is not in
+// Config.OutboundCanonicalDomains.
+func (a *Article) outboundStoryURL() string {
+	here := outlet(a.URL)
+	allowed := func(target string) bool {
+		site := outlet(target)
+		if site == "" || site == here || target == a.URL {
+			return false
+		}
+		return len(a.Config.OutboundCanonicalDomains) == 0 || slices.ContainsFunc(a.Config.OutboundCanonicalDomains, func(domain string) bool {
+			return strings.EqualFold(domain, site)
+		})
+	}
+
+	if a.CanonicalLink != "" && isHTTPURL(a.CanonicalLink) && allowed(a.CanonicalLink) {
+		return a.CanonicalLink
+	}
+	if a.Doc == nil {
+		return ""
+	}
+
+	var targets []string
+	a.Doc.Find("a[href]").Each(func(i int, link *goquery.Selection) {
+		target := urls.JoinURL(a.URL, strings.TrimSpace(link.AttrOr("href", "")))
+		if !isHTTPURL(target) || !allowed(target) || slices.Contains(targets, target) {
+			return
+		}
+		context := strings.ToLower(strings.Join(strings.Fields(link.Parent().Text()), " "))
+		if slices.ContainsFunc(constants.OUTBOUND_STORY_PATTERNS, func(pattern string) bool { return strings.Contains(context, pattern) }) {
+			targets = append(targets, target)
+		}
+	})
+	if len(targets) != 1 {
+		return ""
+	}
+	return targets[0]
+}
+
+// isHTTPURL reports whether rawURL is an absolute http or https URL
+func isHTTPURL(rawURL string) bool {
+	lower := strings.ToLower(rawURL)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
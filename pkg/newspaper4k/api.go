@@ -16,11 +16,14 @@ func DefaultExtractors(config *configuration.Configuration) []newspaper.Extracto
 		newspaper4k.NewTitleExtractor(config),
 		newspaper4k.NewAuthorsExtractor(config),
 		newspaper4k.NewPubdateExtractor(config),
+		newspaper4k.NewUpdateHistoryExtractor(config),
 		newspaper4k.NewBodyExtractor(config),
+		newspaper4k.NewTableExtractor(config),
 		newspaper4k.NewLanguageExtractor(config), // Run twice to ensure language is set after text extraction
 		newspaper4k.NewCategoryExtractor(config),
 		newspaper4k.NewImageExtractor(config),
-		newspaper4k.NewVideoExtractor(),
+		newspaper4k.NewVideoExtractor(config),
+		newspaper4k.NewAudioExtractor(config),
 		newspaper4k.NewIOCsExtractor(config),
 	}
 }
@@ -1,8 +1,24 @@
 package newspaper4k
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/parsers"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/extractors/newspaper4k"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
 )
 
 const testHTML = `
@@ -165,8 +181,8 @@ func TestNewArticleFromHTML(t *testing.T) {
 		t.Error("Article should be parsed")
 	}
 
-	if art.DownloadState != 2 { // Success
-		t.Error("Download state should be Success")
+	if art.DownloadState != newspaper.Success {
+		t.Errorf("Download state should be Success, got %v", art.DownloadState)
 	}
 }
 
@@ -199,6 +215,52 @@ func TestArticleAuthors(t *testing.T) {
 			t.Errorf("Expected author %q not found in authors list: %v", expected, art.Authors)
 		}
 	}
+
+	if !art.IsCoAuthored() {
+		t.Error("Expected the article to be co-authored")
+	}
+	out, err := art.ToJSON()
+	if err != nil {
+		t.Fatalf("Error serializing article: %v", err)
+	}
+	if !strings.Contains(out, fmt.Sprintf(`"author_count":%d`, len(art.Authors))) {
+		t.Errorf("Expected the author count in the JSON, got %s", out)
+	}
+}
+
+func TestArticleNewsKeywords(t *testing.T) {
+	html := `<html><head><title>Storm hits the coast</title>
+<meta name="news_keywords" content="Storm, Lisbon harbour , flooding">
+<meta name="keywords" content="weather, storm, news">
+</head><body><article><h1>Storm hits the coast</h1><p>The storm hit the coast on Monday, closing roads, schools and ports across the region.</p></article></body></html>`
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	if expected := []string{"Storm", "Lisbon harbour", "flooding", "weather", "news"}; !slices.Equal(art.MetaKeywords, expected) {
+		t.Errorf("Expected meta keywords %q, got %q", expected, art.MetaKeywords)
+	}
+}
+
+func TestArticleMetaDataAllowlist(t *testing.T) {
+	art, err := NewArticleFromHTML(testHTML)
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	art.Config.MetaDataAllowlist = []string{"description", "OG:TITLE"}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if len(art.MetaData) != 2 || art.MetaData["description"] == "" || art.MetaData["og:title"] == "" {
+		t.Errorf("Expected only description and og:title in the metadata, got %v", art.MetaData)
+	}
+	if art.MetaDescription == "" {
+		t.Error("Expected the meta description to be extracted whatever the allowlist")
+	}
 }
 
 func TestArticleMetaData(t *testing.T) {
@@ -491,3 +553,2133 @@ func TestArticleIsValidBody(t *testing.T) {
 	// The test HTML has limited content, so IsValidBody may return false
 	// This is expected behavior
 }
+
+func TestArticleVideosSkipAds(t *testing.T) {
+	html := `<html><head><title>Video filtering</title></head><body>
+	<article>
+		<p>The legitimate embedded video is part of the article body.</p>
+		<iframe src="https://www.youtube.com/embed/dQw4w9WgXcQ"></iframe>
+		<iframe src="https://www.youtube.com/embed/"></iframe>
+	</article>
+	<aside class="advert">
+		<iframe src="https://www.youtube-nocookie.com/embed/promo12345"></iframe>
+	</aside>
+	<div id="outstream-player"><video src="https://ads.example.com/preroll.mp4"></video></div>
+	<script type="application/ld+json">
+	{"@context": "https://schema.org", "@type": "VideoObject", "embedUrl": "https://www.youtube.com/embed/dQw4w9WgXcQ?autoplay=1"}
+	</script>
+</body></html>`
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+
+	err = art.Build(DefaultExtractors(art.Config))
+	if err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	expected := []string{"https://www.youtube.com/embed/dQw4w9WgXcQ"}
+	if len(art.Movies) != len(expected) || art.Movies[0] != expected[0] {
+		t.Errorf("Expected movies %v, got %v", expected, art.Movies)
+	}
+
+	// The classes of the page, e.g. of a site showing ads, do not make an ad slot
+	for _, page := range []string{
+		`<html><head><title>Storm</title></head><body class="page has-ads"><article class="sponsored-ready">
+<p>The storm hit the coast on Monday.</p><iframe src="https://www.youtube.com/embed/dQw4w9WgXcQ"></iframe>
+</article></body></html>`,
+		`<html><head><title>Storm</title></head><body class="ad-layout"><div class="story">
+<p>The storm hit the coast on Monday.</p><iframe src="https://www.youtube.com/embed/dQw4w9WgXcQ"></iframe>
+</div></body></html>`,
+	} {
+		art, err := NewArticleFromHTML(page)
+		if err != nil {
+			t.Fatalf("Error creating article: %v", err)
+		}
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+		if !slices.Equal(art.Movies, expected) {
+			t.Errorf("Expected movies %v despite the page classes, got %v", expected, art.Movies)
+		}
+	}
+}
+
+func TestArticleVideosProviderDenylist(t *testing.T) {
+	art, err := NewArticleFromHTML(testHTML)
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	art.Config.VideoProviderDenylist = []string{"vimeo"}
+
+	err = art.Build(DefaultExtractors(art.Config))
+	if err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	for _, video := range art.Movies {
+		if strings.Contains(video, "vimeo") {
+			t.Errorf("Denylisted provider should be skipped, got %v", art.Movies)
+		}
+	}
+}
+
+func TestArticleSeriesLinks(t *testing.T) {
+	html := `<html><head>
+	<title>Part two of the series</title>
+	<link rel="prev" href="/series/part-1">
+	<link rel="next" href="https://example.com/series/part-3">
+</head><body><p>Second part.</p></body></html>`
+
+	req := NewDefaultParseRequest("https://example.com/series/part-2")
+	req.InputHTML = html
+	art, err := NewArticleFromRequest(req)
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+
+	err = art.Build(DefaultExtractors(art.Config))
+	if err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if art.PrevURL != "https://example.com/series/part-1" {
+		t.Errorf("Expected PrevURL %q, got %q", "https://example.com/series/part-1", art.PrevURL)
+	}
+	if art.NextURL != "https://example.com/series/part-3" {
+		t.Errorf("Expected NextURL %q, got %q", "https://example.com/series/part-3", art.NextURL)
+	}
+}
+
+func TestScoreDocumentMatchesPipeline(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(testHTML))
+	if err != nil {
+		t.Fatalf("Error parsing HTML: %v", err)
+	}
+	before, _ := doc.Html()
+
+	score, err := newspaper4k.ScoreDocument(doc, "en")
+	if err != nil {
+		t.Fatalf("Error scoring document: %v", err)
+	}
+	if score.TopNode == nil || score.TopNode.Length() == 0 {
+		t.Fatal("Top node should not be empty")
+	}
+
+	after, _ := doc.Html()
+	if before != after {
+		t.Error("Scoring should not mutate the document")
+	}
+
+	if len(score.Candidates) == 0 || score.Candidates[0].Node.Get(0) != score.TopNode.Get(0) {
+		t.Fatal("Top node should be the first ranked candidate")
+	}
+	for i := 1; i < len(score.Candidates); i++ {
+		if score.Candidates[i].Score > score.Candidates[i-1].Score {
+			t.Errorf("Candidates should be ranked by score, got %v before %v", score.Candidates[i-1].Score, score.Candidates[i].Score)
+		}
+	}
+	if f := score.FeaturesOf(score.TopNode); f == nil || f.GravityScore != score.TopScore {
+		t.Errorf("Top node features should carry its score %v, got %+v", score.TopScore, f)
+	}
+
+	art, err := NewArticleFromHTML(testHTML)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	err = art.Build(DefaultExtractors(art.Config))
+	if err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	// Cleaned as the pipeline cleans its top node, the node of ScoreDocument
+	// gives the same text
+	if text := parsers.GetText(art.CleanNode(score.TopNode.Clone())); art.Text == "" || text != art.Text {
+		t.Errorf("Pipeline and ScoreDocument should agree on the top node, got %q and %q", art.Text, text)
+	}
+	if !strings.Contains(score.TopNode.Text(), "scientists at the International Research Institute") {
+		t.Error("Top node should contain the article body")
+	}
+}
+
+func TestArticleNLPLanguageLowConfidence(t *testing.T) {
+	html := `<html><head><title>Le chat</title></head><body><p>Le chat</p></body></html>`
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	err = art.Build(DefaultExtractors(art.Config))
+	if err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if art.MetaLang != "fr" {
+		t.Errorf("Expected detected language %q to be recorded, got %q", "fr", art.MetaLang)
+	}
+	if lang := art.NLPLanguage(); lang != "fr" {
+		t.Errorf("Expected NLP to use the detected language %q without a threshold, got %q", "fr", lang)
+	}
+
+	art.Config.LangDetectMinConfidence = 0.8
+	if art.LanguageConfidence >= art.Config.LangDetectMinConfidence {
+		t.Fatalf("Expected a low confidence detection, got %v", art.LanguageConfidence)
+	}
+	if lang := art.NLPLanguage(); lang != "en" {
+		t.Errorf("Expected NLP to fall back to %q, got %q", "en", lang)
+	}
+
+	art.Config.LangDetectMinConfidence = art.LanguageConfidence / 2
+	if lang := art.NLPLanguage(); lang != "fr" {
+		t.Errorf("Expected NLP to use detected language %q above threshold, got %q", "fr", lang)
+	}
+}
+
+func TestArticleUTF16Fixtures(t *testing.T) {
+	for _, fixture := range []string{"testdata/article_utf16le.html", "testdata/article_utf16be.html"} {
+		content, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatalf("Error reading fixture %s: %v", fixture, err)
+		}
+
+		art, err := NewArticleFromHTML(string(content))
+		if err != nil {
+			t.Fatalf("%s: error creating article from HTML: %v", fixture, err)
+		}
+		err = art.Build(DefaultExtractors(art.Config))
+		if err != nil {
+			t.Fatalf("%s: error building article: %v", fixture, err)
+		}
+
+		if art.Title != "City Council Approves New Harbour Bridge" {
+			t.Errorf("%s: unexpected title %q", fixture, art.Title)
+		}
+		if !strings.Contains(art.Text, "approved the construction of a new harbour bridge") {
+			t.Errorf("%s: text should contain the article body, got %q", fixture, art.Text)
+		}
+		if strings.Contains(art.HTML, "\x00") {
+			t.Errorf("%s: HTML should not contain NUL bytes", fixture)
+		}
+	}
+}
+
+func TestArticleCharsetFixtures(t *testing.T) {
+	fixtures := []struct {
+		path  string
+		title string
+		text  string
+	}{
+		{"testdata/article_latin1.html", "Le conseil municipal approuve le nouveau pont du port", "a approuvé mardi soir la construction d'un nouveau pont"},
+		{"testdata/article_shift_jis.html", "市議会、港の新しい橋の建設を承認", "港に新しい橋を建設する計画を承認しました"},
+	}
+	for _, fixture := range fixtures {
+		content, err := os.ReadFile(fixture.path)
+		if err != nil {
+			t.Fatalf("Error reading fixture %s: %v", fixture.path, err)
+		}
+
+		art, err := NewArticleFromHTML(string(content))
+		if err != nil {
+			t.Fatalf("%s: error creating article from HTML: %v", fixture.path, err)
+		}
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("%s: error building article: %v", fixture.path, err)
+		}
+		if art.Title != fixture.title {
+			t.Errorf("%s: unexpected title %q", fixture.path, art.Title)
+		}
+		if !strings.Contains(art.Text, fixture.text) {
+			t.Errorf("%s: text should contain the decoded body, got %q", fixture.path, art.Text)
+		}
+	}
+
+	// Without its meta tag, the charset of the page comes from the Content-Type header
+	content, err := os.ReadFile("testdata/article_shift_jis.html")
+	if err != nil {
+		t.Fatalf("Error reading fixture: %v", err)
+	}
+	content = bytes.Replace(content, []byte(`<meta charset="Shift_JIS">`), nil, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=Shift_JIS")
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	art, err := NewArticleFromURL(server.URL + "/2025/03/14/bridge.html")
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	if art.Title != "市議会、港の新しい橋の建設を承認" {
+		t.Errorf("Expected the title decoded from the charset of the header, got %q", art.Title)
+	}
+}
+
+func TestArticleAudio(t *testing.T) {
+	html := `<html><head>
+	<title>Episode 42: The future of energy</title>
+	<script type="application/ld+json">
+	{
+		"@context": "https://schema.org",
+		"@type": "PodcastEpisode",
+		"name": "The future of energy",
+		"associatedMedia": {
+			"@type": "MediaObject",
+			"contentUrl": "https://cdn.example.com/podcasts/episode-42.mp3",
+			"duration": "PT32M15S"
+		}
+	}
+	</script>
+</head><body>
+	<audio controls><source src="/podcasts/episode-42.mp3" type="audio/mpeg"></audio>
+	<audio src="https://cdn.example.com/podcasts/trailer.ogg"></audio>
+</body></html>`
+
+	req := NewDefaultParseRequest("https://cdn.example.com/shows/episode-42")
+	req.InputHTML = html
+	art, err := NewArticleFromRequest(req)
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	err = art.Build(DefaultExtractors(art.Config))
+	if err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if len(art.Audio) != 2 {
+		t.Fatalf("Expected 2 audio files, got %v", art.Audio)
+	}
+	episode := art.Audio[0]
+	if episode.URL != "https://cdn.example.com/podcasts/episode-42.mp3" {
+		t.Errorf("Unexpected audio URL %q", episode.URL)
+	}
+	if episode.MimeType != "audio/mpeg" {
+		t.Errorf("Expected MIME type audio/mpeg, got %q", episode.MimeType)
+	}
+	if episode.Duration != 32*time.Minute+15*time.Second {
+		t.Errorf("Expected duration 32m15s, got %v", episode.Duration)
+	}
+	if art.Audio[1].MimeType != "audio/ogg" {
+		t.Errorf("Expected guessed MIME type audio/ogg, got %q", art.Audio[1].MimeType)
+	}
+}
+
+func TestArticleTables(t *testing.T) {
+	html := `<html><head><title>Rainfall in 2024</title></head><body><article>
+	<p>The weather service published the rainfall figures for the year and the results show that the spring was the wettest season of the year.</p>
+	<table>
+		<thead><tr><th>Month</th><th>Rainfall (mm)</th></tr></thead>
+		<tbody>
+			<tr><td>March</td><td>112</td></tr>
+			<tr><td>April</td><td> 98 </td></tr>
+		</tbody>
+	</table>
+	<p>The figures are higher than the average of the last ten years and the service said that the trend is likely to continue.</p>
+</article></body></html>`
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	art.Config.ExtractTables = true
+	err = art.Build(DefaultExtractors(art.Config))
+	if err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if len(art.Tables) != 1 {
+		t.Fatalf("Expected 1 table, got %d", len(art.Tables))
+	}
+	table := art.Tables[0]
+	if strings.Join(table.Headers, "|") != "Month|Rainfall (mm)" {
+		t.Errorf("Unexpected headers %v", table.Headers)
+	}
+	if len(table.Rows) != 2 || strings.Join(table.Rows[0], "|") != "March|112" || strings.Join(table.Rows[1], "|") != "April|98" {
+		t.Errorf("Unexpected rows %v", table.Rows)
+	}
+
+	art, _ = NewArticleFromHTML(html)
+	_ = art.Build(DefaultExtractors(art.Config))
+	if len(art.Tables) != 0 {
+		t.Error("Tables should only be extracted when ExtractTables is set")
+	}
+}
+
+func TestArticleUpdateHistory(t *testing.T) {
+	html := `<html><head>
+	<title>Storm hits the coast</title>
+	<meta property="article:published_time" content="2025-03-04T08:00:00Z">
+</head><body><article>
+	<p>A storm hit the coast on Tuesday morning and the authorities said that thousands of homes were left without power.</p>
+	<div class="article-update-history"><ul>
+		<li><time datetime="2025-03-04T14:02:00Z">14:02</time> — added minister's statement</li>
+		<li><time datetime="2025-03-04T09:15:00Z">09:15</time> — first published</li>
+		<li>Updated 11:40 — corrected the number of homes without power</li>
+	</ul></div>
+	<p class="updated">Updated <time datetime="2025-03-04T14:02:00Z">14:02</time></p>
+</article></body></html>`
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	err = art.Build(DefaultExtractors(art.Config))
+	if err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	expected := []struct {
+		time string
+		note string
+	}{
+		{"2025-03-04T09:15:00Z", "first published"},
+		{"2025-03-04T11:40:00Z", "corrected the number of homes without power"},
+		{"2025-03-04T14:02:00Z", "added minister's statement"},
+	}
+	if len(art.UpdateHistory) != len(expected) {
+		t.Fatalf("Expected %d updates, got %v", len(expected), art.UpdateHistory)
+	}
+	for i, e := range expected {
+		u := art.UpdateHistory[i]
+		if u.Time.UTC().Format(time.RFC3339) != e.time {
+			t.Errorf("Update %d: expected time %s, got %s", i, e.time, u.Time.UTC().Format(time.RFC3339))
+		}
+		if u.Note != e.note {
+			t.Errorf("Update %d: expected note %q, got %q", i, e.note, u.Note)
+		}
+	}
+
+	single := `<html><body><div class="update-log"><p>Updated <time datetime="2025-03-04T14:02:00Z">14:02</time></p></div></body></html>`
+	art, _ = NewArticleFromHTML(single)
+	_ = art.Build(DefaultExtractors(art.Config))
+	if len(art.UpdateHistory) != 0 {
+		t.Errorf("A single update label should not produce a history, got %v", art.UpdateHistory)
+	}
+}
+
+func TestArticleResolveOEmbed(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("url"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"type": "video", "title": "Launch highlights", "author_name": "Space Channel", "provider_name": "Vimeo", "thumbnail_url": "https://i.vimeocdn.com/video/123456.jpg"}`))
+	}))
+	defer server.Close()
+
+	html := `<html><head><title>Rocket launch</title></head><body>
+	<iframe src="https://player.vimeo.com/video/123456"></iframe>
+</body></html>`
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	art.Config.ResolveOEmbed = true
+	art.Config.OEmbedEndpoints = map[string]string{"vimeo": server.URL + "/oembed"}
+	err = art.Build(DefaultExtractors(art.Config))
+	if err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if len(requested) != 1 || requested[0] != "https://player.vimeo.com/video/123456" {
+		t.Errorf("Expected one oEmbed request for the video, got %v", requested)
+	}
+	if len(art.Videos) != 1 {
+		t.Fatalf("Expected 1 resolved video, got %v", art.Videos)
+	}
+	video := art.Videos[0]
+	if video.Title != "Launch highlights" || video.Author != "Space Channel" || video.Provider != "Vimeo" {
+		t.Errorf("Unexpected oEmbed fields %+v", video)
+	}
+	if video.ThumbnailURL != "https://i.vimeocdn.com/video/123456.jpg" {
+		t.Errorf("Unexpected thumbnail %q", video.ThumbnailURL)
+	}
+}
+
+func TestArticleMalformedAttributes(t *testing.T) {
+	html := "<html><head>" +
+		"<title>Fallback title</title>" +
+		"<meta property=\"og:title\" content='The \"Quoted\" Headline'>" +
+		"<meta name=\"author\" content=\"Pat O'Brien\">" +
+		"<meta name=\"description\" content='Summary with \"quotes\" and a newline\n inside'>" +
+		"</head><body><article class=\"article\nbody main-\"story\">" +
+		"<p>The council said on Monday that the new park would open in the summer and that it would be free for all residents of the city.</p>" +
+		"</article></body></html>"
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	err = art.Build(DefaultExtractors(art.Config))
+	if err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if art.Title == "" {
+		t.Error("Title should be extracted")
+	}
+	if !strings.Contains(art.MetaDescription, `"quotes"`) {
+		t.Errorf("Expected the quoted description, got %q", art.MetaDescription)
+	}
+	if !strings.Contains(strings.Join(art.Authors, ","), "O'Brien") {
+		t.Errorf("Expected the author with a quote in the name, got %v", art.Authors)
+	}
+	if !strings.Contains(art.Text, "new park") {
+		t.Errorf("Expected the body text, got %q", art.Text)
+	}
+}
+
+func TestArticleStripEmoji(t *testing.T) {
+	html := "<html><head><title>Storm warning</title></head><body><article>" +
+		"<p>The storm \U0001F32A\ufe0f reached the coast\u200b on Tuesday night and the authorities ordered the evacuation of several villages along the shore.</p>" +
+		"<p>Residents \U0001F64F were told to stay indoors until the morning while the emergency services cleared the roads of fallen trees.</p>" +
+		"</article></body></html>"
+
+	for _, strip := range []bool{false, true} {
+		art, err := NewArticleFromHTML(html)
+		if err != nil {
+			t.Fatalf("Error creating article from HTML: %v", err)
+		}
+		art.Config.StripEmoji = strip
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+
+		if strings.Contains(art.Text, "\u200b") {
+			t.Errorf("StripEmoji=%v: zero-width space should always be removed, got %q", strip, art.Text)
+		}
+		if hasEmoji := strings.Contains(art.Text, "\U0001F64F"); hasEmoji == strip {
+			t.Errorf("StripEmoji=%v: unexpected emoji handling in %q", strip, art.Text)
+		}
+		if !strings.Contains(art.Text, "reached the coast on Tuesday") {
+			t.Errorf("StripEmoji=%v: expected the body text, got %q", strip, art.Text)
+		}
+	}
+}
+
+func TestArticleNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     string
+		title    string
+		text     string
+		expected string
+	}{
+		{"whitespace", "en", "Council   approves\tnew   budget", "", "Council approves new budget"},
+		{"video label", "en", "VIDEO. Firefighters rescue cat from tree", "", "Firefighters rescue cat from tree"},
+		{"watch label", "en", "Watch: Firefighters rescue cat from tree", "", "Firefighters rescue cat from tree"},
+		{"french live label", "fr", "EN DIRECT. Le gouvernement présente son budget", "", "Le gouvernement présente son budget"},
+		{"label needs separator", "en", "Live music returns to the city center", "", "Live music returns to the city center"},
+		{"trailing ellipsis", "en", "Council approves new budget…", "", "Council approves new budget"},
+		{"trailing dots", "en", "Council approves new budget ...", "", "Council approves new budget"},
+		{"all caps", "en", "MAYOR RESIGNS AFTER SCANDAL. CITY IN SHOCK", "", "Mayor resigns after scandal. City in shock"},
+		{"all caps keeps acronyms", "en", "NASA AND ESA PLAN NEW MISSION TO THE MOON", "The NASA and ESA teams will present the details of the mission next week in Paris.", "NASA and ESA plan new mission to the moon"},
+		{"acronym in normal title", "en", "NASA confirms water on the moon…", "", "NASA confirms water on the moon"},
+		{"german keeps capitals", "de", "REGIERUNG BESCHLIESST NEUE STEUERREFORM", "", "REGIERUNG BESCHLIESST NEUE STEUERREFORM"},
+		{"german nouns", "de", "Die Regierung beschließt eine neue Steuerreform", "", "Die Regierung beschließt eine neue Steuerreform"},
+		{"non latin script", "ru", "ПРАВИТЕЛЬСТВО ПРИНЯЛО НОВЫЙ БЮДЖЕТ", "", "ПРАВИТЕЛЬСТВО ПРИНЯЛО НОВЫЙ БЮДЖЕТ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := `<html lang="` + tt.lang + `"><head><title>` + tt.title + `</title></head><body><article>` +
+				`<p>The text of the article only needs to be long enough to be kept by the extractor. ` + tt.text + `</p>` +
+				`</article></body></html>`
+
+			art, err := NewArticleFromHTML(html)
+			if err != nil {
+				t.Fatalf("Error creating article from HTML: %v", err)
+			}
+			art.Config.NormalizeTitle = true
+			if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+				t.Fatalf("Error building article: %v", err)
+			}
+
+			if art.Title != tt.expected {
+				t.Errorf("Expected title %q, got %q", tt.expected, art.Title)
+			}
+			if art.RawTitle != strings.TrimSpace(tt.title) {
+				t.Errorf("Expected raw title %q, got %q", tt.title, art.RawTitle)
+			}
+		})
+	}
+}
+
+func TestArticleMaxJSONLDBytes(t *testing.T) {
+	catalog := `{"@type": "NewsArticle", "author": {"@type": "Person", "name": "Catalog Author"}, "hasPart": [` +
+		strings.Repeat(`{"@type": "Product", "name": "Garden chair", "offers": {"price": "19.99"}},`, 200) + `{}]}`
+	html := `<html><head><title>Garden furniture sale</title>` +
+		`<script type="application/ld+json">` + catalog + `</script>` +
+		`<script type="application/ld+json">{"@type": "NewsArticle", "datePublished": "2025-03-14T09:00:00Z", "author": {"@type": "Person", "name": "Jane Smith"}}</script>` +
+		`</head><body><article><p>The garden furniture sale starts on Friday and the prices of most chairs and tables will be cut by half until the end of the month.</p></article></body></html>`
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	art.Config.MaxJSONLDBytes = 4096
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if art.PublishDate == nil || art.PublishDate.Year() != 2025 {
+		t.Errorf("Expected the publish date from the small JSON-LD block, got %v", art.PublishDate)
+	}
+	authors := strings.Join(art.Authors, ",")
+	if !strings.Contains(authors, "Jane Smith") || strings.Contains(authors, "Catalog Author") {
+		t.Errorf("Expected only the author of the small JSON-LD block, got %v", art.Authors)
+	}
+	if len(art.Diagnostics) != 1 || !strings.Contains(art.Diagnostics[0].Message, "JSON-LD") {
+		t.Errorf("Expected a diagnostic for the skipped block, got %v", art.Diagnostics)
+	}
+}
+
+func TestArticleTitleWithoutTitleElement(t *testing.T) {
+	tests := []struct {
+		name     string
+		head     string
+		h1       string
+		expected string
+	}{
+		{"h1", "", "City council approves the new budget", "City council approves the new budget"},
+		{"short h1", "", "Budget approved", "Budget approved"},
+		{"meta title", `<meta property="og:title" content="Council approves budget">`, "Budget news", "Council approves budget"},
+		{"json-ld headline", `<script type="application/ld+json">{"@type": "NewsArticle", "headline": "Council votes on budget"}</script>`, "", "Council votes on budget"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := `<html><head>` + tt.head + `</head><body><article>`
+			if tt.h1 != "" {
+				html += `<h1>` + tt.h1 + `</h1>`
+			}
+			html += `<p>The city council approved the new budget on Monday after a long debate about the funding of schools and public transport.</p>` +
+				`</article></body></html>`
+
+			art, err := NewArticleFromHTML(html)
+			if err != nil {
+				t.Fatalf("Error creating article from HTML: %v", err)
+			}
+			if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+				t.Fatalf("Error building article: %v", err)
+			}
+			if art.Title != tt.expected {
+				t.Errorf("Expected title %q, got %q", tt.expected, art.Title)
+			}
+		})
+	}
+}
+
+func TestArticleKeepImageCaptions(t *testing.T) {
+	html := `<html><head><title>New bridge opens</title></head><body><article>
+<p>The new bridge over the river opened to traffic on Monday after three years of construction work and several delays.</p>
+<figure><img src="https://example.com/bridge.jpg"><figcaption>Engineer Hernandez inspects the bridge before the opening</figcaption></figure>
+<p>The bridge connects the two halves of the city and is expected to reduce traffic in the center by a third.</p>
+<figure><img src="https://example.com/crowd.jpg"><figcaption>Photo: Reuters</figcaption></figure>
+</article></body></html>`
+
+	for _, keep := range []bool{true, false} {
+		art, err := NewArticleFromHTML(html)
+		if err != nil {
+			t.Fatalf("Error creating article from HTML: %v", err)
+		}
+		art.Config.KeepImageCaptions = keep
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+
+		hasCaption := strings.Contains(art.Text, "Engineer Hernandez inspects the bridge")
+		hasKeyword := slices.Contains(art.Keywords, "hernandez")
+		if hasCaption != keep || hasKeyword != keep {
+			t.Errorf("KeepImageCaptions=%v: caption in text %v, keyword %v (keywords %v)", keep, hasCaption, hasKeyword, art.Keywords)
+		}
+		if strings.Contains(art.Text, "Reuters") {
+			t.Errorf("KeepImageCaptions=%v: credit-only caption should be removed, got %q", keep, art.Text)
+		}
+	}
+}
+
+func TestArticleRobotsDirectives(t *testing.T) {
+	const body = `<p>The council approved the new budget on Tuesday after a long debate about the cost of the planned tram line.</p>`
+	metaHTML := `<html><head><title>Budget approved</title>
+<meta name="robots" content="NOARCHIVE, max-snippet:50"><meta name="googlebot" content="noimageindex">
+</head><body><article>` + body + `</article></body></html>`
+
+	art, err := NewArticleFromHTML(metaHTML)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	if !slices.Equal(art.RobotsDirectives, []string{"noarchive", "max-snippet:50", "noimageindex"}) {
+		t.Errorf("Unexpected robots directives %v", art.RobotsDirectives)
+	}
+	if !art.NoArchive || art.NoIndex || art.Text == "" {
+		t.Errorf("Expected a noarchive page with its text, got NoArchive=%v NoIndex=%v text %q", art.NoArchive, art.NoIndex, art.Text)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Robots-Tag", "noindex")
+		w.Header().Add("X-Robots-Tag", "bingbot: nofollow")
+		_, _ = w.Write([]byte(metaHTML))
+	}))
+	defer server.Close()
+
+	art, err = NewArticleFromURL(server.URL + "/budget")
+	if err != nil {
+		t.Fatalf("Error creating article from URL: %v", err)
+	}
+	art.Config.DropNoArchiveContent = true
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	if !slices.Equal(art.RobotsDirectives, []string{"noindex", "noarchive", "max-snippet:50", "noimageindex"}) {
+		t.Errorf("Expected header and meta directives to be merged, got %v", art.RobotsDirectives)
+	}
+	if !art.NoIndex || !art.NoArchive {
+		t.Errorf("Expected NoIndex and NoArchive, got %v and %v", art.NoIndex, art.NoArchive)
+	}
+	if art.Title != "Budget approved" {
+		t.Errorf("Expected the title to be kept, got %q", art.Title)
+	}
+	if art.HTML != "" || art.ArticleHTML != "" || art.Text != "" || art.Doc != nil {
+		t.Errorf("Expected the content of a noarchive page to be dropped, got text %q", art.Text)
+	}
+}
+
+func TestArticleMinimalBlogPost(t *testing.T) {
+	content, err := os.ReadFile("testdata/minimal_blog_post.html")
+	if err != nil {
+		t.Fatalf("Error reading fixture: %v", err)
+	}
+
+	art, err := NewArticleFromHTML(string(content))
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if !strings.Contains(art.Text, "I moved this blog to a static site last month") {
+		t.Errorf("Text should start with the lede paragraph, got %q", art.Text)
+	}
+	if strings.Count(art.Text, "The move took a weekend") != 1 {
+		t.Errorf("Every paragraph should appear exactly once, got %q", art.Text)
+	}
+	if strings.Contains(art.Text, "Archive") {
+		t.Errorf("Navigation should not be part of the text, got %q", art.Text)
+	}
+}
+
+func TestArticleTopImageOrientation(t *testing.T) {
+	body := `<p>The new bridge over the river opened to traffic on Monday after three years of construction work and several delays.</p>`
+	tests := []struct {
+		name        string
+		html        string
+		width       int
+		height      int
+		orientation string
+	}{
+		{
+			name:  "img attributes",
+			html:  `<html><head><title>Bridge</title></head><body><article><img src="/bridge-wide.jpg" width="1200" height="400px">` + body + `</article></body></html>`,
+			width: 1200, height: 400, orientation: "landscape",
+		},
+		{
+			name: "og:image size",
+			html: `<html><head><title>Bridge</title><meta property="og:image" content="https://example.com/bridge-tall.jpg">` +
+				`<meta property="og:image:width" content="600"><meta property="og:image:height" content="900"></head>` +
+				`<body><article><img src="https://example.com/bridge-tall.jpg">` + body + `</article></body></html>`,
+			width: 600, height: 900, orientation: "portrait",
+		},
+		{
+			name:  "unknown size",
+			html:  `<html><head><title>Bridge</title></head><body><article><img src="/bridge.jpg" width="100%">` + body + `</article></body></html>`,
+			width: 0, height: 0, orientation: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			art, err := NewArticleFromHTML(tt.html)
+			if err != nil {
+				t.Fatalf("Error creating article from HTML: %v", err)
+			}
+			if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+				t.Fatalf("Error building article: %v", err)
+			}
+			if art.TopImage == "" {
+				t.Fatal("Expected a top image")
+			}
+			if art.TopImageWidth != tt.width || art.TopImageHeight != tt.height || art.TopImageOrientation != tt.orientation {
+				t.Errorf("Expected %dx%d %q, got %dx%d %q", tt.width, tt.height, tt.orientation,
+					art.TopImageWidth, art.TopImageHeight, art.TopImageOrientation)
+			}
+		})
+	}
+}
+
+func TestArticleSkipBodyForNonArticles(t *testing.T) {
+	html := `<html><head><title>City News</title><meta property="og:type" content="website"></head><body><article>
+<p>The council approved the new budget on Tuesday after a long debate about the cost of the planned tram line.</p>
+<p>The tram line is expected to open in three years and to carry thousands of passengers every day.</p>
+</article></body></html>`
+
+	for _, skip := range []bool{true, false} {
+		art, err := NewArticleFromHTML(html)
+		if err != nil {
+			t.Fatalf("Error creating article from HTML: %v", err)
+		}
+		art.Config.SkipBodyForNonArticles = skip
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+
+		if art.OGType != "website" || art.ContentType != newspaper.ContentTypeWebsite {
+			t.Errorf("Expected og:type website, got %q (%q)", art.OGType, art.ContentType)
+		}
+		skipped := slices.ContainsFunc(art.Diagnostics, func(d newspaper.Diagnostic) bool { return strings.Contains(d.Message, "skipped body extraction") })
+		if skipped != skip || (art.Text == "") != skip {
+			t.Errorf("SkipBodyForNonArticles=%v: body skipped %v, text %q", skip, skipped, art.Text)
+		}
+		if art.Title != "City News" {
+			t.Errorf("SkipBodyForNonArticles=%v: expected metadata to be extracted, got title %q", skip, art.Title)
+		}
+	}
+}
+
+func TestArticleElementLanguage(t *testing.T) {
+	german := `<p>Der Stadtrat hat am Dienstag nach einer langen Debatte den neuen Haushalt für das kommende Jahr beschlossen.</p>
+<p>Die neue Straßenbahnlinie soll in drei Jahren fertig sein und jeden Tag tausende Fahrgäste durch die Stadt bringen.</p>
+<p>Die Opposition kritisierte die hohen Kosten und forderte eine Bürgerbefragung über das Projekt.</p>`
+	tests := []struct {
+		name   string
+		html   string
+		lang   string
+		source string
+	}{
+		{
+			name:   "article element",
+			html:   `<html lang="en"><head><title>Haushalt beschlossen</title></head><body><article lang="de-DE">` + german + `</article></body></html>`,
+			lang:   "de",
+			source: "article",
+		},
+		{
+			name:   "top node ancestor",
+			html:   `<html lang="en"><head><title>Haushalt beschlossen</title></head><body><div class="content" lang="de"><article>` + german + `</article></div></body></html>`,
+			lang:   "de",
+			source: "top_node",
+		},
+		{
+			name:   "html element",
+			html:   `<html lang="de"><head><title>Haushalt beschlossen</title></head><body><article>` + german + `</article></body></html>`,
+			lang:   "de",
+			source: "html",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			art, err := NewArticleFromHTML(tt.html)
+			if err != nil {
+				t.Fatalf("Error creating article from HTML: %v", err)
+			}
+			if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+				t.Fatalf("Error building article: %v", err)
+			}
+			if art.MetaLang != tt.lang || art.LanguageSource != tt.source {
+				t.Errorf("Expected language %q from %q, got %q from %q", tt.lang, tt.source, art.MetaLang, art.LanguageSource)
+			}
+			if art.NLPLanguage() != tt.lang {
+				t.Errorf("Expected NLP in %q, got %q", tt.lang, art.NLPLanguage())
+			}
+			if slices.Contains(art.Keywords, "die") || slices.Contains(art.Keywords, "der") {
+				t.Errorf("German stop words should not be keywords, got %v", art.Keywords)
+			}
+		})
+	}
+}
+
+func TestArticleLanguagePriority(t *testing.T) {
+	html := `<html lang="en"><head><title>Le conseil adopte le budget</title>
+<meta property="og:locale" content="fr_FR">
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "NewsArticle", "inLanguage": "es"}</script>
+</head><body><article>
+<p>Le conseil municipal a adopté mardi le nouveau budget de la ville après un long débat entre la majorité et l'opposition.</p>
+<p>La nouvelle ligne de tramway doit être terminée dans trois ans et transporter chaque jour des milliers de voyageurs.</p>
+</article></body></html>`
+	tests := []struct {
+		name     string
+		priority []string
+		lang     string
+		source   string
+	}{
+		{name: "default", lang: "en", source: "html"},
+		{name: "og first", priority: []string{"og", "html"}, lang: "fr", source: "og"},
+		{name: "jsonld first", priority: []string{"jsonld", "html", "og", "detect"}, lang: "es", source: "jsonld"},
+		{name: "detect first", priority: []string{"detect", "html"}, lang: "fr", source: "detected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			art, err := NewArticleFromHTML(html)
+			if err != nil {
+				t.Fatalf("Error creating article from HTML: %v", err)
+			}
+			art.Config.LanguagePriority = tt.priority
+			if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+				t.Fatalf("Error building article: %v", err)
+			}
+			if art.MetaLang != tt.lang || art.LanguageSource != tt.source {
+				t.Errorf("Expected language %q from %q, got %q from %q", tt.lang, tt.source, art.MetaLang, art.LanguageSource)
+			}
+			if !slices.Contains(art.Diagnostics, newspaper.Diagnostic{Severity: newspaper.DiagnosticInfo, Message: "conflicting language signals: html=en, og=fr, jsonld=es"}) {
+				t.Errorf("Expected the observed language signals in the diagnostics, got %v", art.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestArticleAMP(t *testing.T) {
+	html := `<!doctype html>
+<html amp lang="en">
+<head>
+<meta charset="utf-8">
+<title>Harbour reopens after the storm</title>
+<link rel="canonical" href="https://news.example.com/2025/01/06/harbour-reopens.html">
+<meta name="viewport" content="width=device-width">
+<script async src="https://cdn.ampproject.org/v0.js"></script>
+<script async custom-element="amp-youtube" src="https://cdn.ampproject.org/v0/amp-youtube-0.1.js"></script>
+<style amp-boilerplate>body{visibility:hidden}</style>
+</head>
+<body>
+<header><amp-img src="/static/logo.png" width="120" height="40" alt="Coast News"></amp-img></header>
+<article>
+<h1>Harbour reopens after the storm</h1>
+<amp-img src="/images/harbour-hero.jpg" srcset="/images/harbour-hero-640.jpg 640w, /images/harbour-hero.jpg 1200w" width="1200" height="800" layout="responsive" alt="The harbour">
+<noscript><img src="/images/harbour-hero.jpg" width="1200" height="800"></noscript>
+</amp-img>
+<p>The harbour reopened on Monday after the storm that damaged the piers and sank several fishing boats last week.</p>
+<p>Workers repaired the main pier over the weekend and the first ferries left in the morning with a full load of passengers.</p>
+<amp-img src="/images/harbour-ferry.jpg" width="800" height="600" layout="responsive" alt="A ferry"></amp-img>
+<p>The port authority said that the remaining repairs would take several weeks but would not disturb the traffic.</p>
+<amp-youtube data-videoid="dQw4w9WgXcQ" layout="responsive" width="480" height="270"></amp-youtube>
+</article>
+</body>
+</html>`
+
+	req := NewDefaultParseRequest("https://news.example.com/amp/2025/01/06/harbour-reopens.html")
+	req.InputHTML = html
+	art, err := NewArticleFromRequest(req)
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	expectedImages := []string{
+		"https://news.example.com/static/logo.png",
+		"https://news.example.com/images/harbour-hero.jpg",
+		"https://news.example.com/images/harbour-ferry.jpg",
+	}
+	if !slices.Equal(art.Images, expectedImages) {
+		t.Errorf("Expected images %v, got %v", expectedImages, art.Images)
+	}
+	if art.TopImage != "https://news.example.com/images/harbour-hero.jpg" || art.TopImageWidth != 1200 || art.TopImageHeight != 800 {
+		t.Errorf("Expected the 1200x800 hero image as top image, got %s (%dx%d)", art.TopImage, art.TopImageWidth, art.TopImageHeight)
+	}
+	if expected := []string{"https://www.youtube.com/embed/dQw4w9WgXcQ"}; !slices.Equal(art.Movies, expected) {
+		t.Errorf("Expected videos %v, got %v", expected, art.Movies)
+	}
+	if !strings.Contains(art.ArticleHTML, "harbour-ferry.jpg") {
+		t.Errorf("Expected the cleaned article HTML to keep the amp-img elements, got %s", art.ArticleHTML)
+	}
+}
+
+func TestArticleRating(t *testing.T) {
+	html := `<html><head><title>Review: the new phone</title>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Product", "name": "Phone X",
+"aggregateRating": {"@type": "AggregateRating", "ratingValue": "4.4", "bestRating": 5, "worstRating": "1", "ratingCount": 1289}}</script>
+</head><body><article><h1>Review: the new phone</h1><p>The new phone has a brighter screen, a longer battery life and a better camera than the previous model.</p></article></body></html>`
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	expected := newspaper.RatingData{Value: 4.4, Best: 5, Worst: 1, Count: 1289}
+	if art.Rating == nil || *art.Rating != expected {
+		t.Errorf("Expected rating %+v, got %+v", expected, art.Rating)
+	}
+
+	art, err = NewArticleFromHTML(`<html><head><title>No rating</title></head><body><p>Nothing to rate here.</p></body></html>`)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	if art.Rating != nil {
+		t.Errorf("Expected no rating, got %+v", art.Rating)
+	}
+}
+
+func TestArticleFirstParagraph(t *testing.T) {
+	html := `<html><head><title>Storm hits the coast</title></head><body><article><h1>Storm hits the coast</h1>
+<figure><img src="/storm.jpg"><figcaption><p>Waves crashing against the harbour wall of the old town on Monday morning, as seen from the pier.</p></figcaption></figure>
+<p>Harbour.</p>
+<p>LISBON (Reuters) —</p>
+<p>The storm hit the coast on Monday, closing roads, schools and ports across the region as winds reached record speeds.</p>
+<p>Emergency services said hundreds of homes were without power and several villages had been cut off by flooding overnight.</p>
+<p>Forecasters expect the winds to ease by Wednesday, although heavy rain should continue in the north for the rest of the week.</p>
+</article></body></html>`
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	expected := "The storm hit the coast on Monday, closing roads, schools and ports across the region as winds reached record speeds."
+	if got := art.FirstParagraph(); got != expected {
+		t.Errorf("Expected first paragraph %q, got %q", expected, got)
+	}
+
+	art.Config.FirstParagraphMinWords = 1
+	if got := art.FirstParagraph(); got != "Harbour." {
+		t.Errorf("Expected the one-word paragraph with FirstParagraphMinWords=1, got %q", got)
+	}
+}
+
+func TestArticleDownloadWithoutHTML(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/empty":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		case "/api":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"title": "Storm hits the coast"}`))
+		case "/missing", "/broken":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			if r.URL.Path == "/missing" {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			_, _ = w.Write([]byte(`<html><head><title>Page not found</title></head><body><p>The page you requested does not exist.</p></body></html>`))
+		case "/moved":
+			http.Redirect(w, r, "/article", http.StatusMovedPermanently)
+		case "/article":
+			_, _ = w.Write([]byte(`<html><head><title>Storm</title></head><body><p>The storm hit the coast.</p></body></html>`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path     string
+		expected error
+		state    newspaper.ArticleDownloadState
+	}{
+		{path: "/no-content", expected: newspaper.ErrEmptyResponse, state: newspaper.FailedResponse},
+		{path: "/empty", expected: newspaper.ErrEmptyResponse, state: newspaper.FailedResponse},
+		{path: "/api", expected: newspaper.ErrNotHTML, state: newspaper.NotHTML},
+		{path: "/missing", expected: newspaper.ErrHTTPStatus, state: newspaper.FailedResponse},
+		{path: "/broken", expected: newspaper.ErrHTTPStatus, state: newspaper.FailedResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			art, err := NewArticleFromURL(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("Error creating article: %v", err)
+			}
+			err = art.Download()
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected error %v, got %v", tt.expected, err)
+			}
+			if art.DownloadState != tt.state {
+				t.Errorf("Expected download state %v, got %v", tt.state, art.DownloadState)
+			}
+			if errors.Is(err, newspaper.ErrHTTPStatus) && (!strings.Contains(art.DownloadExceptionMsg, server.URL+tt.path) || art.HTML != "") {
+				t.Errorf("Expected the URL and status in the message and no HTML, got %q", art.DownloadExceptionMsg)
+			}
+			if err := art.Parse(DefaultExtractors(art.Config)); err == nil || art.IsParsed {
+				t.Error("Expected parsing to fail after a failed download")
+			}
+		})
+	}
+
+	moved, err := NewArticleFromURL(server.URL + "/moved")
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	if err := moved.Download(); err != nil || moved.DownloadState != newspaper.Success {
+		t.Errorf("Expected the redirect to be followed, got %v", err)
+	}
+
+	art, err := NewArticleFromHTML(" <html><head></head><body>\n</body></html>")
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); !errors.Is(err, newspaper.ErrEmptyDocument) {
+		t.Errorf("Expected error %v for an empty document, got %v", newspaper.ErrEmptyDocument, err)
+	}
+	if art.IsParsed {
+		t.Error("Expected an empty document not to be marked as parsed")
+	}
+}
+
+func TestArticleStripEmptyBlocks(t *testing.T) {
+	html := `<html><head><title>Storm hits the coast</title></head><body><article><h1>Storm hits the coast</h1>
+<p>The storm hit the coast on Monday, closing roads, schools and ports across the region as winds reached record speeds.</p>
+<p></p>
+<p> &nbsp; </p>
+<div><p><br></p></div>
+<p>Emergency services said hundreds of homes were without power and several villages had been cut off by flooding overnight.</p>
+<p><img src="https://example.com/storm.jpg"></p>
+</article></body></html>`
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(art.ArticleHTML))
+	if err != nil {
+		t.Fatalf("Error parsing article HTML: %v", err)
+	}
+	doc.Find("p, div").Each(func(i int, s *goquery.Selection) {
+		if strings.TrimSpace(s.Text()) == "" && s.Find("img").Length() == 0 {
+			t.Errorf("Expected no empty block in ArticleHTML, got %q", goquery.NodeName(s))
+		}
+	})
+	if paragraphs := doc.Find("p").Length(); paragraphs != 3 {
+		t.Errorf("Expected the 2 text paragraphs and the image paragraph to stay, got %d paragraphs in %s", paragraphs, art.ArticleHTML)
+	}
+}
+
+func TestArticleCategories(t *testing.T) {
+	html := `<html><head><title>Storm hits the coast</title><meta property="article:section" content="Europe"></head><body>
+<nav><a href="https://news.example.com/politics">Politics</a> <a href="https://news.example.com/sport">Sport</a> <a href="https://news.example.com/culture">Culture</a></nav>
+<ol class="breadcrumbs"><li><a href="/">Home</a></li><li><a href="/world">World</a></li><li><a href="/world/europe">Europe</a></li></ol>
+<article><h1>Storm hits the coast</h1>
+<p class="byline">By <a href="/authors/jane-doe" rel="author">Jane Doe</a></p>
+<p>The storm hit the coast on Monday, closing roads, schools and ports across the region as winds reached record speeds.</p>
+<p>Tags: <a href="/tag/storms" rel="tag">Storms</a>, <a href="/tag/portugal" rel="tag">Portugal</a></p>
+</article></body></html>`
+
+	req := NewDefaultParseRequest("https://news.example.com/world/europe/storm-hits-the-coast")
+	req.InputHTML = html
+	art, err := NewArticleFromRequest(req)
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	var categories []string
+	for _, c := range art.Categories {
+		categories = append(categories, c.String())
+	}
+	expected := []string{
+		"https://news.example.com/world",
+		"https://news.example.com/world/europe",
+		"https://news.example.com/tag/storms",
+		"https://news.example.com/tag/portugal",
+	}
+	if !slices.Equal(categories, expected) {
+		t.Errorf("Expected categories %q, got %q", expected, categories)
+	}
+
+	art.SourceURL = "https://news.example.com"
+	art.Config.SiteWideArticleCategories = true
+	if err := newspaper4k.NewCategoryExtractor(art.Config).Parse(art); err != nil {
+		t.Fatalf("Error extracting categories: %v", err)
+	}
+	categories = nil
+	for _, c := range art.Categories {
+		categories = append(categories, c.String())
+	}
+	if !slices.Contains(categories, "https://news.example.com/politics") {
+		t.Errorf("Expected the navigation categories with SiteWideArticleCategories, got %v", art.Categories)
+	}
+}
+
+func TestArticleDownloadUserAgents(t *testing.T) {
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.UserAgent())
+		_, _ = w.Write([]byte(`<html><head><title>Storm</title></head><body><p>The storm hit the coast.</p></body></html>`))
+	}))
+	defer server.Close()
+
+	pool := []string{"agent-a/1.0", "agent-b/2.0", "agent-c/3.0"}
+	download := func(path string, config *configuration.Configuration) {
+		t.Helper()
+		art := &newspaper.Article{URL: server.URL + path, Config: config}
+		if err := art.Download(); err != nil {
+			t.Fatalf("Error downloading article: %v", err)
+		}
+	}
+
+	// The articles of a configuration share its rotation
+	config := configuration.NewConfiguration()
+	config.RequestsParams.UserAgents = pool
+	for _, path := range []string{"/1", "/2", "/3"} {
+		download(path, config)
+	}
+	if !slices.Equal(userAgents, pool) {
+		t.Fatalf("Expected successive requests to rotate through %q, got %q", pool, userAgents)
+	}
+
+	// Another configuration starts its own rotation
+	other := configuration.NewConfiguration()
+	other.RequestsParams.UserAgents = pool
+	download("/4", other)
+	if ua := userAgents[len(userAgents)-1]; ua != pool[0] {
+		t.Errorf("Expected a new configuration to start with the first user agent, got %q", ua)
+	}
+
+	// So does a configuration not made by NewConfiguration
+	userAgents = nil
+	literal := &configuration.Configuration{RequestsParams: configuration.RequestsParams{UserAgents: pool}}
+	for _, path := range []string{"/6", "/7", "/8"} {
+		download(path, literal)
+	}
+	if !slices.Equal(userAgents, pool) {
+		t.Errorf("Expected a configuration literal to rotate through %q, got %q", pool, userAgents)
+	}
+
+	config.RequestsParams.UserAgent = "fixed-agent/1.0"
+	download("/5", config)
+	if ua := userAgents[len(userAgents)-1]; ua != "fixed-agent/1.0" {
+		t.Errorf("Expected UserAgent to take precedence over the pool, got %q", ua)
+	}
+}
+
+func TestArticleDownloadHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Language") != "fr-FR" || r.Header.Get("X-Api-Key") != "secret" || r.UserAgent() != "reader/1.0" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`<html><head><title>Storm</title></head><body><p>The storm hit the coast.</p></body></html>`))
+	}))
+	defer server.Close()
+
+	art, err := NewArticleFromURL(server.URL + "/storm")
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	art.Config.RequestsParams.Headers = map[string]string{
+		"Accept-Language": "fr-FR",
+		"X-Api-Key":       "secret",
+		"User-Agent":      "overridden/1.0",
+	}
+	art.Config.RequestsParams.UserAgent = "reader/1.0"
+	if err := art.Download(); err != nil {
+		t.Fatalf("Error downloading article: %v", err)
+	}
+	if art.DownloadState != newspaper.Success {
+		t.Errorf("Expected the configured headers to be sent, got state %v: %s", art.DownloadState, art.DownloadExceptionMsg)
+	}
+}
+
+func TestArticleDownloadProxy(t *testing.T) {
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.String())
+		_, _ = w.Write([]byte(`<html><head><title>Storm</title></head><body><p>The storm hit the coast.</p></body></html>`))
+	}))
+	defer proxy.Close()
+
+	// The host does not resolve, only the proxy can answer
+	art, err := NewArticleFromURL("http://news.invalid/storm")
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	art.Config.RequestsParams.Proxies = map[string]string{"http": proxy.URL}
+	if err := art.Download(); err != nil {
+		t.Fatalf("Error downloading article: %v", err)
+	}
+	if art.DownloadState != newspaper.Success {
+		t.Errorf("Expected a successful download, got state %v: %s", art.DownloadState, art.DownloadExceptionMsg)
+	}
+	if got, _ := proxied.Load().(string); got != "http://news.invalid/storm" {
+		t.Errorf("Expected the request to go through the proxy, got %q", got)
+	}
+}
+
+func TestArticleDownloadTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(3 * time.Second):
+			_, _ = w.Write([]byte(`<html><head><title>Storm</title></head><body><p>The storm hit the coast.</p></body></html>`))
+		}
+	}))
+	defer server.Close()
+
+	art, err := NewArticleFromURL(server.URL + "/slow")
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	art.Config.RequestsParams.Timeout = 1
+	start := time.Now()
+	if err := art.Download(); err == nil {
+		t.Fatal("Expected the download to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 2500*time.Millisecond {
+		t.Errorf("Expected the configured timeout to stop the download, took %v", elapsed)
+	}
+	if art.DownloadState != newspaper.FailedResponse {
+		t.Errorf("Expected a failed download, got %v", art.DownloadState)
+	}
+}
+
+func TestArticleDownloadRetries(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`<html><head><title>Storm</title></head><body><p>The storm hit the coast.</p></body></html>`))
+	}))
+	defer server.Close()
+
+	art, err := NewArticleFromURL(server.URL + "/flaky")
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	art.Config.RequestsParams.MaxRetries = 2
+	art.Config.RequestsParams.RetryBackoff = 10 * time.Millisecond
+	if err := art.Download(); err != nil {
+		t.Fatalf("Expected the article to be downloaded after the retries, got %v", err)
+	}
+	if art.DownloadState != newspaper.Success || !strings.Contains(art.HTML, "The storm hit the coast.") {
+		t.Errorf("Expected the downloaded page, got state %v", art.DownloadState)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("Expected 3 requests, server saw %d", got)
+	}
+}
+
+// stubClient answers every request with the same page
+type stubClient struct {
+	page     string
+	requests atomic.Int32
+}
+
+func (c *stubClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	header := http.Header{"Content-Type": []string{"text/html; charset=utf-8"}}
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Request: req, Body: io.NopCloser(strings.NewReader(c.page))}, nil
+}
+
+func TestArticleDownloadHTTPClient(t *testing.T) {
+	client := &stubClient{page: `<html><head><title>Storm</title></head><body><p>The storm hit the coast.</p></body></html>`}
+	art, err := NewArticleFromURL("https://offline.example.com/2025/03/14/storm.html")
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	art.Config.HTTPClient = client
+	if err := art.Download(); err != nil {
+		t.Fatalf("Error downloading article: %v", err)
+	}
+	if client.requests.Load() != 1 || art.HTML != client.page {
+		t.Errorf("Expected the page to be downloaded through the configured client, got %d requests", client.requests.Load())
+	}
+}
+
+func TestArticleSummaryFallback(t *testing.T) {
+	lead := "The city council approved on Tuesday a new plan to expand the tram network to the northern suburbs by 2030."
+	parse := func(head string) *newspaper.Article {
+		t.Helper()
+		html := `<html><head><title>Tram plan approved</title>` + head + `</head><body><article>
+<h1>Tram plan approved</h1>
+<p>` + lead + `</p>
+<p>The first line is expected to open in four years, after a public consultation that starts next month and lasts until the summer.</p>
+<p>Opponents of the project criticised its cost and asked for more buses instead of new tram lines in the city centre.</p>
+</article></body></html>`
+		req := NewDefaultParseRequest("https://www.example.com/2025/04/01/tram-plan.html")
+		req.InputHTML = html
+		art, err := NewArticleFromRequest(req)
+		if err != nil {
+			t.Fatalf("Error creating article: %v", err)
+		}
+		// Download and Parse only: NLP is skipped, so Summary stays empty
+		if err := art.Download(); err != nil {
+			t.Fatalf("Error downloading article: %v", err)
+		}
+		if err := art.Parse(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error parsing article: %v", err)
+		}
+		if art.Summary != "" {
+			t.Fatalf("Expected no summary without NLP, got %q", art.Summary)
+		}
+		return art
+	}
+
+	art := parse(`<meta name="description" content="The tram network will reach the northern suburbs.">
+<script type="application/ld+json">{"@type": "NewsArticle", "description": "A JSON-LD description"}</script>`)
+	if summary := art.GetSummary(); summary != "The tram network will reach the northern suburbs." {
+		t.Errorf("Expected the meta description, got %q", summary)
+	}
+
+	art = parse(`<script type="application/ld+json">{"@graph": [{"@type": "WebPage"}, {"@type": "NewsArticle", "description": "A JSON-LD description"}]}</script>`)
+	if summary := art.GetSummary(); summary != "A JSON-LD description" {
+		t.Errorf("Expected the JSON-LD description, got %q", summary)
+	}
+
+	art = parse("")
+	if summary := art.GetSummary(); summary != lead {
+		t.Errorf("Expected the lead, got %q", summary)
+	}
+}
+
+func TestArticleAuthorNameParticles(t *testing.T) {
+	for byline, expected := range map[string][]string{
+		"Jean-Paul Sartre":                        {"Jean-Paul Sartre"},
+		"Maria van der Berg and Li Wei":           {"Maria van der Berg", "Li Wei"},
+		"By Juan Carlos de la Cruz y Martínez":    {"Juan Carlos de la Cruz y Martínez"},
+		"Ludwig van Beethoven / Anne-Marie Duval": {"Ludwig van Beethoven", "Anne-Marie Duval"},
+	} {
+		art, err := NewArticleFromHTML(`<html><head><title>Review</title></head><body><article>
+<span class="byline">` + byline + `</span>
+<p>The orchestra played the whole cycle over three evenings in front of a full house.</p>
+</article></body></html>`)
+		if err != nil {
+			t.Fatalf("Error creating article: %v", err)
+		}
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+		if !slices.Equal(art.Authors, expected) {
+			t.Errorf("%q: expected authors %q, got %q", byline, expected, art.Authors)
+		}
+	}
+}
+
+func TestArticlePartOf(t *testing.T) {
+	build := func(head string) *newspaper.Article {
+		t.Helper()
+		req := NewDefaultParseRequest("https://www.example.com/investigations/water/part-2.html")
+		req.InputHTML = `<html><head><title>Who owns the water, part 2</title>` + head + `</head><body><article>
+<h1>Who owns the water, part 2</h1>
+<p>In the second part of our investigation, we follow the contracts signed by the city with private water companies since 2010.</p>
+</article></body></html>`
+		art, err := NewArticleFromRequest(req)
+		if err != nil {
+			t.Fatalf("Error creating article: %v", err)
+		}
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+		return art
+	}
+
+	art := build(`<script type="application/ld+json">{"@context": "https://schema.org", "@graph": [
+{"@type": "WebPage", "@id": "https://www.example.com/investigations/water/part-2.html#webpage", "name": "Who owns the water, part 2"},
+{"@type": "NewsArticle", "headline": "Who owns the water, part 2",
+ "isPartOf": [{"@id": "https://www.example.com/investigations/water/part-2.html#webpage"}, {"@id": "https://www.example.com/investigations/water/#series"}]},
+{"@type": "CreativeWorkSeries", "@id": "https://www.example.com/investigations/water/#series", "name": "Who owns the water", "url": "/investigations/water/"}
+]}</script>`)
+	if art.PartOf != "Who owns the water" || art.PartOfURL != "https://www.example.com/investigations/water/" {
+		t.Errorf("Expected the series from isPartOf, got %q %q", art.PartOf, art.PartOfURL)
+	}
+
+	art = build(`<meta property="article:series" content="Who owns the water">`)
+	if art.PartOf != "Who owns the water" || art.PartOfURL != "" {
+		t.Errorf("Expected the series from article:series, got %q %q", art.PartOf, art.PartOfURL)
+	}
+
+	art = build(`<script type="application/ld+json">{"@type": "NewsArticle", "isPartOf": {"@type": "WebSite", "name": "Example News"}}</script>`)
+	if art.PartOf != "" {
+		t.Errorf("Expected no series for the website, got %q", art.PartOf)
+	}
+}
+
+func TestArticleEntitiesInMetadata(t *testing.T) {
+	html := `<html><head><title>Rock &#8217;n&#8217; roll &amp; the city | Example News</title>
+<meta property="og:title" content="Rock &amp;#8217;n&amp;#8217; roll &amp;amp; the city">
+<meta name="author" content="Jane Doe &amp;amp; John Smith">
+<meta name="description" content="A caf&amp;eacute; concert series returns downtown">
+<meta property="og:site_name" content="Example &amp;amp; Co News">
+</head><body><article>
+<h1>Rock &#8217;n&#8217; roll &amp; the city</h1>
+<p>The concert series returns downtown this summer with a dozen bands playing in cafés and on rooftops every weekend.</p>
+</article></body></html>`
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if art.Title != "Rock ’n’ roll & the city" {
+		t.Errorf("Expected a decoded title, got %q", art.Title)
+	}
+	if expected := []string{"Jane Doe", "John Smith"}; !slices.Equal(art.Authors, expected) {
+		t.Errorf("Expected authors %q split on the decoded ampersand, got %q", expected, art.Authors)
+	}
+	if art.MetaDescription != "A café concert series returns downtown" {
+		t.Errorf("Expected a decoded description, got %q", art.MetaDescription)
+	}
+	if art.MetaSiteName != "Example & Co News" {
+		t.Errorf("Expected a decoded site name, got %q", art.MetaSiteName)
+	}
+}
+
+func TestArticleFollowOutboundCanonical(t *testing.T) {
+	story := []string{
+		"The regional council approved on Tuesday a new plan to expand the tram network to the northern suburbs by the end of the decade.",
+		"The first line is expected to open in four years, after a public consultation that starts next month and lasts until the summer.",
+		"Opponents of the project criticised its cost and asked for more buses instead of new tram lines in the city centre.",
+	}
+	outlet := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private/\n")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		robots := ""
+		if strings.HasPrefix(r.URL.Path, "/noindex/") {
+			robots = `<meta name="robots" content="noindex">`
+		}
+		fmt.Fprintf(w, `<html><head><title>Tram plan approved</title>%s</head><body><article><h1>Tram plan approved</h1><p>%s</p></article></body></html>`,
+			robots, strings.Join(story, "</p><p>"))
+	}))
+	defer outlet.Close()
+	// localhost is another site than the 127.0.0.1 of the aggregator
+	outletURL := strings.Replace(outlet.URL, "127.0.0.1", "localhost", 1)
+	storyURL := outletURL + "/2025/04/01/tram-plan.html"
+
+	aggregator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		robots := ""
+		if r.URL.Path == "/nofollow" {
+			robots = `<meta name="robots" content="nofollow">`
+		}
+		target := storyURL
+		switch r.URL.Path {
+		case "/news/private":
+			target = outletURL + "/private/tram-plan.html"
+		case "/news/noindex":
+			target = outletURL + "/noindex/tram-plan.html"
+		}
+		fmt.Fprintf(w, `<html><head><title>Tram plan approved</title>%s</head><body>
+<nav><a href="/">Home</a> <a href="/politics">Politics</a></nav>
+<article><h1>Tram plan approved</h1>
+<p>The council approved the tram extension to the northern suburbs.</p>
+<p>Read the full story at <a href="%s">The Daily Outlet</a></p>
+</article></body></html>`, robots, target)
+	}))
+	defer aggregator.Close()
+
+	build := func(path string, follow bool) *newspaper.Article {
+		t.Helper()
+		art, err := NewArticleFromRequest(NewDefaultParseRequest(aggregator.URL + path))
+		if err != nil {
+			t.Fatalf("Error creating article: %v", err)
+		}
+		art.Config.FollowOutboundCanonical = follow
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+		return art
+	}
+
+	art := build("/news/tram", true)
+	if !strings.Contains(art.Text, story[1]) {
+		t.Errorf("Expected the text of the full story, got %q", art.Text)
+	}
+	if art.URL != storyURL || art.CanonicalLink != storyURL || art.ViaURL != aggregator.URL+"/news/tram" {
+		t.Errorf("Expected the story URL and the aggregator as via URL, got %q %q %q", art.URL, art.CanonicalLink, art.ViaURL)
+	}
+
+	art = build("/news/tram", false)
+	if strings.Contains(art.Text, story[1]) || art.ViaURL != "" || art.URL != aggregator.URL+"/news/tram" {
+		t.Errorf("Expected the teaser without FollowOutboundCanonical, got %q from %q", art.Text, art.URL)
+	}
+
+	art = build("/nofollow", true)
+	if art.ViaURL != "" || art.URL != aggregator.URL+"/nofollow" {
+		t.Errorf("Expected the nofollow page not to be followed, got %q", art.URL)
+	}
+
+	// The robots.txt and the noindex directive of the outlet are respected
+	for _, path := range []string{"/news/private", "/news/noindex"} {
+		art = build(path, true)
+		if art.ViaURL != "" || art.URL != aggregator.URL+path || strings.Contains(art.Text, story[1]) {
+			t.Errorf("Expected the teaser of %s to be kept, got %q from %q", path, art.Text, art.URL)
+		}
+		if !slices.ContainsFunc(art.Diagnostics, func(d newspaper.Diagnostic) bool { return strings.Contains(d.Message, "not followed") }) {
+			t.Errorf("Expected a diagnostic for the story of %s not followed, got %v", path, art.Diagnostics)
+		}
+	}
+}
+
+func TestArticleRawJSONLD(t *testing.T) {
+	valid := `{"@context": "https://schema.org", "@type": "NewsArticle", "headline": "Harbour reopens", "author": {"@type": "Person", "name": "Jane Doe"}}`
+	malformed := `{"@context": "https://schema.org", "@type": "NewsArticle", "author": {"name": "John Smith",}`
+	html := `<html><head><title>Harbour reopens</title>
+<script type="application/ld+json">` + valid + `</script>
+<script type="application/ld+json">` + malformed + `</script>
+</head><body><article>
+<h1>Harbour reopens</h1>
+<p>The harbour reopened on Tuesday after repairs to the sea wall damaged by the storm were completed ahead of schedule.</p>
+</article></body></html>`
+
+	for _, keep := range []bool{false, true} {
+		art, err := NewArticleFromHTML(html)
+		if err != nil {
+			t.Fatalf("Error creating article from HTML: %v", err)
+		}
+		art.Config.KeepRawJSONLD = keep
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+
+		if !keep {
+			if art.RawJSONLD != nil {
+				t.Errorf("Expected no raw JSON-LD without KeepRawJSONLD, got %q", art.RawJSONLD)
+			}
+			continue
+		}
+		if expected := []string{valid, malformed}; !slices.Equal(art.RawJSONLD, expected) {
+			t.Errorf("Expected the raw text of both blocks %q, got %q", expected, art.RawJSONLD)
+		}
+		// Only the valid block is decoded
+		if expected := []string{"Jane Doe"}; !slices.Equal(art.Authors, expected) {
+			t.Errorf("Expected the author of the valid block %q, got %q", expected, art.Authors)
+		}
+		if !slices.ContainsFunc(art.Diagnostics, func(d newspaper.Diagnostic) bool {
+			return d.Severity == newspaper.DiagnosticError && strings.Contains(d.Message, "malformed JSON-LD")
+		}) {
+			t.Errorf("Expected a diagnostic for the malformed block, got %q", art.Diagnostics)
+		}
+		out, err := art.ToFullJSON()
+		if err != nil || !strings.Contains(out, `"raw_json_ld":[`) {
+			t.Errorf("Expected the raw JSON-LD in the full JSON, got %v", err)
+		}
+	}
+}
+
+func TestArticleMetaImageRelativeURLs(t *testing.T) {
+	tests := []struct {
+		articleURL string
+		ogImage    string
+		expected   string
+	}{
+		{"http://news.example.com/2025/storm.html", "//cdn.example.com/img/storm.jpg", "http://cdn.example.com/img/storm.jpg"},
+		{"https://news.example.com/2025/storm.html", " //cdn.example.com/img/storm.jpg\n", "https://cdn.example.com/img/storm.jpg"},
+		{"https://news.example.com/2025/storm.html", "/img/storm.jpg", "https://news.example.com/img/storm.jpg"},
+		{"", "//cdn.example.com/img/storm.jpg", "https://cdn.example.com/img/storm.jpg"},
+	}
+	for _, tt := range tests {
+		req := NewDefaultParseRequest(tt.articleURL)
+		req.InputHTML = `<html><head><meta property="og:image" content="` + tt.ogImage + `"></head>
+<body><article><p>The storm reached the coast on Monday and damaged the harbour.</p></article></body></html>`
+		art, err := NewArticleFromRequest(req)
+		if err != nil {
+			t.Fatalf("Error creating article: %v", err)
+		}
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+		if art.MetaImg != tt.expected {
+			t.Errorf("%q on %q: expected the meta image %q, got %q", tt.ogImage, tt.articleURL, tt.expected, art.MetaImg)
+		}
+	}
+}
+
+func TestArticleDedupeImages(t *testing.T) {
+	html := `<html><head><title>Storm hits the coast</title></head><body><article>
+<h1>Storm hits the coast</h1>
+<img src="https://cdn.example.com/img/storm.jpg?w=300">
+<p>The storm reached the coast on Monday and damaged the harbour, residents said on Tuesday.</p>
+<img src="https://cdn.example.com/img/storm.jpg?w=1200&amp;q=80">
+<img src="https://cdn.example.com/img/storm.jpg?w=800">
+<img src="https://cdn.example.com/img/harbour.jpg?w=800">
+</article></body></html>`
+
+	if configuration.NewConfiguration().DedupeImages {
+		t.Error("Expected DedupeImages to be off by default")
+	}
+	for _, dedupe := range []bool{true, false} {
+		art, err := NewArticleFromHTML(html)
+		if err != nil {
+			t.Fatalf("Error creating article from HTML: %v", err)
+		}
+		art.Config.DedupeImages = dedupe
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+		expected := []string{"https://cdn.example.com/img/storm.jpg?w=1200&q=80", "https://cdn.example.com/img/harbour.jpg?w=800"}
+		if !dedupe {
+			expected = []string{
+				"https://cdn.example.com/img/storm.jpg?w=300",
+				"https://cdn.example.com/img/storm.jpg?w=1200&q=80",
+				"https://cdn.example.com/img/storm.jpg?w=800",
+				"https://cdn.example.com/img/harbour.jpg?w=800",
+			}
+		}
+		if !slices.Equal(art.Images, expected) {
+			t.Errorf("DedupeImages=%v: expected images %q, got %q", dedupe, expected, art.Images)
+		}
+	}
+}
+
+func TestArticleModifiedDateRelative(t *testing.T) {
+	page := func(head, banner string) string {
+		return `<html><head><title>Storm hits the coast</title>` + head + `</head><body><article>
+<h1>Storm hits the coast</h1>
+<div class="meta"><span class="updated">` + banner + `</span></div>
+<p>The storm reached the coast on Monday and damaged the harbour, residents said. Updated 5 days ago, the forecast still expects rain for the rest of the week.</p>
+</article></body></html>`
+	}
+
+	art, err := NewArticleFromHTML(page("", "Updated 2 hours ago"))
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	if art.FetchedAt.IsZero() || art.ModifiedDate == nil {
+		t.Fatalf("Expected a fetch time and a modified date, got %v and %v", art.FetchedAt, art.ModifiedDate)
+	}
+	if diff := art.FetchedAt.Add(-2 * time.Hour).Sub(*art.ModifiedDate); diff < -time.Minute || diff > time.Minute {
+		t.Errorf("Expected the modified date 2 hours before the fetch time %v, got %v", art.FetchedAt, art.ModifiedDate)
+	}
+
+	art, err = NewArticleFromHTML(page(`<meta property="article:modified_time" content="2025-03-14T09:30:00Z">`, "Updated an hour ago"))
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	if expected := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC); art.ModifiedDate == nil || !art.ModifiedDate.Equal(expected) {
+		t.Errorf("Expected the structured modified date %v to win, got %v", expected, art.ModifiedDate)
+	}
+
+	art, err = NewArticleFromHTML(page("", "By Jane Doe"))
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	if art.ModifiedDate != nil {
+		t.Errorf("Expected no modified date from body text, got %v", art.ModifiedDate)
+	}
+
+	// A relative banner means nothing without the time of the download
+	art, err = NewArticleFromHTML(page("", "Updated 2 hours ago"))
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	art.FetchedAt = time.Time{}
+	if err := newspaper4k.NewUpdateHistoryExtractor(art.Config).Parse(art); err != nil {
+		t.Fatalf("Error extracting the update history: %v", err)
+	}
+	if art.ModifiedDate != nil {
+		t.Errorf("Expected no modified date without a fetch time, got %v", art.ModifiedDate)
+	}
+}
+
+func TestArticleDedupeSentences(t *testing.T) {
+	const repeated = "Residents were told to stay away from the harbour until the sea wall is repaired."
+	html := `<html><head><title>Storm hits the coast</title></head><body><article>
+<h1>Storm hits the coast</h1>
+<p>The storm reached the coast on Monday and damaged the harbour, flooding the streets of the old town. ` + repeated + `</p>
+<p>` + repeated + ` The council said the repairs would take at least three weeks and cost several million euros.</p>
+</article></body></html>`
+
+	for _, dedupe := range []bool{false, true} {
+		art, err := NewArticleFromHTML(html)
+		if err != nil {
+			t.Fatalf("Error creating article from HTML: %v", err)
+		}
+		art.Config.DedupeSentences = dedupe
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+		expected := 2
+		if dedupe {
+			expected = 1
+		}
+		if count := strings.Count(art.Text, repeated); count != expected {
+			t.Errorf("DedupeSentences=%v: expected %d copies of the repeated sentence, got %d in %q", dedupe, expected, count, art.Text)
+		}
+		if !strings.Contains(art.Text, "three weeks") {
+			t.Errorf("DedupeSentences=%v: expected the rest of the body to be kept, got %q", dedupe, art.Text)
+		}
+	}
+}
+
+func TestArticleDiscussionURL(t *testing.T) {
+	page := func(head, links string) string {
+		return `<html><head><title>Storm hits the coast</title>` + head + `</head><body><article>
+<h1>Storm hits the coast</h1>
+<p>The storm reached the coast on Monday and damaged the harbour, residents said on Tuesday.</p>
+` + links + `</article></body></html>`
+	}
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{"link rel", page(`<link rel="discussion" href="/forum/t/storm-hits-the-coast">`, `<a href="/comments/123">12 comments</a>`), "https://news.example.com/forum/t/storm-hits-the-coast"},
+		{"comments anchor", page("", `<a href="#comments">Comments</a><a href="https://talk.example.com/c/123">Join the discussion</a>`), "https://talk.example.com/c/123"},
+		{"same page only", page("", `<a href="#comments">Comments</a><a href="/2025/storm.html#comments">5 comments</a>`), ""},
+	}
+	for _, tt := range tests {
+		req := NewDefaultParseRequest("https://news.example.com/2025/storm.html")
+		req.InputHTML = tt.html
+		art, err := NewArticleFromRequest(req)
+		if err != nil {
+			t.Fatalf("Error creating article: %v", err)
+		}
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+		if art.DiscussionURL != tt.expected {
+			t.Errorf("%s: expected the discussion URL %q, got %q", tt.name, tt.expected, art.DiscussionURL)
+		}
+	}
+}
+
+func TestArticleBreadcrumbs(t *testing.T) {
+	body := `<article><h1>Storm hits the coast</h1><p>The storm hit the coast on Monday, closing roads, schools and ports across the region.</p></article>`
+	tests := []struct {
+		name     string
+		html     string
+		expected []string
+	}{
+		{
+			name: "json-ld",
+			html: `<html><head><title>Storm hits the coast</title>
+<script type="application/ld+json">{"@context": "https://schema.org", "@graph": [{"@type": "NewsArticle", "headline": "Storm hits the coast"},
+{"@type": "BreadcrumbList", "itemListElement": [
+{"@type": "ListItem", "position": 3, "item": {"@id": "https://example.com/news/weather", "name": "Weather"}},
+{"@type": "ListItem", "position": 1, "name": "Home", "item": "https://example.com/"},
+{"@type": "ListItem", "position": 2, "name": "News", "item": "https://example.com/news"}]}]}</script>
+</head><body><nav class="breadcrumb"><a href="/">Front page</a></nav>` + body + `</body></html>`,
+			expected: []string{"Home", "News", "Weather"},
+		},
+		{
+			name: "microdata",
+			html: `<html><head><title>Storm hits the coast</title></head><body>
+<ol itemscope itemtype="https://schema.org/BreadcrumbList">
+<li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><a itemprop="item" href="/"><span itemprop="name">Home</span></a><meta itemprop="position" content="1"></li>
+<li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><a itemprop="item" href="/news"><span itemprop="name"> News </span></a><meta itemprop="position" content="2"></li>
+</ol>` + body + `</body></html>`,
+			expected: []string{"Home", "News"},
+		},
+		{
+			name:     "breadcrumb links",
+			html:     `<html><head><title>Storm hits the coast</title></head><body><nav aria-label="breadcrumb"><a href="/">Home</a> &gt; <a href="/weather">Weather</a></nav>` + body + `</body></html>`,
+			expected: []string{"Home", "Weather"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			art, err := NewArticleFromHTML(tt.html)
+			if err != nil {
+				t.Fatalf("Error creating article from HTML: %v", err)
+			}
+			if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+				t.Fatalf("Error building article: %v", err)
+			}
+			if !slices.Equal(art.Breadcrumbs, tt.expected) {
+				t.Errorf("Expected breadcrumbs %q, got %q", tt.expected, art.Breadcrumbs)
+			}
+		})
+	}
+}
+
+func TestArticleMaxImages(t *testing.T) {
+	var gallery, related strings.Builder
+	for i := range 40 {
+		fmt.Fprintf(&gallery, `<div class="slide"><img src="/gallery/%d.jpg"></div>`, i)
+		fmt.Fprintf(&related, `<li><img src="/related/%d.jpg"></li>`, i)
+	}
+	html := `<html><head><title>Storm hits the coast</title></head><body>
+<ul class="related">` + related.String() + `</ul>
+<div class="gallery">` + gallery.String() + `</div>
+<div class="content"><article>
+<h1>Storm hits the coast</h1>
+<p>The storm hit the coast on Monday, closing roads, schools and ports across the region. Officials said the damage was the worst in a decade.</p>
+<figure><img src="/photos/harbour.jpg"></figure>
+<p>Thousands of homes lost power overnight and crews were still working on Tuesday to restore the lines that were brought down by the wind.</p>
+<figure><img src="/photos/roads.jpg"></figure>
+<p>More storms are expected later this week, according to the national weather service, which urged residents to stay at home.</p>
+</article></div>
+</body></html>`
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	art.Config.MaxImages = 3
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if len(art.Images) != 3 {
+		t.Fatalf("Expected 3 images, got %d: %v", len(art.Images), art.Images)
+	}
+	for i, suffix := range []string{"/photos/harbour.jpg", "/photos/roads.jpg"} {
+		if !strings.HasSuffix(art.Images[i], suffix) {
+			t.Errorf("Expected the body images first, got %v", art.Images)
+		}
+	}
+
+	art.Config.MaxImages = 0
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	if len(art.Images) != 82 {
+		t.Errorf("Expected every image without MaxImages, got %d", len(art.Images))
+	}
+}
+
+func TestArticleSecureImageURLs(t *testing.T) {
+	html := `<html><head><title>Storm hits the coast</title>
+<link rel="icon" href="//static.example.com/favicon.ico">
+<meta property="og:image" content="http://images.example.com/storm.jpg">
+</head><body><article>
+<h1>Storm hits the coast</h1>
+<p>The storm hit the coast on Monday, closing roads, schools and ports across the region. Officials said the damage was the worst in a decade.</p>
+<img src="http://photos.agency.org/harbour.jpg">
+</article></body></html>`
+
+	for _, upgrade := range []bool{true, false} {
+		art, err := NewArticleFromHTML(html)
+		if err != nil {
+			t.Fatalf("Error creating article from HTML: %v", err)
+		}
+		art.URL = "https://news.example.com/2025/01/06/storm.html"
+		art.Config.UpgradeInsecureImageURLs = upgrade
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+
+		if art.MetaFavicon != "https://static.example.com/favicon.ico" {
+			t.Errorf("Expected the protocol-relative favicon to use https, got %q", art.MetaFavicon)
+		}
+		metaImg := "http://images.example.com/storm.jpg"
+		if upgrade {
+			metaImg = "https://images.example.com/storm.jpg"
+		}
+		if art.MetaImg != metaImg {
+			t.Errorf("UpgradeInsecureImageURLs=%v: expected meta image %q, got %q", upgrade, metaImg, art.MetaImg)
+		}
+		if art.TopImage != "http://photos.agency.org/harbour.jpg" {
+			t.Errorf("Expected the third-party top image to be kept, got %q", art.TopImage)
+		}
+		if !slices.Contains(art.Diagnostics, newspaper.Diagnostic{Severity: newspaper.DiagnosticInfo, Message: "insecure image URL http://photos.agency.org/harbour.jpg on an https page"}) {
+			t.Errorf("Expected a diagnostic for the third-party image, got %q", art.Diagnostics)
+		}
+	}
+}
+
+func TestArticleNoscriptImages(t *testing.T) {
+	html := `<html><head><title>Harbour reopens after the storm</title></head><body><article>
+<h1>Harbour reopens after the storm</h1>
+<img class="lazyload" src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" alt="">
+<noscript><img src="/images/harbour-hero.jpg" width="1200" height="800" alt="The harbour"></noscript>
+<p>The harbour reopened on Monday after the storm that damaged the piers and sank several fishing boats last week.</p>
+<p>Workers repaired the main pier over the weekend and the first ferries left in the morning with a full load of passengers.</p>
+</article></body></html>`
+	const hero = "https://news.example.com/images/harbour-hero.jpg"
+
+	for _, enabled := range []bool{false, true} {
+		req := NewDefaultParseRequest("https://news.example.com/2025/01/06/harbour-reopens.html")
+		req.InputHTML = html
+		art, err := NewArticleFromRequest(req)
+		if err != nil {
+			t.Fatalf("Error creating article: %v", err)
+		}
+		art.Config.ParseNoscriptImages = enabled
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building article: %v", err)
+		}
+		if got := slices.Contains(art.Images, hero); got != enabled {
+			t.Errorf("ParseNoscriptImages=%v: unexpected images %v", enabled, art.Images)
+		}
+		if enabled && (art.TopImage != hero || art.TopImageWidth != 1200) {
+			t.Errorf("Expected the noscript image as top image, got %s (%dx%d)", art.TopImage, art.TopImageWidth, art.TopImageHeight)
+		}
+		if enabled && strings.Contains(art.Text, "<img") {
+			t.Errorf("Expected no markup in the text, got %q", art.Text)
+		}
+	}
+}
+
+func TestArticlePublishDateMicrodata(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		element string
+	}{
+		{"time", `<time itemprop="datePublished" datetime="2025-08-27">Wednesday</time>`},
+		{"span content", `<span itemprop="datePublished" content="2025-08-27T08:30:00Z">Yesterday</span>`},
+		{"span text", `<span itemprop="datePublished">2025-08-27</span>`},
+		{"itemprop list", `<span itemprop="datePublished dateCreated">August 27, 2025</span>`},
+	} {
+		html := `<html><head><title>Harbour reopens after the storm</title></head><body><article>
+<h1>Harbour reopens after the storm</h1>
+<p>Published ` + tc.element + `</p>
+<p>The harbour reopened on Wednesday after a week of repairs to the sea wall damaged by the storm.</p>
+<p>Fishing boats were the first to leave, followed by the ferry to the islands in the afternoon.</p>
+</article></body></html>`
+		art, err := NewArticleFromHTML(html)
+		if err != nil {
+			t.Fatalf("%s: error creating article from HTML: %v", tc.name, err)
+		}
+		if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("%s: error building article: %v", tc.name, err)
+		}
+		if art.PublishDate == nil || art.PublishDate.Year() != 2025 || art.PublishDate.Month() != time.August || art.PublishDate.Day() != 27 {
+			t.Errorf("%s: expected the publish date from the microdata, got %v", tc.name, art.PublishDate)
+		}
+	}
+}
+
+func TestArticleDublinCore(t *testing.T) {
+	html := `<html><head><title>Sea level rise along the coast | Institutional Repository</title>
+<meta name="DC.title" content="Sea level rise along the coast">
+<meta name="DC.creator" content="Doe, Jane">
+<meta name="DC.creator" content="John Smith">
+<meta name="DC.date" content="2024-03-15">
+<meta name="DC.subject" content="Climate; Oceans">
+<meta name="DCTERMS.publisher" content="Coastal Research Institute">
+<meta name="eprints.type" content="article">
+</head><body><article>
+<h1>Sea level rise along the coast</h1>
+<p>Sea levels along the coast rose faster over the last decade than in the previous century, according to the survey published on Friday.</p>
+<p>The report combines tide gauge records with satellite measurements collected since the early nineties.</p>
+</article></body></html>`
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if expected := []string{"Jane Doe", "John Smith"}; !slices.Equal(art.Authors, expected) {
+		t.Errorf("Expected authors %q from DC.creator, got %q", expected, art.Authors)
+	}
+	if art.PublishDate == nil || !art.PublishDate.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the publish date from DC.date, got %v", art.PublishDate)
+	}
+	if expected := []string{"Climate", "Oceans"}; !slices.Equal(art.MetaKeywords, expected) {
+		t.Errorf("Expected meta keywords %q from DC.subject, got %q", expected, art.MetaKeywords)
+	}
+	if art.Title != "Sea level rise along the coast" {
+		t.Errorf("Unexpected title %q", art.Title)
+	}
+	if art.DublinCore["dcterms.publisher"] != "Coastal Research Institute" || art.DublinCore["eprints.type"] != "article" ||
+		art.DublinCore["dc.creator"] != "Doe, Jane; John Smith" {
+		t.Errorf("Unexpected Dublin Core tags %v", art.DublinCore)
+	}
+}
+
+func TestArticleDublinCorePRISM(t *testing.T) {
+	html := `<html><head><title>Glacier retreat in the Alps</title>
+<meta name="keywords" content="ice sheets, climate">
+<meta name="DC.Creator" content="Doe, Jane">
+<meta name="dc.creator" content="John Smith">
+<meta name="DC.Contributor" content="Ana Lopez">
+<meta name="dc.subject" content="Glaciology; Climate">
+<meta name="DCTERMS.abstract" content="Alpine glaciers lost a tenth of their volume in two years.">
+<meta name="prism.publicationName" content="Journal of Alpine Studies">
+<meta name="prism.doi" content="doi:10.1234/jas.2024.0042">
+<meta name="dc.identifier" content="ISSN 1234-5678">
+</head><body><article>
+<h1>Glacier retreat in the Alps</h1>
+<p>Alpine glaciers retreated faster over the last two summers than in any period since measurements began, the study found.</p>
+<p>The authors combined aerial surveys with field measurements taken on forty glaciers across four countries.</p>
+</article></body></html>`
+
+	art, err := NewArticleFromHTML(html)
+	if err != nil {
+		t.Fatalf("Error creating article from HTML: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	if expected := []string{"Jane Doe", "John Smith", "Ana Lopez"}; !slices.Equal(art.Authors, expected) {
+		t.Errorf("Expected authors %q from the creators and contributors, got %q", expected, art.Authors)
+	}
+	if expected := []string{"ice sheets", "climate", "Glaciology"}; !slices.Equal(art.MetaKeywords, expected) {
+		t.Errorf("Expected the subjects merged into the meta keywords %q, got %q", expected, art.MetaKeywords)
+	}
+	if art.MetaDescription != "Alpine glaciers lost a tenth of their volume in two years." {
+		t.Errorf("Expected the abstract as description, got %q", art.MetaDescription)
+	}
+	if art.MetaSiteName != "Journal of Alpine Studies" {
+		t.Errorf("Expected the publication name as site name, got %q", art.MetaSiteName)
+	}
+	if art.DOI != "10.1234/jas.2024.0042" {
+		t.Errorf("Expected the DOI from prism.doi, got %q", art.DOI)
+	}
+}
+
+func TestArticleLazyTopImage(t *testing.T) {
+	tests := []struct {
+		name string
+		img  string
+	}{
+		{"data-src", `<img class="lazyload" src="/static/placeholder.gif" data-src="/photos/storm-hero.jpg" alt="Storm">`},
+		{"data-srcset", `<img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" data-srcset="/photos/storm-hero-small.jpg 480w, /photos/storm-hero.jpg 1200w">`},
+		{"srcset", `<img src="/static/blank.png" srcset="/photos/storm-hero-small.jpg 1x, /photos/storm-hero.jpg 2x">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := `<html><head><title>Storm hits the coast</title></head><body><article>
+<h1>Storm hits the coast</h1>
+<figure>` + tt.img + `</figure>
+<p>The storm hit the coast on Monday, closing roads, schools and ports across the region. Officials said the damage was the worst in a decade.</p>
+</article></body></html>`
+			art, err := NewArticleFromHTML(html)
+			if err != nil {
+				t.Fatalf("Error creating article from HTML: %v", err)
+			}
+			art.URL = "https://news.example.com/2025/01/06/storm.html"
+			if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+				t.Fatalf("Error building article: %v", err)
+			}
+			if art.TopImage != "https://news.example.com/photos/storm-hero.jpg" {
+				t.Errorf("Expected the lazy-loaded hero image as top image, got %q", art.TopImage)
+			}
+			if !slices.Contains(art.Images, art.TopImage) {
+				t.Errorf("Expected the hero image among the images, got %v", art.Images)
+			}
+		})
+	}
+}
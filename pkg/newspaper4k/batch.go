@@ -0,0 +1,154 @@
+package newspaper4k
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/tguidoux/newspaper4k-go/internal/urls"
+	"github.com/tguidoux/newspaper4k-go/pkg/extractors/newspaper4k"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+// BatchOptions controls how BuildArticles processes a batch of articles
+type BatchOptions struct {
+	// SkipDuplicates stops an article after its metadata when its canonical URL
+	// matches an article already built in the batch, and before NLP when the
+	// fingerprint of its cleaned text does
+	SkipDuplicates bool
+}
+
+// BuildReport summarizes a BuildArticles run
+type BuildReport struct {
+	Built      int        // Articles that went through the whole pipeline
+	Duplicates int        // Articles short-circuited as duplicates, see Article.DuplicateOf
+	Failed     int        // Articles that could not be downloaded or parsed
+	Errors     []error    // One error per failed article
+	Stats      BatchStats // Outcomes and durations of the extractor runs
+}
+
+// minFingerprintWords is the number of words a paragraph needs to be part of a fingerprint
+const minFingerprintWords = 15
+
+// fingerprintParagraphs is the number of leading paragraphs hashed into a fingerprint
+const fingerprintParagraphs = 3
+
+// BuildArticles builds the articles of a batch, typically the result of
+// Source.GetArticles, in place. With SkipDuplicates, print, AMP and mobile
+// variants of a story that was already built in the batch are detected once
+// their metadata is extracted, or once their body is when only the text
+// matches, and are not processed any further.
+// Only context cancellation stops the batch early.
+func BuildArticles(ctx context.Context, articles []newspaper.Article, extractors []newspaper.Extractor, opts BatchOptions) (BuildReport, error) {
+	var report BuildReport
+	seen := make(map[string]string)
+
+	for i := range articles {
+		if err := ctx.Err(); err != nil {
+			report.Stats.computePercentiles()
+			return report, err
+		}
+		a := &articles[i]
+
+		if err := a.Download(); err != nil {
+			report.Failed++
+			report.Errors = append(report.Errors, fmt.Errorf("%s: %w", a.URL, err))
+			continue
+		}
+
+		var keys []string
+		if opts.SkipDuplicates {
+			if err := newspaper4k.NewMetadataExtractor(a.Config).Parse(a); err != nil {
+				report.Failed++
+				report.Errors = append(report.Errors, fmt.Errorf("%s: %w", a.URL, err))
+				continue
+			}
+			keys = urlKeys(a)
+			if original := firstSeen(seen, keys); original != "" {
+				a.DuplicateOf = original
+				report.Duplicates++
+				continue
+			}
+		}
+
+		if err := a.ParseObserved(extractors, report.Stats.observer(a)); err != nil {
+			report.Failed++
+			report.Errors = append(report.Errors, fmt.Errorf("%s: %w", a.URL, err))
+			continue
+		}
+		if opts.SkipDuplicates {
+			if fp := contentFingerprint(a.Text); fp != "" {
+				keys = append(keys, "content:"+fp)
+			}
+			if original := firstSeen(seen, keys); original != "" {
+				a.DuplicateOf = original
+				report.Duplicates++
+				continue
+			}
+		}
+		if err := a.NLP(); err != nil {
+			report.Failed++
+			report.Errors = append(report.Errors, fmt.Errorf("%s: %w", a.URL, err))
+			continue
+		}
+		report.Built++
+
+		for _, key := range keys {
+			if _, ok := seen[key]; !ok {
+				seen[key] = a.URL
+			}
+		}
+	}
+
+	report.Stats.computePercentiles()
+	return report, nil
+}
+
+// urlKeys returns the keys identifying the story of an article by its URL
+// and its canonical URL
+func urlKeys(a *newspaper.Article) []string {
+	var keys []string
+	for _, u := range []string{a.URL, a.CanonicalLink} {
+		if u == "" {
+			continue
+		}
+		if parsed, err := urls.Parse(u); err == nil {
+			keys = append(keys, "url:"+parsed.Host+strings.TrimSuffix(parsed.Path, "/"))
+		}
+	}
+	return keys
+}
+
+// firstSeen returns the URL of the article registered under one of keys
+func firstSeen(seen map[string]string, keys []string) string {
+	for _, key := range keys {
+		if original, ok := seen[key]; ok {
+			return original
+		}
+	}
+	return ""
+}
+
+// contentFingerprint hashes the first long paragraphs of the cleaned text of
+// an article, which the variants of a story share even when their page
+// layout differs
+func contentFingerprint(text string) string {
+	var paragraphs []string
+	for line := range strings.Lines(text) {
+		words := strings.Fields(strings.ToLower(line))
+		if len(words) < minFingerprintWords {
+			continue
+		}
+		if paragraphs = append(paragraphs, strings.Join(words, " ")); len(paragraphs) == fingerprintParagraphs {
+			break
+		}
+	}
+	if len(paragraphs) == 0 {
+		return ""
+	}
+
+	sum := sha1.Sum([]byte(strings.Join(paragraphs, "\n")))
+	return hex.EncodeToString(sum[:])
+}
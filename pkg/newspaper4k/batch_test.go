@@ -0,0 +1,169 @@
+package newspaper4k
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspapertest"
+)
+
+const storyParagraphs = `<p>The regional council said on Monday that the new budget would focus on schools and public transport in the coming years.</p>
+<p>Opposition members criticised the plan and said that it did not do enough for the rural areas of the region.</p>`
+
+func TestBuildArticlesSkipDuplicates(t *testing.T) {
+	var requests atomic.Int32
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/2025/03/14/council-budget.html":
+			fmt.Fprintf(w, `<html><head><title>Council budget</title><link rel="canonical" href="%s/2025/03/14/council-budget.html"></head>
+<body><nav><a href="/">Home</a></nav><article>%s</article><footer>Regional News</footer></body></html>`, srv.URL, storyParagraphs)
+		case "/amp/2025/03/14/council-budget.html":
+			fmt.Fprintf(w, `<html amp><head><title>Council budget</title><link rel="canonical" href="%s/2025/03/14/council-budget.html"></head>
+<body><article>%s</article></body></html>`, srv.URL, storyParagraphs)
+		case "/print/2025/03/14/council-budget.html":
+			fmt.Fprintf(w, `<html><head><title>Council budget (print)</title></head><body><article>%s</article></body></html>`, storyParagraphs)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	config := configuration.NewConfiguration()
+	articles := []newspaper.Article{
+		{URL: srv.URL + "/2025/03/14/council-budget.html", Config: config},
+		{URL: srv.URL + "/amp/2025/03/14/council-budget.html", Config: config},
+		{URL: srv.URL + "/print/2025/03/14/council-budget.html", Config: config},
+	}
+
+	report, err := BuildArticles(context.Background(), articles, DefaultExtractors(config), BatchOptions{SkipDuplicates: true})
+	if err != nil {
+		t.Fatalf("BuildArticles returned an error: %v", err)
+	}
+
+	if report.Built != 1 || report.Duplicates != 2 || report.Failed != 0 {
+		t.Errorf("Expected 1 built and 2 duplicates, got %+v", report)
+	}
+	if !articles[0].IsParsed || articles[0].Text == "" {
+		t.Error("Expected the first variant to be fully built")
+	}
+	for _, dup := range articles[1:] {
+		if dup.DuplicateOf != articles[0].URL {
+			t.Errorf("Expected %s to be a duplicate of %s, got %q", dup.URL, articles[0].URL, dup.DuplicateOf)
+		}
+	}
+	// The AMP variant shares the canonical URL and is stopped after its
+	// metadata, the print variant only shares the text and is stopped before NLP
+	if amp := articles[1]; amp.IsParsed || amp.Text != "" {
+		t.Errorf("Expected %s to be short-circuited", amp.URL)
+	}
+	if printed := articles[2]; printed.Text != articles[0].Text || printed.Summary != "" {
+		t.Errorf("Expected %s to be parsed but not processed by NLP", printed.URL)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("Expected one download per variant, got %d requests", n)
+	}
+
+	// Without SkipDuplicates every variant is built
+	fresh := []newspaper.Article{
+		{URL: srv.URL + "/2025/03/14/council-budget.html", Config: config},
+		{URL: srv.URL + "/print/2025/03/14/council-budget.html", Config: config},
+	}
+	report, err = BuildArticles(context.Background(), fresh, DefaultExtractors(config), BatchOptions{})
+	if err != nil {
+		t.Fatalf("BuildArticles returned an error: %v", err)
+	}
+	if report.Built != 2 || report.Duplicates != 0 {
+		t.Errorf("Expected 2 built articles without deduplication, got %+v", report)
+	}
+}
+
+func TestBuildArticlesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	config := configuration.NewConfiguration()
+	articles := []newspaper.Article{{URL: "https://example.com/2025/03/14/story.html", Config: config}}
+	if _, err := BuildArticles(ctx, articles, DefaultExtractors(config), BatchOptions{}); err == nil {
+		t.Error("Expected an error for a cancelled context")
+	}
+}
+
+func TestBuildArticlesStats(t *testing.T) {
+	site := newspapertest.NewFakeSite(t, newspapertest.SiteSpec{
+		Categories: []newspapertest.CategorySpec{{Name: "World", Articles: []newspapertest.ArticleSpec{
+			{Title: "Storm hits the coast", Authors: []string{"Jane Doe"}},
+			{Title: "Council approves budget", Authors: []string{"John Smith"}},
+			{Title: "Broken markup story", Authors: []string{"Jane Doe"}},
+		}}},
+		ArticleTemplate: `<html lang="en"><head><title>{{.Title}}</title>
+{{if eq .Title "Council approves budget"}}<meta property="og:locale" content="fr_FR">{{end}}
+{{if eq .Title "Broken markup story"}}<script type="application/ld+json">{"@type": "NewsArticle", "author": </script>
+{{else}}<script type="application/ld+json">{"@type": "NewsArticle", "author": {"@type": "Person", "name": "{{index .Authors 0}}"}}</script>
+{{end}}</head><body><article>{{range .Paragraphs}}<p>{{.}}</p>{{end}}</article></body></html>`,
+	})
+
+	config := configuration.NewConfiguration()
+	var articles []newspaper.Article
+	for _, expected := range site.Articles() {
+		articles = append(articles, newspaper.Article{URL: expected.URL, Config: config})
+	}
+
+	report, err := BuildArticles(context.Background(), articles, DefaultExtractors(config), BatchOptions{})
+	if err != nil {
+		t.Fatalf("BuildArticles returned an error: %v", err)
+	}
+	if report.Built != 3 {
+		t.Fatalf("Expected 3 built articles, got %+v", report)
+	}
+
+	// The malformed block is an error of the authors extractor, the
+	// conflicting language signals are a notice of the metadata extractor
+	for name, stats := range report.Stats.Extractors {
+		expected := 0
+		if name == "authors" {
+			expected = 1
+		}
+		if stats.Errors != expected {
+			t.Errorf("Expected %d errors for the %s extractor, got %d", expected, name, stats.Errors)
+		}
+	}
+	var notices int
+	for _, article := range articles {
+		for _, diagnostic := range article.Diagnostics {
+			if diagnostic.Severity == newspaper.DiagnosticInfo {
+				notices++
+			}
+		}
+	}
+	if notices != 1 {
+		t.Errorf("Expected a notice for the conflicting language signals, got %d", notices)
+	}
+	authors := report.Stats.Extractors["authors"]
+	if authors == nil || authors.Runs != 3 || authors.Empty != 1 || authors.ErrorRate() != 1.0/3 {
+		t.Errorf("Expected 3 runs with 1 error and 1 empty result for the authors extractor, got %+v", authors)
+	}
+	if language := report.Stats.Extractors["language"]; language == nil || language.Runs != 6 {
+		t.Errorf("Expected the language extractor to run twice per article, got %+v", language)
+	}
+	if body := report.Stats.Extractors["body"]; body == nil || body.P50 <= 0 || body.P99 < body.P50 {
+		t.Errorf("Expected duration percentiles for the body extractor, got %+v", body)
+	}
+
+	out, err := json.Marshal(report.Stats)
+	if err != nil {
+		t.Fatalf("Error serializing the stats: %v", err)
+	}
+	if !strings.Contains(string(out), `"authors":{"runs":3,"errors":1,"empty":1,`) {
+		t.Errorf("Unexpected JSON stats %s", out)
+	}
+}
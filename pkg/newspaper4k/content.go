@@ -0,0 +1,157 @@
+package newspaper4k
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/helpers"
+	"github.com/tguidoux/newspaper4k-go/internal/parsers"
+	"github.com/tguidoux/newspaper4k-go/internal/urls"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/extractors/newspaper4k"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+// ContentOptions configures ExtractContent
+type ContentOptions struct {
+	// Language is the 2 char code of the content language, e.g. "en". It is
+	// detected when empty.
+	Language string
+	// BaseURL resolves the relative image and link URLs, which are kept
+	// relative when empty
+	BaseURL string
+	// Config is the configuration the content is extracted with, as for an
+	// Article, NewConfiguration() when nil. It is copied, not modified.
+	Config *configuration.Configuration
+	// DropImageCaptions removes the <figcaption> text from Text, overriding
+	// Configuration.KeepImageCaptions
+	DropImageCaptions bool
+	// StripEmoji removes emoji from Text, see Configuration.StripEmoji
+	StripEmoji bool
+	// DedupeSentences removes repeated consecutive sentences from Text, see Configuration.DedupeSentences
+	DedupeSentences bool
+}
+
+// ContentResult is the main content extracted from an HTML page or fragment
+type ContentResult struct {
+	Title  string   // Title of the page, empty if none could be derived
+	Text   string   // Text of the main content
+	HTML   string   // Cleaned HTML of the main content
+	Images []string // Image URLs of the main content
+	Links  []string // Link URLs of the main content, in document order
+}
+
+// ExtractContent extracts the main content of a full HTML page or of an HTML
+// fragment, such as an e-mail body or an RSS content:encoded value, with the
+// cleaner and body extraction of the Article pipeline. No URL is needed and
+// nothing is fetched. Fragments too short for the body scoring are cleaned
+// and returned whole.
+func ExtractContent(htmlContent string, opts ContentOptions) (ContentResult, error) {
+	htmlContent = unwrapFragment(htmlContent)
+	if htmlContent == "" {
+		return ContentResult{}, errors.New("empty HTML content")
+	}
+
+	config := configuration.NewConfiguration()
+	if opts.Config != nil {
+		copied := *opts.Config
+		config = &copied
+	}
+	if opts.Language != "" {
+		if err := config.SetLanguage(opts.Language); err != nil {
+			return ContentResult{}, err
+		}
+	}
+	if opts.DropImageCaptions {
+		config.KeepImageCaptions = false
+	}
+	config.StripEmoji = config.StripEmoji || opts.StripEmoji
+	config.DedupeSentences = config.DedupeSentences || opts.DedupeSentences
+	config.DownloadOptions.InputHTML = htmlContent
+
+	art, err := NewArticleFromRequest(newspaper.ParseRequest{URL: opts.BaseURL, InputHTML: htmlContent})
+	if err != nil {
+		return ContentResult{}, err
+	}
+	art.Config = config
+	if err := art.Download(); err != nil {
+		return ContentResult{}, err
+	}
+	if err := art.Parse(contentExtractors(config)); err != nil {
+		return ContentResult{}, fmt.Errorf("error parsing content: %w", err)
+	}
+
+	content := art.TopNode
+	if content == nil || strings.TrimSpace(art.Text) == "" {
+		// Short fragments have no node scoring high enough: keep the whole body
+		content = art.CleanNode(art.Doc.Find("body").First())
+		art.Text = parsers.GetText(content)
+		if config.StripEmoji {
+			art.Text = parsers.StripEmoji(art.Text)
+		}
+		if config.DedupeSentences {
+			art.Text = parsers.DedupeConsecutiveSentences(art.Text)
+		}
+		art.ArticleHTML = parsers.NodeToString(content)
+		if len(art.Images) == 0 {
+			content.Find("img[src]").Each(func(i int, img *goquery.Selection) {
+				art.Images = append(art.Images, urls.JoinURL(opts.BaseURL, img.AttrOr("src", "")))
+			})
+			art.Images = helpers.UniqueStringsSimple(art.Images)
+		}
+	}
+
+	return ContentResult{
+		Title:  art.Title,
+		Text:   art.Text,
+		HTML:   art.ArticleHTML,
+		Images: art.Images,
+		Links:  contentLinks(content, opts.BaseURL),
+	}, nil
+}
+
+// contentExtractors are the extractors of DefaultExtractors needed for the
+// title, body and images
+func contentExtractors(config *configuration.Configuration) []newspaper.Extractor {
+	return []newspaper.Extractor{
+		newspaper4k.NewMetadataExtractor(config),
+		newspaper4k.NewLanguageExtractor(config),
+		newspaper4k.NewTitleExtractor(config),
+		newspaper4k.NewBodyExtractor(config),
+		newspaper4k.NewLanguageExtractor(config),
+		newspaper4k.NewImageExtractor(config),
+	}
+}
+
+// unwrapFragment trims htmlContent and removes the CDATA section or the
+// entity escaping of feed values
+func unwrapFragment(htmlContent string) string {
+	htmlContent = strings.TrimSpace(htmlContent)
+	if inner, ok := strings.CutPrefix(htmlContent, "<![CDATA["); ok {
+		htmlContent = strings.TrimSpace(strings.TrimSuffix(inner, "]]>"))
+	}
+	if !strings.Contains(htmlContent, "<") && strings.Contains(htmlContent, "&lt;") {
+		htmlContent = strings.TrimSpace(html.UnescapeString(htmlContent))
+	}
+	return htmlContent
+}
+
+// contentLinks returns the distinct link URLs under content, resolved
+// against baseURL, ignoring in-page anchors and javascript: links
+func contentLinks(content *goquery.Selection, baseURL string) []string {
+	links := []string{}
+	if content == nil {
+		return links
+	}
+	content.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		href := strings.TrimSpace(s.AttrOr("href", ""))
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			return
+		}
+		links = append(links, urls.JoinURL(baseURL, href))
+	})
+	return helpers.UniqueStringsSimple(links)
+}
@@ -0,0 +1,103 @@
+package newspaper4k
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/constants"
+)
+
+func TestExtractContentFragment(t *testing.T) {
+	fragment := `<![CDATA[<p>The regional council said on Monday that the new budget would focus on schools and public transport.</p>
+<p><img src="/images/council.jpg" alt="The council"> Read the <a href="/budget/2025">full budget</a> or the <a href="#notes">notes</a>.</p>
+<script>trackView();</script>]]>`
+
+	result, err := ExtractContent(fragment, ContentOptions{Language: "en", BaseURL: "https://example.com/news/"})
+	if err != nil {
+		t.Fatalf("ExtractContent returned an error: %v", err)
+	}
+	if !strings.Contains(result.Text, "the new budget would focus on schools") || !strings.Contains(result.Text, "full budget") {
+		t.Errorf("Expected the fragment text, got %q", result.Text)
+	}
+	if strings.Contains(result.Text, "trackView") || strings.Contains(result.HTML, "<script") {
+		t.Errorf("Expected the script to be cleaned, got %q and %q", result.Text, result.HTML)
+	}
+	if expected := []string{"https://example.com/images/council.jpg"}; !slices.Equal(result.Images, expected) {
+		t.Errorf("Expected images %v, got %v", expected, result.Images)
+	}
+	if expected := []string{"https://example.com/budget/2025"}; !slices.Equal(result.Links, expected) {
+		t.Errorf("Expected links %v, got %v", expected, result.Links)
+	}
+
+	if _, err := ExtractContent("  <![CDATA[ ]]> ", ContentOptions{}); err == nil {
+		t.Error("Expected an error for an empty fragment")
+	}
+	if _, err := ExtractContent("<p>Text</p>", ContentOptions{Language: "english"}); err == nil {
+		t.Error("Expected an error for an invalid language")
+	}
+}
+
+func TestExtractContentParity(t *testing.T) {
+	page, err := os.ReadFile("testdata/minimal_blog_post.html")
+	if err != nil {
+		t.Fatalf("Error reading the page: %v", err)
+	}
+
+	art, err := NewArticleFromHTML(string(page))
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+
+	result, err := ExtractContent(string(page), ContentOptions{})
+	if err != nil {
+		t.Fatalf("ExtractContent returned an error: %v", err)
+	}
+	if result.Text == "" || result.Text != art.Text {
+		t.Errorf("Expected the text of the article pipeline %q, got %q", art.Text, result.Text)
+	}
+	if result.Title != art.Title || result.HTML != art.ArticleHTML {
+		t.Errorf("Expected title %q and the article HTML, got %q and %q", art.Title, result.Title, result.HTML)
+	}
+
+	captioned := `<html><head><title>New bridge opens</title></head><body><article>
+<p>The new bridge over the river opened to traffic on Monday after three years of construction work and several delays.</p>
+<figure><img src="https://example.com/bridge.jpg"><figcaption>Engineer Hernandez inspects the bridge before the opening</figcaption></figure>
+<p>The bridge connects the two halves of the city and is expected to reduce traffic in the center by a third.</p>
+<figure><img src="https://example.com/crowd.jpg"><figcaption>Photo: Reuters</figcaption></figure>
+<figure><img src="https://example.com/river.jpg"><figcaption>Courtesy of the city archives</figcaption></figure>
+</article></body></html>`
+	config := configuration.NewConfiguration()
+	config.CaptionCreditPatterns = append(slices.Clone(constants.CAPTION_CREDIT_PATTERNS), `^courtesy of\b`)
+	art, err = NewArticleFromHTML(captioned)
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	art.Config.CaptionCreditPatterns = config.CaptionCreditPatterns
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	result, err = ExtractContent(captioned, ContentOptions{Config: config})
+	if err != nil {
+		t.Fatalf("ExtractContent returned an error: %v", err)
+	}
+	if result.Text != art.Text || !strings.Contains(result.Text, "Engineer Hernandez inspects the bridge") {
+		t.Errorf("Expected the captioned text of the article pipeline %q, got %q", art.Text, result.Text)
+	}
+	if strings.Contains(result.Text, "Reuters") || strings.Contains(result.Text, "city archives") {
+		t.Errorf("Expected the credit lines to be removed, got %q", result.Text)
+	}
+
+	result, err = ExtractContent(captioned, ContentOptions{DropImageCaptions: true})
+	if err != nil {
+		t.Fatalf("ExtractContent returned an error: %v", err)
+	}
+	if strings.Contains(result.Text, "Engineer Hernandez") {
+		t.Errorf("Expected DropImageCaptions to remove the caption, got %q", result.Text)
+	}
+}
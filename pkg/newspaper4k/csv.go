@@ -0,0 +1,94 @@
+package newspaper4k
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+	"golang.org/x/text/language"
+)
+
+// csvColumns maps the column names accepted by WriteArticlesCSV to the value
+// written for an article
+var csvColumns = map[string]func(a *newspaper.Article) string{
+	"url":   func(a *newspaper.Article) string { return a.URL },
+	"title": func(a *newspaper.Article) string { return a.Title },
+	"authors": func(a *newspaper.Article) string {
+		return strings.Join(a.Authors, ";")
+	},
+	"author_count": func(a *newspaper.Article) string { return strconv.Itoa(len(a.Authors)) },
+	"publish_date": func(a *newspaper.Article) string {
+		if a.PublishDate == nil {
+			return ""
+		}
+		return a.PublishDate.Format(time.RFC3339)
+	},
+	"language": func(a *newspaper.Article) string {
+		// Discovered articles are not parsed yet: do not report the English fallback of GetLanguage
+		if a.Language != language.Und {
+			return a.Language.String()
+		}
+		return a.MetaLang
+	},
+	"word_count": func(a *newspaper.Article) string { return strconv.Itoa(len(strings.Fields(a.Text))) },
+	"top_image":  func(a *newspaper.Article) string { return a.TopImage },
+	"summary":    func(a *newspaper.Article) string { return a.Summary },
+	"section": func(a *newspaper.Article) string {
+		if section := a.MetaData["article:section"]; section != "" {
+			return section
+		}
+		return a.SectionFromURL()
+	},
+	"source_url": func(a *newspaper.Article) string { return a.SourceURL },
+}
+
+// CSVColumns returns the column names accepted by WriteArticlesCSV, sorted
+func CSVColumns() []string {
+	columns := make([]string, 0, len(csvColumns))
+	for name := range csvColumns {
+		columns = append(columns, name)
+	}
+	slices.Sort(columns)
+	return columns
+}
+
+// WriteArticlesCSV writes a header row followed by one row per article, with
+// the given columns in order (see CSVColumns). Authors are joined with ";"
+// and publish dates are formatted as RFC3339, empty when unknown.
+func WriteArticlesCSV(w io.Writer, articles []*newspaper.Article, columns []string) error {
+	values := make([]func(a *newspaper.Article) string, len(columns))
+	for i, column := range columns {
+		value, ok := csvColumns[column]
+		if !ok {
+			return fmt.Errorf("unknown CSV column %q, valid columns are %s", column, strings.Join(CSVColumns(), ", "))
+		}
+		values[i] = value
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+	record := make([]string, len(columns))
+	for _, a := range articles {
+		if a == nil {
+			continue
+		}
+		for i, value := range values {
+			record[i] = value(a)
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV row for %s: %w", a.URL, err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("error writing CSV: %w", err)
+	}
+	return nil
+}
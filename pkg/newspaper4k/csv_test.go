@@ -0,0 +1,65 @@
+package newspaper4k
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+	"golang.org/x/text/language"
+)
+
+func TestWriteArticlesCSV(t *testing.T) {
+	published := time.Date(2025, 1, 6, 9, 30, 0, 0, time.UTC)
+	articles := []*newspaper.Article{
+		{
+			URL:         "https://example.com/2025/01/06/storm.html",
+			SourceURL:   "https://example.com",
+			Title:       "Storm hits the coast",
+			Authors:     []string{"Jane Doe", "John Smith"},
+			PublishDate: &published,
+			Language:    language.English,
+			Text:        "The storm hit the coast on Monday.",
+			Summary:     "Roads, schools and ports were closed.\nMore storms are expected, \"officials\" said.",
+			MetaData:    map[string]string{"article:section": "Weather"},
+		},
+		{
+			URL:       "https://example.com/2025/01/06/budget.html",
+			SourceURL: "https://example.com",
+			Title:     "Council approves budget",
+			MetaLang:  "fr",
+		},
+	}
+	columns := []string{"title", "url", "authors", "author_count", "publish_date", "language", "word_count", "summary", "section", "top_image", "source_url"}
+
+	var buf bytes.Buffer
+	if err := WriteArticlesCSV(&buf, articles, columns); err != nil {
+		t.Fatalf("Error writing CSV: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Error reading CSV back: %v", err)
+	}
+	if len(records) != 3 || !slices.Equal(records[0], columns) {
+		t.Fatalf("Expected a header and two rows, got %q", records)
+	}
+
+	expected := [][]string{
+		{"Storm hits the coast", articles[0].URL, "Jane Doe;John Smith", "2", "2025-01-06T09:30:00Z", "en", "7", articles[0].Summary, "Weather", "", "https://example.com"},
+		{"Council approves budget", articles[1].URL, "", "0", "", "fr", "0", "", "", "", "https://example.com"},
+	}
+	for i, row := range expected {
+		if !slices.Equal(records[i+1], row) {
+			t.Errorf("Row %d: expected %q, got %q", i+1, row, records[i+1])
+		}
+	}
+
+	err = WriteArticlesCSV(&buf, articles, []string{"url", "body"})
+	if err == nil || !strings.Contains(err.Error(), `"body"`) || !strings.Contains(err.Error(), "word_count") {
+		t.Errorf("Expected an error listing the valid columns, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package newspaper4k
+
+import (
+	"strings"
+
+	"github.com/tguidoux/newspaper4k-go/internal/parsers"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+// NewArticleFromURLInLanguage creates and downloads the article at url in
+// lang, a language code such as "fr". When the downloaded page is in another
+// language and declares an alternate in lang (<link rel="alternate"
+// hreflang="fr">), the alternate is downloaded instead and its URL becomes
+// the URL of the article. The page is kept when it declares no alternate in
+// lang. The article is downloaded but not parsed: call Parse and NLP, or
+// Build, to extract it. A nil cfg uses the default configuration.
+func NewArticleFromURLInLanguage(url, lang string, cfg *configuration.Configuration) (*newspaper.Article, error) {
+	art, err := newDownloadedArticle(url, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	lang = parsers.LanguageCode(lang)
+	if lang == "" || art.Doc == nil || parsers.LangAttribute(art.Doc.Find("html")) == lang {
+		return art, nil
+	}
+	alternate := parsers.HreflangLink(art.URL, art.Doc.Selection, lang)
+	if alternate == "" || strings.EqualFold(alternate, art.URL) {
+		return art, nil
+	}
+	return newDownloadedArticle(alternate, cfg)
+}
+
+// newDownloadedArticle creates the article at url with a copy of cfg and
+// downloads it
+func newDownloadedArticle(url string, cfg *configuration.Configuration) (*newspaper.Article, error) {
+	art, err := NewArticleFromURL(url)
+	if err != nil {
+		return nil, err
+	}
+	if cfg != nil {
+		config := *cfg
+		config.DownloadOptions.InputHTML = ""
+		art.Config = &config
+	}
+	if err := art.Download(); err != nil {
+		return nil, err
+	}
+	return art, nil
+}
@@ -0,0 +1,62 @@
+package newspaper4k
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+)
+
+func TestNewArticleFromURLInLanguage(t *testing.T) {
+	page := func(lang, title, text string) string {
+		return `<html lang="` + lang + `"><head><title>` + title + `</title>
+<link rel="alternate" hreflang="en" href="/en/storm">
+<link rel="alternate" hreflang="fr-CA" href="/ca/tempete">
+<link rel="alternate" hreflang="fr" href="/fr/tempete">
+<link rel="alternate" hreflang="x-default" href="/en/storm">
+</head><body><article><h1>` + title + `</h1><p>` + text + `</p></article></body></html>`
+	}
+	var fetched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = append(fetched, r.URL.Path)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch r.URL.Path {
+		case "/en/storm":
+			fmt.Fprint(w, page("en", "Storm hits the coast", "The storm reached the coast on Monday and damaged the harbour."))
+		case "/fr/tempete":
+			fmt.Fprint(w, page("fr", "La tempête frappe la côte", "La tempête a atteint la côte lundi et endommagé le port."))
+		case "/ca/tempete":
+			fmt.Fprint(w, page("fr-CA", "La tempête frappe la côte", "La tempête a atteint la côte lundi."))
+		}
+	}))
+	defer server.Close()
+
+	art, err := NewArticleFromURLInLanguage(server.URL+"/en/storm", "fr", nil)
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	if art.URL != server.URL+"/fr/tempete" {
+		t.Errorf("Expected the French alternate to be fetched, got %s", art.URL)
+	}
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		t.Fatalf("Error building article: %v", err)
+	}
+	if art.Title != "La tempête frappe la côte" {
+		t.Errorf("Expected the French title, got %q", art.Title)
+	}
+	if expected := []string{"/en/storm", "/fr/tempete"}; !slices.Equal(fetched, expected) {
+		t.Errorf("Expected fetches %v, got %v", expected, fetched)
+	}
+
+	fetched = nil
+	art, err = NewArticleFromURLInLanguage(server.URL+"/en/storm", "en", configuration.NewConfiguration())
+	if err != nil {
+		t.Fatalf("Error creating article: %v", err)
+	}
+	if art.URL != server.URL+"/en/storm" || len(fetched) != 1 {
+		t.Errorf("Expected the page already in English to be kept, got %s after %v", art.URL, fetched)
+	}
+}
@@ -0,0 +1,174 @@
+package newspaper4k
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tguidoux/newspaper4k-go/internal/warc"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+// ProcessDirectory runs the full parse/NLP pipeline over saved pages without any network access.
+// It walks dir for *.html/*.htm files and *.warc/*.warc.gz archives (response records only)
+// and calls fn with every parsed article. The original URL of an HTML file is read from an
+// adjacent sidecar (page.html.url, page.url or page.json with a "url" field).
+// Errors for individual files are collected and returned together; only context
+// cancellation stops the walk.
+func ProcessDirectory(ctx context.Context, dir string, cfg *configuration.Configuration, fn func(*newspaper.Article) error) error {
+	return ProcessDirectoryWithURLMapper(ctx, dir, cfg, nil, fn)
+}
+
+// ProcessDirectoryWithURLMapper is like ProcessDirectory but calls mapURL to infer the original
+// URL of HTML files that have no sidecar. mapURL receives the file path and may return "".
+func ProcessDirectoryWithURLMapper(ctx context.Context, dir string, cfg *configuration.Configuration, mapURL func(path string) string, fn func(*newspaper.Article) error) error {
+	var errs []error
+
+	walkErr := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		name := strings.ToLower(d.Name())
+		switch {
+		case strings.HasSuffix(name, ".html"), strings.HasSuffix(name, ".htm"):
+			articleURL := sidecarURL(path)
+			if articleURL == "" && mapURL != nil {
+				articleURL = mapURL(path)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				return nil
+			}
+			if err := processOffline(string(content), articleURL, cfg, fn); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			}
+		case strings.HasSuffix(name, ".warc"), strings.HasSuffix(name, ".warc.gz"):
+			errs = append(errs, processWARC(ctx, path, cfg, fn)...)
+		}
+		return nil
+	})
+	if walkErr != nil {
+		errs = append(errs, walkErr)
+	}
+
+	return errors.Join(errs...)
+}
+
+// processWARC runs the pipeline over the successful HTML response records of
+// a WARC archive. Redirects and error pages are skipped, as are the records
+// too large to be read, which are reported.
+func processWARC(ctx context.Context, path string, cfg *configuration.Configuration, fn func(*newspaper.Article) error) []error {
+	var errs []error
+
+	f, err := os.Open(path)
+	if err != nil {
+		return []error{fmt.Errorf("%s: %w", path, err)}
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	reader, err := warc.NewReader(f)
+	if err != nil {
+		return []error{fmt.Errorf("%s: %w", path, err)}
+	}
+
+	for ctx.Err() == nil {
+		record, err := reader.Next()
+		if errors.Is(err, warc.ErrRecordTooLarge) {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			}
+			break
+		}
+		if record.StatusCode < 200 || record.StatusCode >= 300 {
+			continue
+		}
+		if ct := strings.ToLower(record.ContentType); ct != "" && !strings.Contains(ct, "html") {
+			continue
+		}
+		if err := processOffline(string(record.Body), record.TargetURI, cfg, fn); err != nil {
+			errs = append(errs, fmt.Errorf("%s (%s): %w", path, record.TargetURI, err))
+		}
+	}
+
+	return errs
+}
+
+// processOffline builds an article from already fetched HTML and hands it to fn
+func processOffline(html, articleURL string, cfg *configuration.Configuration, fn func(*newspaper.Article) error) error {
+	// An empty input would make Download fall back to fetching the URL
+	if strings.TrimSpace(html) == "" {
+		return fmt.Errorf("empty HTML content")
+	}
+
+	req := NewDefaultParseRequest(articleURL)
+	req.InputHTML = html
+	art, err := NewArticleFromRequest(req)
+	if err != nil {
+		return err
+	}
+	if cfg != nil {
+		config := *cfg
+		config.DownloadOptions.InputHTML = html
+		art.Config = &config
+	}
+
+	if err := art.Build(DefaultExtractors(art.Config)); err != nil {
+		return err
+	}
+	return fn(art)
+}
+
+// sidecarURL reads the original URL of a saved page from an adjacent .url or .json file
+func sidecarURL(path string) string {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+
+	for _, candidate := range []string{path + ".url", base + ".url"} {
+		content, err := os.ReadFile(candidate)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			// Windows internet shortcuts store the URL as "URL=..."
+			if value, ok := strings.CutPrefix(line, "URL="); ok {
+				return strings.TrimSpace(value)
+			}
+			if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
+				return line
+			}
+		}
+	}
+
+	if content, err := os.ReadFile(base + ".json"); err == nil {
+		var meta struct {
+			URL string `json:"url"`
+		}
+		if json.Unmarshal(content, &meta) == nil {
+			return meta.URL
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,95 @@
+package newspaper4k
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+// failingTransport fails and counts every request it receives
+type failingTransport struct {
+	calls int
+}
+
+func (ft *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ft.calls++
+	return nil, errors.New("network access is disabled in tests")
+}
+
+func offlinePage(title string) string {
+	return fmt.Sprintf(`<html><head><title>%s</title></head><body><article>
+<p>The regional council said on Monday that the new budget would focus on schools and public transport in the coming years.</p>
+<p>Opposition members criticised the plan and said that it did not do enough for the rural areas of the region.</p>
+</article></body></html>`, title)
+}
+
+func TestProcessDirectory(t *testing.T) {
+	transport := &failingTransport{}
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = transport
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+
+	writeFile("budget.html", offlinePage("Council presents budget"))
+	writeFile("budget.html.url", "[InternetShortcut]\nURL=https://news.example.com/politics/budget\n")
+	writeFile("schools.htm", offlinePage("Schools get more funding"))
+
+	httpResponse := "HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=utf-8\r\n\r\n" + offlinePage("Transport plan unveiled")
+	httpRequest := "GET /transport HTTP/1.1\r\nHost: news.example.com\r\n\r\n"
+	// Error pages and redirects are not articles
+	notFound := "HTTP/1.1 404 Not Found\r\nContent-Type: text/html\r\n\r\n" + offlinePage("Page not found")
+	moved := "HTTP/1.1 301 Moved Permanently\r\nLocation: https://news.example.com/transport\r\nContent-Type: text/html\r\n\r\n" + offlinePage("Moved")
+	response := func(uri, block string) string {
+		return fmt.Sprintf("WARC/1.0\r\nWARC-Type: response\r\nWARC-Target-URI: %s\r\nContent-Type: application/http; msgtype=response\r\nContent-Length: %d\r\n\r\n%s\r\n\r\n", uri, len(block), block)
+	}
+	writeFile("crawl.warc",
+		fmt.Sprintf("WARC/1.0\r\nWARC-Type: request\r\nWARC-Target-URI: https://news.example.com/transport\r\nContent-Type: application/http; msgtype=request\r\nContent-Length: %d\r\n\r\n%s\r\n\r\n", len(httpRequest), httpRequest)+
+			response("https://news.example.com/transport", httpResponse)+
+			response("https://news.example.com/missing", notFound)+
+			response("https://news.example.com/old-transport", moved))
+	writeFile("empty.html", "")
+
+	articles := map[string]*newspaper.Article{}
+	err := ProcessDirectory(context.Background(), dir, configuration.NewConfiguration(), func(a *newspaper.Article) error {
+		articles[a.Title] = a
+		return nil
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "empty.html") {
+		t.Errorf("Expected the empty file error to be collected, got %v", err)
+	}
+	if len(articles) != 3 {
+		t.Fatalf("Expected 3 parsed articles, got %d", len(articles))
+	}
+	if transport.calls != 0 {
+		t.Errorf("Expected no network calls, got %d", transport.calls)
+	}
+
+	budget := articles["Council presents budget"]
+	if budget == nil || budget.URL != "https://news.example.com/politics/budget" {
+		t.Error("Expected the budget article URL to come from the sidecar")
+	}
+	transportArticle := articles["Transport plan unveiled"]
+	if transportArticle == nil || transportArticle.URL != "https://news.example.com/transport" {
+		t.Error("Expected the transport article URL to come from the WARC record")
+	}
+	for title, a := range articles {
+		if !strings.Contains(a.Text, "regional council") {
+			t.Errorf("%s: expected parsed text, got %q", title, a.Text)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package newspaper4k
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+	"github.com/tguidoux/newspaper4k-go/pkg/source"
+)
+
+// defaultPipelineQueueSize is the bound of the queues between the stages of
+// a Pipeline when QueueSize is not called
+const defaultPipelineQueueSize = 16
+
+// Pipeline chains the discovery of the articles of sources, their building
+// by a pool of workers, filters and a sink. The stages are connected by
+// queues of QueueSize articles, so that a slow sink throttles the downloads
+// and the discovery instead of buffering articles: at most workers+QueueSize
+// built articles wait for the sink at any time.
+//
+// A pipeline is configured with its builder methods then started with Run:
+//
+//	errs := NewPipeline().
+//		Discover(src).
+//		Build(8, DefaultExtractors).
+//		Filter(func(a *newspaper.Article) bool { return a.IsValidBody() }).
+//		Sink(store).
+//		Run(ctx)
+//	for err := range errs {
+//		log.Println(err)
+//	}
+type Pipeline struct {
+	queueSize  int
+	sources    []source.Source
+	workers    int
+	extractors func(config *configuration.Configuration) []newspaper.Extractor
+	filters    []func(*newspaper.Article) bool
+	sink       func(*newspaper.Article) error
+}
+
+// NewPipeline creates a Pipeline with one worker and the default extractors
+func NewPipeline() *Pipeline {
+	return &Pipeline{
+		queueSize:  defaultPipelineQueueSize,
+		workers:    1,
+		extractors: DefaultExtractors,
+	}
+}
+
+// QueueSize sets the number of articles each queue between two stages holds
+func (p *Pipeline) QueueSize(n int) *Pipeline {
+	p.queueSize = max(n, 0)
+	return p
+}
+
+// Discover adds sources whose articles are built, in order. Every source is
+// built, with BuildWithContext when it has one so that the cancellation of
+// the pipeline stops the discovery, then its articles are queued as returned
+// by GetArticles.
+func (p *Pipeline) Discover(sources ...source.Source) *Pipeline {
+	p.sources = append(p.sources, sources...)
+	return p
+}
+
+// Build sets the number of workers downloading, parsing and running NLP on
+// the articles, and the extractors they use. The extractors are created for
+// every article, since they keep state while parsing, e.g. by passing
+// DefaultExtractors. The downloads are canceled with the pipeline.
+func (p *Pipeline) Build(workers int, extractors func(config *configuration.Configuration) []newspaper.Extractor) *Pipeline {
+	p.workers = max(workers, 1)
+	if extractors != nil {
+		p.extractors = extractors
+	}
+	return p
+}
+
+// Filter adds a predicate built articles must satisfy to reach the sink
+func (p *Pipeline) Filter(keep func(*newspaper.Article) bool) *Pipeline {
+	p.filters = append(p.filters, keep)
+	return p
+}
+
+// Sink sets the function called with every built article that passed the
+// filters, from a single goroutine. Its errors are reported and do not stop
+// the pipeline.
+func (p *Pipeline) Sink(sink func(*newspaper.Article) error) *Pipeline {
+	p.sink = sink
+	return p
+}
+
+// Run starts the pipeline, stopped by the cancellation of ctx, and returns the
+// channel its errors are sent to: sources failing to build, articles failing
+// to build and sink errors. The channel must be drained; it is closed once
+// every stage has stopped, after all the articles went through or after ctx
+// was canceled and the runs in progress finished.
+func (p *Pipeline) Run(ctx context.Context) <-chan error {
+	errs := make(chan error, p.queueSize)
+	discovered := make(chan *newspaper.Article, p.queueSize)
+	built := make(chan *newspaper.Article, p.queueSize)
+
+	report := func(err error) {
+		select {
+		case errs <- err:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(discovered)
+		p.discover(ctx, discovered, report)
+	}()
+
+	var workers sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			p.build(ctx, discovered, built, report)
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(built)
+	}()
+
+	go func() {
+		defer close(errs)
+		p.drain(ctx, built, report)
+	}()
+
+	return errs
+}
+
+// contextBuilder is implemented by the sources whose build can be canceled,
+// such as source.DefaultSource and source.AsyncSource
+type contextBuilder interface {
+	BuildWithContext(ctx context.Context) error
+}
+
+// discover builds the sources and queues their articles
+func (p *Pipeline) discover(ctx context.Context, out chan<- *newspaper.Article, report func(error)) {
+	for _, src := range p.sources {
+		if ctx.Err() != nil {
+			return
+		}
+		build := src.Build
+		if cb, ok := src.(contextBuilder); ok {
+			build = func() error { return cb.BuildWithContext(ctx) }
+		}
+		if err := build(); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			report(fmt.Errorf("error building source %s: %w", sourceURL(src), err))
+			continue
+		}
+		articles := src.GetArticles()
+		for i := range articles {
+			select {
+			case out <- &articles[i]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// build builds the discovered articles and queues the ones passing the filters
+func (p *Pipeline) build(ctx context.Context, in <-chan *newspaper.Article, out chan<- *newspaper.Article, report func(error)) {
+	for a := range in {
+		if ctx.Err() != nil {
+			continue
+		}
+		if a.Config == nil {
+			a.Config = configuration.NewConfiguration()
+		}
+		if err := a.BuildWithContext(ctx, p.extractors(a.Config)); err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			report(fmt.Errorf("%s: %w", a.URL, err))
+			continue
+		}
+		if !p.keep(a) {
+			continue
+		}
+		select {
+		case out <- a:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// drain passes the built articles to the sink until ctx is canceled
+func (p *Pipeline) drain(ctx context.Context, in <-chan *newspaper.Article, report func(error)) {
+	for a := range in {
+		if ctx.Err() != nil || p.sink == nil {
+			continue
+		}
+		if err := p.sink(a); err != nil {
+			report(fmt.Errorf("%s: %w", a.URL, err))
+		}
+	}
+}
+
+// keep reports whether a satisfies every filter
+func (p *Pipeline) keep(a *newspaper.Article) bool {
+	for _, keep := range p.filters {
+		if !keep(a) {
+			return false
+		}
+	}
+	return true
+}
+
+// sourceURL returns the URL of src for error messages
+func sourceURL(src source.Source) string {
+	if u := src.ParsedURL(); u != nil {
+		return u.String()
+	}
+	return ""
+}
@@ -0,0 +1,240 @@
+package newspaper4k
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+	"github.com/tguidoux/newspaper4k-go/pkg/source"
+)
+
+// listSource is a source whose articles are a fixed list of URLs
+type listSource struct {
+	*source.DefaultSource
+	urls []string
+}
+
+func (ls *listSource) Build() error { return nil }
+
+func (ls *listSource) BuildWithContext(ctx context.Context) error { return ctx.Err() }
+
+func (ls *listSource) GetArticles() []newspaper.Article {
+	var articles []newspaper.Article
+	for _, u := range ls.urls {
+		config := configuration.NewConfiguration()
+		articles = append(articles, newspaper.Article{URL: u, SourceURL: ls.URL, Config: config, DownloadState: newspaper.NotStarted})
+	}
+	return articles
+}
+
+// pipelineSite serves numbered articles and tracks how many of them were
+// downloaded and not yet handed to the sink
+type pipelineSite struct {
+	server *httptest.Server
+	src    *listSource
+
+	mu             sync.Mutex
+	downloaded     int
+	sunk           int
+	maxOutstanding int
+}
+
+func newPipelineSite(t *testing.T, count int) *pipelineSite {
+	t.Helper()
+	ps := &pipelineSite{}
+	ps.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ps.mu.Lock()
+		ps.downloaded++
+		ps.maxOutstanding = max(ps.maxOutstanding, ps.downloaded-ps.sunk)
+		ps.mu.Unlock()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, offlinePage("Story "+strings.TrimPrefix(r.URL.Path, "/story/")))
+	}))
+	t.Cleanup(ps.server.Close)
+
+	ds, err := source.NewDefaultSource(source.SourceRequest{URL: ps.server.URL, Config: *configuration.NewConfiguration()})
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+	ps.src = &listSource{DefaultSource: ds}
+	for i := range count {
+		ps.src.urls = append(ps.src.urls, fmt.Sprintf("%s/story/%d", ps.server.URL, i))
+	}
+	return ps
+}
+
+// sinkEntered records that an article reached the sink
+func (ps *pipelineSite) sinkEntered() int {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.sunk++
+	return ps.sunk
+}
+
+func TestPipelineBackpressure(t *testing.T) {
+	const workers, queueSize, count = 3, 2, 12
+	site := newPipelineSite(t, count)
+
+	var titles []string
+	errs := NewPipeline().
+		QueueSize(queueSize).
+		Discover(site.src).
+		Build(workers, DefaultExtractors).
+		Filter(func(a *newspaper.Article) bool { return a.Title != "Story 7" }).
+		Sink(func(a *newspaper.Article) error {
+			site.sinkEntered()
+			time.Sleep(5 * time.Millisecond)
+			titles = append(titles, a.Title)
+			if a.Title == "Story 3" {
+				return fmt.Errorf("storage is full")
+			}
+			return nil
+		}).
+		Run(context.Background())
+
+	var reported []error
+	for err := range errs {
+		reported = append(reported, err)
+	}
+
+	if len(titles) != count-1 {
+		t.Errorf("Expected %d articles in the sink, got %d", count-1, len(titles))
+	}
+	if site.maxOutstanding > workers+queueSize {
+		t.Errorf("Expected at most %d articles downloaded ahead of the sink, got %d", workers+queueSize, site.maxOutstanding)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "/story/3: storage is full") {
+		t.Errorf("Expected the sink error, got %v", reported)
+	}
+}
+
+func TestPipelineCancel(t *testing.T) {
+	const count = 50
+	site := newPipelineSite(t, count)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := NewPipeline().
+		QueueSize(2).
+		Discover(site.src).
+		Build(2, nil).
+		Sink(func(a *newspaper.Article) error {
+			if site.sinkEntered() == 3 {
+				cancel()
+			}
+			return nil
+		}).
+		Run(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		for range errs {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the pipeline to stop after cancellation")
+	}
+
+	site.mu.Lock()
+	defer site.mu.Unlock()
+	if site.sunk != 3 {
+		t.Errorf("Expected no article in the sink after cancellation, got %d", site.sunk)
+	}
+	if site.downloaded >= count {
+		t.Errorf("Expected the downloads to stop after cancellation, got %d", site.downloaded)
+	}
+}
+
+// slowSource is a source whose build only ends with the cancellation of its context
+type slowSource struct {
+	*listSource
+	started chan struct{}
+}
+
+func (ss *slowSource) BuildWithContext(ctx context.Context) error {
+	close(ss.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestPipelineCancelDiscovery(t *testing.T) {
+	site := newPipelineSite(t, 1)
+	src := &slowSource{listSource: site.src, started: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := NewPipeline().Discover(src, site.src).Run(ctx)
+
+	<-src.started
+	cancel()
+	done := make(chan []error)
+	go func() {
+		var reported []error
+		for err := range errs {
+			reported = append(reported, err)
+		}
+		done <- reported
+	}()
+	select {
+	case reported := <-done:
+		if len(reported) != 0 {
+			t.Errorf("Expected no error for the canceled discovery, got %v", reported)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the cancellation to stop the discovery")
+	}
+
+	site.mu.Lock()
+	defer site.mu.Unlock()
+	if site.downloaded != 0 {
+		t.Errorf("Expected the following sources not to be discovered, got %d downloads", site.downloaded)
+	}
+}
+
+func TestPipelineCancelDownload(t *testing.T) {
+	started := make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { close(started) })
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	ds, err := source.NewDefaultSource(source.SourceRequest{URL: server.URL, Config: *configuration.NewConfiguration()})
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+	src := &listSource{DefaultSource: ds, urls: []string{server.URL + "/story/0"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := NewPipeline().Discover(src).Run(ctx)
+
+	<-started
+	cancel()
+	done := make(chan []error)
+	go func() {
+		var reported []error
+		for err := range errs {
+			reported = append(reported, err)
+		}
+		done <- reported
+	}()
+	select {
+	case reported := <-done:
+		if len(reported) != 0 {
+			t.Errorf("Expected no error for the canceled download, got %v", reported)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the cancellation to stop the download")
+	}
+}
@@ -0,0 +1,125 @@
+package newspaper4k
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+// BatchStats aggregates the extractor runs of a BuildArticles batch, to find
+// the extractors failing most often on a source
+type BatchStats struct {
+	// Extractors holds the stats of each extractor, keyed by its lowercased
+	// type name without the Extractor suffix, e.g. "authors"
+	Extractors map[string]*ExtractorStats `json:"extractors"`
+}
+
+// ExtractorStats are the outcomes and durations of the runs of an extractor.
+// A run fails when the extractor returns an error or adds diagnostics of
+// DiagnosticError severity to the article, and is empty when it produces
+// nothing, e.g. no author for the authors extractor. Extractors without a
+// known result are never empty.
+type ExtractorStats struct {
+	Runs   int           `json:"runs"`
+	Errors int           `json:"errors"`
+	Empty  int           `json:"empty"`
+	P50    time.Duration `json:"p50_ns"`
+	P90    time.Duration `json:"p90_ns"`
+	P99    time.Duration `json:"p99_ns"`
+
+	durations []time.Duration
+}
+
+// ErrorRate returns the share of failed runs, between 0 and 1
+func (es *ExtractorStats) ErrorRate() float64 {
+	if es.Runs == 0 {
+		return 0
+	}
+	return float64(es.Errors) / float64(es.Runs)
+}
+
+// extractorResults tell whether an extractor produced nothing, by extractor name
+var extractorResults = map[string]func(a *newspaper.Article) bool{
+	"metadata":      func(a *newspaper.Article) bool { return len(a.MetaData) == 0 && a.MetaDescription == "" },
+	"language":      func(a *newspaper.Article) bool { return a.MetaLang == "" },
+	"title":         func(a *newspaper.Article) bool { return a.Title == "" },
+	"authors":       func(a *newspaper.Article) bool { return len(a.Authors) == 0 },
+	"pubdate":       func(a *newspaper.Article) bool { return a.PublishDate == nil },
+	"updatehistory": func(a *newspaper.Article) bool { return len(a.UpdateHistory) == 0 },
+	"body":          func(a *newspaper.Article) bool { return a.Text == "" },
+	"table":         func(a *newspaper.Article) bool { return len(a.Tables) == 0 },
+	"category":      func(a *newspaper.Article) bool { return len(a.Categories) == 0 },
+	"image":         func(a *newspaper.Article) bool { return a.TopImage == "" && len(a.Images) == 0 },
+	"video":         func(a *newspaper.Article) bool { return len(a.Movies) == 0 && len(a.Videos) == 0 },
+	"audio":         func(a *newspaper.Article) bool { return len(a.Audio) == 0 },
+}
+
+// extractorName returns the stats key of ext
+func extractorName(ext newspaper.Extractor) string {
+	name := fmt.Sprintf("%T", ext)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(strings.TrimSuffix(name, "Extractor"))
+}
+
+// observer returns the ExtractorObserver recording the runs on a
+func (bs *BatchStats) observer(a *newspaper.Article) newspaper.ExtractorObserver {
+	diagnostics := errorDiagnostics(a)
+	return func(ext newspaper.Extractor, elapsed time.Duration, err error) {
+		name := extractorName(ext)
+		if bs.Extractors == nil {
+			bs.Extractors = make(map[string]*ExtractorStats)
+		}
+		stats, ok := bs.Extractors[name]
+		if !ok {
+			stats = &ExtractorStats{}
+			bs.Extractors[name] = stats
+		}
+
+		stats.Runs++
+		stats.durations = append(stats.durations, elapsed)
+		if err != nil || errorDiagnostics(a) > diagnostics {
+			stats.Errors++
+		}
+		if empty, ok := extractorResults[name]; ok && err == nil && empty(a) {
+			stats.Empty++
+		}
+		diagnostics = errorDiagnostics(a)
+	}
+}
+
+// errorDiagnostics counts the error diagnostics of a, the notices are not
+// failures of the extractors
+func errorDiagnostics(a *newspaper.Article) int {
+	count := 0
+	for _, diagnostic := range a.Diagnostics {
+		if diagnostic.Severity == newspaper.DiagnosticError {
+			count++
+		}
+	}
+	return count
+}
+
+// computePercentiles sets the duration percentiles of every extractor
+func (bs *BatchStats) computePercentiles() {
+	for _, stats := range bs.Extractors {
+		sorted := slices.Clone(stats.durations)
+		slices.Sort(sorted)
+		stats.P50 = percentile(sorted, 50)
+		stats.P90 = percentile(sorted, 90)
+		stats.P99 = percentile(sorted, 99)
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
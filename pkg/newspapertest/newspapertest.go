@@ -0,0 +1,277 @@
+// Package newspapertest fakes news sites for the tests of code built on
+// newspaper4k: a homepage linking to category pages, an RSS feed and article
+// pages, served by an httptest.Server, along with the values expected once the
+// articles are extracted.
+package newspapertest
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+// DefaultDate is the publication date of the articles without Date
+var DefaultDate = time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+// DefaultArticleTemplate renders the article pages. It is executed with an
+// ExpectedArticle.
+const DefaultArticleTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<title>{{.Title}}</title>
+<meta property="og:title" content="{{.Title}}">
+<meta property="article:published_time" content="{{.PublishDate.Format "2006-01-02T15:04:05Z07:00"}}">
+{{range .Authors}}<meta name="author" content="{{.}}">
+{{end}}</head>
+<body>
+<nav><a href="/">Home</a></nav>
+<article>
+<h1>{{.Title}}</h1>
+{{range .Paragraphs}}<p>{{.}}</p>
+{{end}}</article>
+<footer>All rights reserved</footer>
+</body>
+</html>`
+
+// ArticleSpec describes an article of a fake site
+type ArticleSpec struct {
+	Title string
+	// Date is the publication date, truncated to the day since it also
+	// appears in the article URL. DefaultDate is used when zero.
+	Date    time.Time
+	Authors []string
+	// Paragraphs is the body of the article, two paragraphs mentioning the
+	// title are generated when empty
+	Paragraphs []string
+}
+
+// CategorySpec describes a category page linked from the homepage
+type CategorySpec struct {
+	Name     string
+	Articles []ArticleSpec
+}
+
+// SiteSpec describes a fake news site
+type SiteSpec struct {
+	Name        string
+	Description string
+	Categories  []CategorySpec
+	// FeedItems are the articles listed in the RSS feed served at /feed
+	FeedItems []ArticleSpec
+	// ArticleTemplate overrides DefaultArticleTemplate
+	ArticleTemplate string
+}
+
+// ExpectedArticle holds the values of a fake article, for assertions
+type ExpectedArticle struct {
+	URL         string
+	Title       string
+	PublishDate time.Time
+	Authors     []string
+	Paragraphs  []string
+	// Category is the name of the category linking to the article, empty for feed items
+	Category string
+}
+
+// FakeSite is a fake news site served by an httptest.Server
+type FakeSite struct {
+	Server *httptest.Server
+	// URL is the homepage of the site
+	URL string
+	// FeedURL is the URL of the RSS feed, also linked from the category pages
+	FeedURL string
+	// CategoryURLs are the URLs of the category pages, in the order of the spec
+	CategoryURLs []string
+
+	spec     SiteSpec
+	template *template.Template
+
+	mu       sync.Mutex
+	articles []ExpectedArticle
+}
+
+// NewFakeSite starts a fake news site for spec, closed when the test ends
+func NewFakeSite(t testing.TB, spec SiteSpec) *FakeSite {
+	t.Helper()
+	articleTemplate := spec.ArticleTemplate
+	if articleTemplate == "" {
+		articleTemplate = DefaultArticleTemplate
+	}
+	tmpl, err := template.New("article").Parse(articleTemplate)
+	if err != nil {
+		t.Fatalf("Error parsing the article template: %v", err)
+	}
+
+	fs := &FakeSite{spec: spec, template: tmpl}
+	fs.Server = httptest.NewServer(http.HandlerFunc(fs.serveHTTP))
+	t.Cleanup(fs.Server.Close)
+
+	fs.URL = fs.Server.URL
+	fs.FeedURL = fs.URL + "/feed"
+	for _, category := range spec.Categories {
+		fs.CategoryURLs = append(fs.CategoryURLs, fs.URL+"/"+slugify(category.Name))
+		for _, article := range category.Articles {
+			fs.AddArticle(category.Name, article)
+		}
+	}
+	for _, article := range spec.FeedItems {
+		fs.AddArticle("", article)
+	}
+	return fs
+}
+
+// AddArticle publishes an article in the named category, or in the feed when
+// category is empty, and returns its expected values
+func (fs *FakeSite) AddArticle(category string, spec ArticleSpec) ExpectedArticle {
+	date := spec.Date
+	if date.IsZero() {
+		date = DefaultDate
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	paragraphs := spec.Paragraphs
+	if len(paragraphs) == 0 {
+		paragraphs = []string{
+			fmt.Sprintf("%s, the newsroom reported on %s after following the events closely throughout the day.", spec.Title, date.Format("January 2")),
+			"Officials said that more details would be shared in the coming days and asked residents to follow the updates.",
+		}
+	}
+
+	expected := ExpectedArticle{
+		URL:         fmt.Sprintf("%s/%s/%s.html", fs.URL, date.Format("2006/01/02"), slugify(spec.Title)),
+		Title:       spec.Title,
+		PublishDate: date,
+		Authors:     slices.Clone(spec.Authors),
+		Paragraphs:  slices.Clone(paragraphs),
+		Category:    category,
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.articles = append(fs.articles, expected)
+	return expected
+}
+
+// Articles returns the expected values of every article of the site
+func (fs *FakeSite) Articles() []ExpectedArticle {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return slices.Clone(fs.articles)
+}
+
+// Article returns the expected values of the article at articleURL
+func (fs *FakeSite) Article(articleURL string) (ExpectedArticle, bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	for _, article := range fs.articles {
+		if article.URL == articleURL {
+			return article, true
+		}
+	}
+	return ExpectedArticle{}, false
+}
+
+// serveHTTP serves the homepage, the category pages, the feed and the articles
+func (fs *FakeSite) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	fs.mu.Lock()
+	articles := slices.Clone(fs.articles)
+	fs.mu.Unlock()
+
+	var b strings.Builder
+	switch r.URL.Path {
+	case "/":
+		fmt.Fprintf(&b, `<html><head><title>%s</title><meta name="description" content="%s"></head><body>`,
+			template.HTMLEscapeString(fs.spec.Name), template.HTMLEscapeString(fs.spec.Description))
+		for i, category := range fs.spec.Categories {
+			fmt.Fprintf(&b, `<a href="%s">%s</a>`, fs.CategoryURLs[i], template.HTMLEscapeString(category.Name))
+		}
+		b.WriteString("</body></html>")
+	case "/feed":
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"><channel><title>%s</title>`, template.HTMLEscapeString(fs.spec.Name))
+		for _, article := range articles {
+			if article.Category == "" {
+				fmt.Fprintf(&b, "<item><title>%s</title><guid>%s</guid><pubDate>%s</pubDate></item>",
+					template.HTMLEscapeString(article.Title), article.URL, article.PublishDate.Format(time.RFC1123Z))
+			}
+		}
+		b.WriteString("</channel></rss>")
+	default:
+		if i := slices.Index(fs.CategoryURLs, fs.URL+r.URL.Path); i >= 0 {
+			category := fs.spec.Categories[i].Name
+			fmt.Fprintf(&b, `<html><head><title>%s</title><link rel="alternate" type="application/rss+xml" href="/feed"></head><body><h1>%s</h1>`,
+				template.HTMLEscapeString(category), template.HTMLEscapeString(category))
+			for _, article := range articles {
+				if article.Category == category {
+					fmt.Fprintf(&b, `<a href="%s">%s</a>`, strings.TrimPrefix(article.URL, fs.URL), template.HTMLEscapeString(article.Title))
+				}
+			}
+			b.WriteString("</body></html>")
+			break
+		}
+		i := slices.IndexFunc(articles, func(a ExpectedArticle) bool { return a.URL == fs.URL+r.URL.Path })
+		if i < 0 {
+			http.NotFound(w, r)
+			return
+		}
+		var page bytes.Buffer
+		if err := fs.template.Execute(&page, articles[i]); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		b.Write(page.Bytes())
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+	fmt.Fprint(w, b.String())
+}
+
+// AssertArticle reports the differences between an extracted article and
+// the expected values: URL, title, authors, publication day and paragraphs,
+// which must all appear in the text
+func AssertArticle(t testing.TB, a *newspaper.Article, expected ExpectedArticle) {
+	t.Helper()
+	if a.URL != expected.URL {
+		t.Errorf("Expected URL %q, got %q", expected.URL, a.URL)
+	}
+	if a.Title != expected.Title {
+		t.Errorf("%s: expected title %q, got %q", expected.URL, expected.Title, a.Title)
+	}
+	if !slices.Equal(a.Authors, expected.Authors) && (len(a.Authors) != 0 || len(expected.Authors) != 0) {
+		t.Errorf("%s: expected authors %q, got %q", expected.URL, expected.Authors, a.Authors)
+	}
+	if a.PublishDate == nil || a.PublishDate.Format(time.DateOnly) != expected.PublishDate.Format(time.DateOnly) {
+		t.Errorf("%s: expected publication date %s, got %v", expected.URL, expected.PublishDate.Format(time.DateOnly), a.PublishDate)
+	}
+	for _, paragraph := range expected.Paragraphs {
+		if !strings.Contains(a.Text, paragraph) {
+			t.Errorf("%s: expected the text to contain %q, got %q", expected.URL, paragraph, a.Text)
+		}
+	}
+}
+
+// slugify turns a title into a URL path segment
+func slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,109 @@
+package newspapertest
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper4k"
+	"github.com/tguidoux/newspaper4k-go/pkg/source"
+)
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Error fetching %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Error reading %s: %v", url, err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestFakeSite(t *testing.T) {
+	site := NewFakeSite(t, SiteSpec{
+		Name:        "Local News",
+		Description: "News from the coast",
+		Categories: []CategorySpec{
+			{Name: "World", Articles: []ArticleSpec{
+				{Title: "Storm hits the coast", Date: time.Date(2025, 1, 6, 18, 30, 0, 0, time.UTC), Authors: []string{"Jane Doe"}},
+				{Title: "Council approves budget"},
+			}},
+			{Name: "Sports", Articles: []ArticleSpec{{Title: "Local team wins the cup"}}},
+		},
+		FeedItems: []ArticleSpec{
+			{Title: "New bridge opens to traffic", Date: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+			{Title: "School reopens after floods"},
+			{Title: "Harbour festival returns", Paragraphs: []string{"The harbour festival returns this summer with concerts, markets and boat races for the whole family."}},
+		},
+	})
+
+	if expected := []string{site.URL + "/world", site.URL + "/sports"}; !slices.Equal(site.CategoryURLs, expected) {
+		t.Errorf("Expected categories %v, got %v", expected, site.CategoryURLs)
+	}
+	if len(site.Articles()) != 6 {
+		t.Fatalf("Expected 6 articles, got %d", len(site.Articles()))
+	}
+	storm := site.Articles()[0]
+	if storm.URL != site.URL+"/2025/01/06/storm-hits-the-coast.html" || !storm.PublishDate.Equal(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected expected values %+v", storm)
+	}
+
+	status, feed := get(t, site.FeedURL)
+	if status != http.StatusOK || strings.Count(feed, "<item>") != 3 || !strings.Contains(feed, "/2025/01/05/new-bridge-opens-to-traffic.html") {
+		t.Errorf("Expected a feed with 3 items, got %d %s", status, feed)
+	}
+	if status, _ := get(t, site.URL+"/missing"); status != http.StatusNotFound {
+		t.Errorf("Expected a 404 for unknown pages, got %d", status)
+	}
+
+	src, err := source.NewDefaultSource(source.SourceRequest{URL: site.URL, Config: *configuration.NewConfiguration()})
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+	if err := src.Build(); err != nil {
+		t.Fatalf("Error building source: %v", err)
+	}
+	if src.Description() != "News from the coast" || len(src.Categories()) != 2 {
+		t.Errorf("Expected the description and 2 categories, got %q and %v", src.Description(), src.Categories())
+	}
+
+	articles := src.GetArticles()
+	if len(articles) != 6 {
+		t.Fatalf("Expected the source to find 6 articles, got %d", len(articles))
+	}
+	for _, a := range articles {
+		expected, ok := site.Article(a.URL)
+		if !ok {
+			t.Errorf("Unexpected article %s", a.URL)
+			continue
+		}
+		art, err := newspaper4k.NewArticleFromURL(a.URL)
+		if err != nil {
+			t.Fatalf("Error creating article: %v", err)
+		}
+		if err := art.Build(newspaper4k.DefaultExtractors(art.Config)); err != nil {
+			t.Fatalf("Error building %s: %v", a.URL, err)
+		}
+		AssertArticle(t, art, expected)
+	}
+}
+
+func TestFakeSiteArticleTemplate(t *testing.T) {
+	site := NewFakeSite(t, SiteSpec{
+		Categories:      []CategorySpec{{Name: "World", Articles: []ArticleSpec{{Title: "Storm hits the coast"}}}},
+		ArticleTemplate: `<html><head><title>{{.Title}} | Local News</title></head><body><div class="story">{{range .Paragraphs}}<p>{{.}}</p>{{end}}</div></body></html>`,
+	})
+
+	status, page := get(t, site.Articles()[0].URL)
+	if status != http.StatusOK || !strings.Contains(page, "<title>Storm hits the coast | Local News</title>") {
+		t.Errorf("Expected the custom template, got %d %s", status, page)
+	}
+}
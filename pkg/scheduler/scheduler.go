@@ -0,0 +1,446 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+	"github.com/tguidoux/newspaper4k-go/pkg/source"
+)
+
+// Source is a news source the scheduler can poll: it is built on its first
+// run and refreshed on the following ones
+type Source interface {
+	source.Source
+	Refresh() []newspaper.Article
+}
+
+// contextSource is implemented by the sources whose builds and refreshes can
+// be canceled, such as source.DefaultSource and source.AsyncSource. The
+// scheduler cancels their runs when it stops.
+type contextSource interface {
+	BuildWithParamsContext(ctx context.Context, params source.BuildParams) error
+	RefreshWithContext(ctx context.Context) ([]newspaper.Article, error)
+}
+
+// Clock abstracts time so that the scheduler can be driven by a fake clock in tests
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the subset of time.Timer used by the scheduler
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// realClock is the Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{t: time.NewTimer(d)} }
+
+// realTimer wraps a time.Timer
+type realTimer struct {
+	t *time.Timer
+}
+
+func (rt realTimer) C() <-chan time.Time { return rt.t.C }
+
+func (rt realTimer) Stop() bool { return rt.t.Stop() }
+
+// defaultMaxConcurrent is the number of sources polled at the same time when
+// Options.MaxConcurrent is not set
+const defaultMaxConcurrent = 4
+
+// Options configures a Scheduler
+type Options struct {
+	Clock             Clock         // Defaults to the system clock
+	Jitter            time.Duration // Random delay in [0, Jitter) added to every run, staggering the sources
+	MinDomainInterval time.Duration // Minimum time between the starts of two runs on the same domain
+	MaxConcurrent     int           // Maximum number of sources polled at the same time
+	OnResult          func(Result)  // Called after every run, from the scheduler goroutine
+}
+
+// Result is the outcome of one run of a source
+type Result struct {
+	Name     string
+	Articles []newspaper.Article // Articles found by the run: all of them on the first build, then only new ones
+	Err      error
+	Started  time.Time
+	Finished time.Time
+}
+
+// SourceStats are the runtime statistics of a registered source
+type SourceStats struct {
+	Name          string        `json:"name"`
+	URL           string        `json:"url"`
+	Domain        string        `json:"domain"`
+	Interval      time.Duration `json:"interval"`
+	FeedInterval  time.Duration `json:"feed_interval,omitempty"` // Interval suggested by the polling hints of the feeds, used instead of Interval
+	Runs          int           `json:"runs"`
+	Running       bool          `json:"running"`
+	LastRun       time.Time     `json:"last_run"`
+	LastSuccess   time.Time     `json:"last_success"`
+	LastError     string        `json:"last_error,omitempty"`
+	LastErrorAt   time.Time     `json:"last_error_at"`
+	ArticlesFound int           `json:"articles_found"`
+	NextRun       time.Time     `json:"next_run"`
+}
+
+// DomainStats are the politeness statistics of a domain shared by one or more sources
+type DomainStats struct {
+	Domain      string    `json:"domain"`
+	Requests    int       `json:"requests"`
+	LastRequest time.Time `json:"last_request"`
+	Delayed     int       `json:"delayed"` // Runs postponed to respect MinDomainInterval
+}
+
+// Stats is a snapshot of the scheduler state
+type Stats struct {
+	Sources []SourceStats `json:"sources"`
+	Domains []DomainStats `json:"domains"`
+}
+
+// job is a registered source and its schedule
+type job struct {
+	src    Source
+	params *source.BuildParams // Params of the first build, nil for the defaults of the source
+	built  bool
+	stats  SourceStats
+}
+
+// completion is sent by a finished run to the scheduler loop
+type completion struct {
+	job    *job
+	result Result
+}
+
+// Scheduler polls registered sources at their own interval, spacing the runs
+// on the same domain and staggering them with jitter
+type Scheduler struct {
+	opts    Options
+	clock   Clock
+	mu      sync.Mutex
+	jobs    map[string]*job
+	domains map[string]*DomainStats
+	running bool
+	wake    chan struct{}
+}
+
+// New creates a Scheduler
+func New(opts Options) *Scheduler {
+	clock := opts.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = defaultMaxConcurrent
+	}
+	return &Scheduler{
+		opts:    opts,
+		clock:   clock,
+		jobs:    make(map[string]*job),
+		domains: make(map[string]*DomainStats),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Add registers a source polled every interval. Its first run is delayed by
+// the jitter only. Sources can be added while the scheduler is running.
+func (s *Scheduler) Add(name string, src Source, interval time.Duration) error {
+	return s.add(name, src, interval, nil)
+}
+
+// AddWithParams is like Add with the BuildParams of the first build of the
+// source, reused by its refreshes. The source must support cancellation, as
+// source.DefaultSource and source.AsyncSource do.
+func (s *Scheduler) AddWithParams(name string, src Source, interval time.Duration, params source.BuildParams) error {
+	if _, ok := src.(contextSource); !ok {
+		return fmt.Errorf("source %q cannot be built with params", name)
+	}
+	return s.add(name, src, interval, &params)
+}
+
+// add registers a source, built with params when not nil
+func (s *Scheduler) add(name string, src Source, interval time.Duration, params *source.BuildParams) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval %v for source %q", interval, name)
+	}
+	if src == nil || src.ParsedURL() == nil {
+		return fmt.Errorf("source %q has no URL", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("source %q is already registered", name)
+	}
+
+	u := src.ParsedURL()
+	domain := u.Domain
+	if u.TLD != "" {
+		domain += "." + u.TLD
+	}
+	s.jobs[name] = &job{
+		src:    src,
+		params: params,
+		stats: SourceStats{
+			Name:     name,
+			URL:      u.String(),
+			Domain:   domain,
+			Interval: interval,
+			NextRun:  s.clock.Now().Add(s.jitter()),
+		},
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run polls the sources until ctx is done, then cancels the runs in progress,
+// waits for them to return and returns the context error. The runs of the
+// sources not supporting cancellation are waited for.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return errors.New("scheduler is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	done := make(chan completion)
+	inFlight := 0
+
+	for {
+		s.mu.Lock()
+		started, wait, hasNext := s.dispatch(ctx, done, s.opts.MaxConcurrent-inFlight)
+		s.mu.Unlock()
+		inFlight += started
+
+		var timer Timer
+		var timerC <-chan time.Time
+		if hasNext && inFlight < s.opts.MaxConcurrent {
+			timer = s.clock.NewTimer(wait)
+			timerC = timer.C()
+		}
+
+		select {
+		case <-timerC:
+		case c := <-done:
+			inFlight--
+			s.complete(c)
+		case <-s.wake:
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			for ; inFlight > 0; inFlight-- {
+				s.complete(<-done)
+			}
+			s.mu.Lock()
+			s.running = false
+			s.mu.Unlock()
+			return ctx.Err()
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// dispatch starts up to capacity due runs, canceled with ctx, and returns how
+// many were started and the wait until the next run. s.mu must be held.
+func (s *Scheduler) dispatch(ctx context.Context, done chan<- completion, capacity int) (int, time.Duration, bool) {
+	now := s.clock.Now()
+	started := 0
+
+	for _, j := range s.sortedJobs() {
+		if j.stats.Running || j.stats.NextRun.After(now) || started >= capacity {
+			continue
+		}
+
+		domain := s.domains[j.stats.Domain]
+		if domain == nil {
+			domain = &DomainStats{Domain: j.stats.Domain}
+			s.domains[j.stats.Domain] = domain
+		}
+		if !domain.LastRequest.IsZero() && now.Before(domain.LastRequest.Add(s.opts.MinDomainInterval)) {
+			j.stats.NextRun = domain.LastRequest.Add(s.opts.MinDomainInterval)
+			domain.Delayed++
+			continue
+		}
+
+		domain.Requests++
+		domain.LastRequest = now
+		j.stats.Running = true
+		j.stats.LastRun = now
+		started++
+		go s.run(ctx, j, j.stats.Name, j.built, now, done)
+	}
+
+	var next time.Time
+	for _, j := range s.jobs {
+		if !j.stats.Running && (next.IsZero() || j.stats.NextRun.Before(next)) {
+			next = j.stats.NextRun
+		}
+	}
+	if next.IsZero() {
+		return started, 0, false
+	}
+	return started, max(next.Sub(now), 0), true
+}
+
+// run builds or refreshes the source of j and reports the result on done
+func (s *Scheduler) run(ctx context.Context, j *job, name string, built bool, started time.Time, done chan<- completion) {
+	result := Result{Name: name, Started: started}
+	cs, cancelable := j.src.(contextSource)
+	switch {
+	case built && cancelable:
+		result.Articles, result.Err = cs.RefreshWithContext(ctx)
+	case built:
+		result.Articles = j.src.Refresh()
+	case cancelable:
+		params := source.DefaultBuildParams()
+		if j.params != nil {
+			params = *j.params
+		}
+		if result.Err = cs.BuildWithParamsContext(ctx, params); result.Err == nil {
+			result.Articles = j.src.GetArticles()
+		}
+	default:
+		if result.Err = j.src.Build(); result.Err == nil {
+			result.Articles = j.src.GetArticles()
+		}
+	}
+	result.Finished = s.clock.Now()
+	done <- completion{job: j, result: result}
+}
+
+// complete records the result of a run, schedules the next one and calls OnResult
+func (s *Scheduler) complete(c completion) {
+	feeds := c.job.src.Feeds()
+	s.mu.Lock()
+	j := c.job
+	j.stats.Running = false
+	j.stats.Runs++
+	if c.result.Err != nil {
+		j.stats.LastError = c.result.Err.Error()
+		j.stats.LastErrorAt = c.result.Finished
+	} else {
+		j.built = true
+		j.stats.LastSuccess = c.result.Finished
+		j.stats.ArticlesFound += len(c.result.Articles)
+	}
+	interval, hints := feedPolling(feeds, j.stats.Interval)
+	j.stats.FeedInterval = 0
+	if hints != nil {
+		j.stats.FeedInterval = interval
+	}
+	j.stats.NextRun = skipWindows(c.result.Started.Add(interval+s.jitter()), hints)
+	s.mu.Unlock()
+
+	if s.opts.OnResult != nil {
+		s.opts.OnResult(c.result)
+	}
+}
+
+// feedPolling returns the poll interval of a source from the polling hints
+// of its feeds, the shortest suggested interval so that the busiest feed is
+// not missed, and the hints it was computed from. Without hints, it returns
+// interval and nil.
+func feedPolling(feeds []newspaper.Feed, interval time.Duration) (time.Duration, []*newspaper.PollingHints) {
+	var hints []*newspaper.PollingHints
+	suggested := time.Duration(0)
+	for _, feed := range feeds {
+		if feed.PollingHints == nil {
+			continue
+		}
+		hints = append(hints, feed.PollingHints)
+		if i := feed.SuggestedPollInterval(interval); suggested == 0 || i < suggested {
+			suggested = i
+		}
+	}
+	if hints == nil {
+		return interval, nil
+	}
+	return suggested, hints
+}
+
+// maxSkippedHours bounds the postponement of a run by skipWindows: a week
+const maxSkippedHours = 7 * 24
+
+// skipWindows postpones next to the first hour that is not skipped by every
+// feed, see newspaper.PollingHints.Skips
+func skipWindows(next time.Time, hints []*newspaper.PollingHints) time.Time {
+	if hints == nil {
+		return next
+	}
+	skipped := func(t time.Time) bool {
+		for _, h := range hints {
+			if !h.Skips(t) {
+				return false
+			}
+		}
+		return true
+	}
+	for i := 0; i < maxSkippedHours && skipped(next); i++ {
+		next = next.Truncate(time.Hour).Add(time.Hour)
+	}
+	return next
+}
+
+// Stats returns a snapshot of the source and domain statistics, sorted by name
+func (s *Scheduler) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := Stats{
+		Sources: make([]SourceStats, 0, len(s.jobs)),
+		Domains: make([]DomainStats, 0, len(s.domains)),
+	}
+	for _, j := range s.sortedJobs() {
+		stats.Sources = append(stats.Sources, j.stats)
+	}
+	for _, d := range s.domains {
+		stats.Domains = append(stats.Domains, *d)
+	}
+	sort.Slice(stats.Domains, func(i, k int) bool {
+		return stats.Domains[i].Domain < stats.Domains[k].Domain
+	})
+	return stats
+}
+
+// sortedJobs returns the jobs ordered by name so that runs are dispatched
+// deterministically. s.mu must be held.
+func (s *Scheduler) sortedJobs() []*job {
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].stats.Name < jobs[k].stats.Name
+	})
+	return jobs
+}
+
+// jitter returns a random delay in [0, Jitter)
+func (s *Scheduler) jitter() time.Duration {
+	if s.opts.Jitter <= 0 {
+		return 0
+	}
+	return rand.N(s.opts.Jitter)
+}
@@ -0,0 +1,472 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+	"github.com/tguidoux/newspaper4k-go/pkg/source"
+)
+
+// fakeClock is a Clock whose time only moves with Advance
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	when  time.Time
+	ch    chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)}
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) NewTimer(d time.Duration) Timer {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	t := &fakeTimer{clock: fc, when: fc.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.ch <- fc.now
+	} else {
+		fc.timers = append(fc.timers, t)
+	}
+	return t
+}
+
+// Advance moves the clock forward and fires the expired timers
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.now = fc.now.Add(d)
+	active := fc.timers[:0]
+	for _, t := range fc.timers {
+		if t.when.After(fc.now) {
+			active = append(active, t)
+		} else {
+			t.ch <- fc.now
+		}
+	}
+	fc.timers = active
+}
+
+func (fc *fakeClock) activeTimers() int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return len(fc.timers)
+}
+
+func (ft *fakeTimer) C() <-chan time.Time { return ft.ch }
+
+func (ft *fakeTimer) Stop() bool {
+	fc := ft.clock
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	for i, t := range fc.timers {
+		if t == ft {
+			fc.timers = append(fc.timers[:i], fc.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fakeSource records the time of its runs instead of crawling
+type fakeSource struct {
+	*source.DefaultSource
+	clock    *fakeClock
+	mu       sync.Mutex
+	runs     []time.Time
+	failures int
+	block    chan struct{}
+	feeds    []newspaper.Feed
+	params   []source.BuildParams
+}
+
+func newFakeSource(t *testing.T, clock *fakeClock, sourceURL string) *fakeSource {
+	t.Helper()
+	ds, err := source.NewDefaultSource(source.SourceRequest{URL: sourceURL, Config: *configuration.NewConfiguration()})
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+	return &fakeSource{DefaultSource: ds, clock: clock}
+}
+
+func (fs *fakeSource) record(ctx context.Context) error {
+	if fs.block != nil {
+		select {
+		case <-fs.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.runs = append(fs.runs, fs.clock.Now())
+	return nil
+}
+
+func (fs *fakeSource) Build() error {
+	return fs.BuildWithParamsContext(context.Background(), source.DefaultBuildParams())
+}
+
+func (fs *fakeSource) BuildWithParamsContext(ctx context.Context, params source.BuildParams) error {
+	if err := fs.record(ctx); err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.params = append(fs.params, params)
+	if fs.failures > 0 {
+		fs.failures--
+		return errors.New("homepage unavailable")
+	}
+	return nil
+}
+
+func (fs *fakeSource) GetArticles() []newspaper.Article {
+	return []newspaper.Article{{URL: fs.URL + "/a"}, {URL: fs.URL + "/b"}}
+}
+
+func (fs *fakeSource) Refresh() []newspaper.Article {
+	articles, _ := fs.RefreshWithContext(context.Background())
+	return articles
+}
+
+func (fs *fakeSource) RefreshWithContext(ctx context.Context) ([]newspaper.Article, error) {
+	if err := fs.record(ctx); err != nil {
+		return nil, err
+	}
+	return []newspaper.Article{{URL: fs.URL + "/new"}}, nil
+}
+
+func (fs *fakeSource) Feeds() []newspaper.Feed {
+	return fs.feeds
+}
+
+func (fs *fakeSource) runTimes() []time.Time {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return append([]time.Time(nil), fs.runs...)
+}
+
+// waitIdle waits until the scheduler has no run in progress and waits for its next timer
+func waitIdle(t *testing.T, s *Scheduler, clock *fakeClock) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		busy := false
+		for _, st := range s.Stats().Sources {
+			busy = busy || st.Running
+		}
+		if !busy && clock.activeTimers() == 1 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("Scheduler did not become idle")
+}
+
+// runFor advances the clock by step until total has elapsed
+func runFor(t *testing.T, s *Scheduler, clock *fakeClock, total, step time.Duration) {
+	t.Helper()
+	for elapsed := time.Duration(0); elapsed < total; elapsed += step {
+		waitIdle(t, s, clock)
+		clock.Advance(step)
+	}
+	waitIdle(t, s, clock)
+}
+
+func TestSchedulerIntervalsAndJitter(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	const jitter = time.Minute
+	const step = 5 * time.Second
+
+	var mu sync.Mutex
+	var results []Result
+	s := New(Options{Clock: clock, Jitter: jitter, OnResult: func(r Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, r)
+	}})
+
+	fast := newFakeSource(t, clock, "https://fast.example.com")
+	slow := newFakeSource(t, clock, "https://slow-news.org")
+	slow.failures = 1
+	if err := s.Add("fast", fast, 10*time.Minute); err != nil {
+		t.Fatalf("Error adding source: %v", err)
+	}
+	if err := s.Add("slow", slow, 25*time.Minute); err != nil {
+		t.Fatalf("Error adding source: %v", err)
+	}
+	if err := s.Add("fast", fast, time.Minute); err == nil {
+		t.Error("Expected an error for a duplicate source name")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- s.Run(ctx) }()
+
+	runFor(t, s, clock, 2*time.Hour, step)
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Run to return context.Canceled, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		src      *fakeSource
+		interval time.Duration
+	}{{fast, 10 * time.Minute}, {slow, 25 * time.Minute}} {
+		runs := tc.src.runTimes()
+		if len(runs) < int(2*time.Hour/(tc.interval+jitter)) {
+			t.Fatalf("Expected about one run every %v, got %d runs", tc.interval, len(runs))
+		}
+		if first := runs[0].Sub(start); first >= jitter+step {
+			t.Errorf("Expected the first run within the jitter, got %v", first)
+		}
+		for i := 1; i < len(runs); i++ {
+			if gap := runs[i].Sub(runs[i-1]); gap < tc.interval || gap >= tc.interval+jitter+step {
+				t.Errorf("Expected runs %v apart plus jitter, got %v", tc.interval, gap)
+			}
+		}
+	}
+
+	stats := s.Stats()
+	if len(stats.Sources) != 2 || stats.Sources[0].Name != "fast" || stats.Sources[1].Name != "slow" {
+		t.Fatalf("Unexpected stats: %+v", stats.Sources)
+	}
+	slowStats := stats.Sources[1]
+	if slowStats.LastError == "" || slowStats.LastSuccess.IsZero() || !slowStats.LastSuccess.After(slowStats.LastErrorAt) {
+		t.Errorf("Expected a failed first build followed by successes, got %+v", slowStats)
+	}
+	// The first successful build finds two articles, every refresh one
+	if expected := 2 + len(slow.runTimes()) - 2; slowStats.ArticlesFound != expected {
+		t.Errorf("Expected %d articles found, got %d", expected, slowStats.ArticlesFound)
+	}
+	if !slowStats.NextRun.After(slowStats.LastRun) {
+		t.Errorf("Expected the next run after the last one, got %+v", slowStats)
+	}
+	if _, err := json.Marshal(stats); err != nil {
+		t.Errorf("Error marshaling stats: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(results) != len(fast.runTimes())+len(slow.runTimes()) {
+		t.Errorf("Expected one result per run, got %d", len(results))
+	}
+}
+
+func TestSchedulerDomainSpacing(t *testing.T) {
+	clock := newFakeClock()
+	s := New(Options{Clock: clock, MinDomainInterval: 5 * time.Minute})
+
+	world := newFakeSource(t, clock, "https://world.example.com")
+	sports := newFakeSource(t, clock, "https://sports.example.com")
+	if err := s.Add("sports", sports, 10*time.Minute); err != nil {
+		t.Fatalf("Error adding source: %v", err)
+	}
+	if err := s.Add("world", world, 10*time.Minute); err != nil {
+		t.Fatalf("Error adding source: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- s.Run(ctx) }()
+
+	runFor(t, s, clock, time.Hour, 30*time.Second)
+	cancel()
+	<-errc
+
+	var all []time.Time
+	all = append(all, sports.runTimes()...)
+	all = append(all, world.runTimes()...)
+	for i := range all {
+		for k := i + 1; k < len(all); k++ {
+			gap := all[i].Sub(all[k]).Abs()
+			if gap < 5*time.Minute {
+				t.Errorf("Expected runs on the same domain at least 5m apart, got %v", gap)
+			}
+		}
+	}
+
+	stats := s.Stats()
+	if len(stats.Domains) != 1 || stats.Domains[0].Domain != "example.com" {
+		t.Fatalf("Expected a single shared domain, got %+v", stats.Domains)
+	}
+	if d := stats.Domains[0]; d.Delayed == 0 || d.Requests != len(all) {
+		t.Errorf("Expected delayed runs and one request per run, got %+v", d)
+	}
+}
+
+func TestSchedulerFeedPollingHints(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	s := New(Options{Clock: clock})
+
+	busy := newFakeSource(t, clock, "https://busy.example.com")
+	busy.feeds = []newspaper.Feed{
+		{URL: "https://busy.example.com/rss", PollingHints: &newspaper.PollingHints{UpdatePeriod: "hourly", UpdateFrequency: 2, SkipHours: []int{9}}},
+		{URL: "https://busy.example.com/weekly.xml", PollingHints: &newspaper.PollingHints{TTL: 24 * time.Hour, SkipHours: []int{9}}},
+	}
+	quiet := newFakeSource(t, clock, "https://quiet.example.org")
+	quiet.feeds = []newspaper.Feed{{URL: "https://quiet.example.org/rss", PollingHints: &newspaper.PollingHints{TTL: 2 * time.Hour}}}
+	plain := newFakeSource(t, clock, "https://plain.example.net")
+	plain.feeds = []newspaper.Feed{{URL: "https://plain.example.net/rss"}}
+	for name, src := range map[string]*fakeSource{"busy": busy, "quiet": quiet, "plain": plain} {
+		if err := s.Add(name, src, time.Hour); err != nil {
+			t.Fatalf("Error adding source: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- s.Run(ctx) }()
+
+	runFor(t, s, clock, 3*time.Hour, time.Minute)
+	cancel()
+	<-errc
+
+	// The busy feed is polled every 30 minutes but not during its skipped 9 o'clock hour
+	for name, tc := range map[string]struct {
+		src      *fakeSource
+		expected []time.Duration
+	}{
+		"busy":  {busy, []time.Duration{0, 30 * time.Minute, 2 * time.Hour, 150 * time.Minute, 3 * time.Hour}},
+		"quiet": {quiet, []time.Duration{0, 2 * time.Hour}},
+		"plain": {plain, []time.Duration{0, time.Hour, 2 * time.Hour, 3 * time.Hour}},
+	} {
+		var offsets []time.Duration
+		for _, run := range tc.src.runTimes() {
+			offsets = append(offsets, run.Sub(start))
+		}
+		if !slices.Equal(offsets, tc.expected) {
+			t.Errorf("Expected %s runs at %v, got %v", name, tc.expected, offsets)
+		}
+	}
+
+	for _, st := range s.Stats().Sources {
+		expected := map[string]time.Duration{"busy": 30 * time.Minute, "quiet": 2 * time.Hour}[st.Name]
+		if st.FeedInterval != expected {
+			t.Errorf("Expected a feed interval of %v for %s, got %v", expected, st.Name, st.FeedInterval)
+		}
+	}
+}
+
+// plainSource hides the cancellation support of the source it wraps
+type plainSource struct {
+	Source
+}
+
+func TestSchedulerShutdown(t *testing.T) {
+	tests := []struct {
+		name       string
+		cancelable bool
+	}{
+		{name: "cancels the runs", cancelable: true},
+		{name: "waits for the runs without cancellation", cancelable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			var results []Result
+			s := New(Options{Clock: clock, OnResult: func(r Result) { results = append(results, r) }})
+
+			src := newFakeSource(t, clock, "https://news.example.com")
+			src.block = make(chan struct{})
+			var registered Source = src
+			if !tt.cancelable {
+				registered = plainSource{src}
+			}
+			if err := s.Add("news", registered, time.Hour); err != nil {
+				t.Fatalf("Error adding source: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errc := make(chan error, 1)
+			go func() { errc <- s.Run(ctx) }()
+
+			deadline := time.Now().Add(2 * time.Second)
+			for !s.Stats().Sources[0].Running {
+				if time.Now().After(deadline) {
+					t.Fatal("Source run did not start")
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			cancel()
+			if !tt.cancelable {
+				select {
+				case <-errc:
+					t.Fatal("Run returned before the run in progress finished")
+				case <-time.After(20 * time.Millisecond):
+				}
+				close(src.block)
+			}
+
+			select {
+			case err := <-errc:
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("Expected context.Canceled, got %v", err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Run did not return after the shutdown")
+			}
+			if len(results) != 1 || s.Stats().Sources[0].Running {
+				t.Fatalf("Expected the run in progress to complete, got %d results", len(results))
+			}
+			if canceled := errors.Is(results[0].Err, context.Canceled); canceled != tt.cancelable {
+				t.Errorf("Expected the run to be canceled: %v, got error %v", tt.cancelable, results[0].Err)
+			}
+		})
+	}
+}
+
+func TestSchedulerAddWithParams(t *testing.T) {
+	clock := newFakeClock()
+	s := New(Options{Clock: clock})
+
+	src := newFakeSource(t, clock, "https://news.example.com")
+	params := source.DefaultBuildParams()
+	params.StripAllQueryParams = true
+	params.LimitArticles = 10
+	if err := s.AddWithParams("news", src, time.Hour, params); err != nil {
+		t.Fatalf("Error adding source: %v", err)
+	}
+	if err := s.AddWithParams("plain", plainSource{newFakeSource(t, clock, "https://other.example.org")}, time.Hour, params); err == nil {
+		t.Error("Expected an error for a source without BuildWithParamsContext")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- s.Run(ctx) }()
+	runFor(t, s, clock, 2*time.Hour, time.Hour)
+	cancel()
+	<-errc
+
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	if len(src.params) != 1 || !src.params[0].StripAllQueryParams || src.params[0].LimitArticles != 10 {
+		t.Errorf("Expected one build with the params of the job, got %+v", src.params)
+	}
+	if runs := len(src.runs); runs != 3 {
+		t.Errorf("Expected a build and two refreshes, got %d runs", runs)
+	}
+}
@@ -1,6 +1,7 @@
 package source
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -32,18 +33,37 @@ func (s *AsyncSource) BuildAsync() error {
 	return s.BuildWithParamsAsync(DefaultBuildParams())
 }
 
+// BuildWithContext is like DefaultSource.BuildWithContext with the concurrent
+// feed checks of BuildAsync
+func (s *AsyncSource) BuildWithContext(ctx context.Context) error {
+	return s.BuildWithParamsContext(ctx, DefaultBuildParams())
+}
+
+// BuildWithParamsContext is like DefaultSource.BuildWithParamsContext with
+// the concurrent feed checks of BuildWithParamsAsync
+func (s *AsyncSource) BuildWithParamsContext(ctx context.Context, params BuildParams) error {
+	return contextError(ctx, s.buildWithParamsAsync(ctx, params))
+}
+
 // Build encapsulates download and basic parsing
 func (s *AsyncSource) BuildWithParams(params BuildParams) error {
 	return s.BuildWithParamsAsync(params)
 }
 func (s *AsyncSource) BuildWithParamsAsync(params BuildParams) error {
+	return s.buildWithParamsAsync(context.Background(), params)
+}
+
+// buildWithParamsAsync builds the source with every download canceled by ctx
+func (s *AsyncSource) buildWithParamsAsync(ctx context.Context, params BuildParams) error {
+	s.buildParams = &params
+	s.prefetched = params.PrefetchedPages
 
 	// Step 1: Download and parse homepage
 	// if InputHTML is provided, use it instead of downloading
 	if params.InputHTML != "" {
 		s.HTML = params.InputHTML
 	} else {
-		err := s.Download()
+		err := s.download(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to download source: %v", err)
 		}
@@ -56,24 +76,24 @@ func (s *AsyncSource) BuildWithParamsAsync(params BuildParams) error {
 	// Step 2: Set categories and feeds, download and parse them
 	// if onlyHomepage is true, skip categories and feeds
 	if params.OnlyHomepage {
-		s.Categories = []newspaper.Category{{URL: s.URL, HTML: s.HTML, Doc: s.Doc}}
+		s.categories = []newspaper.Category{{URL: s.URL, HTML: s.HTML, Doc: s.Doc}}
 	} else {
-		err := s.SearchCategories()
+		err := s.SearchCategoriesWithParams(params)
 		if err != nil {
 			return fmt.Errorf("failed to set categories: %v", err)
 		}
-		s.DownloadCategories()
+		s.downloadCategoriesAsync(ctx)
 	}
 	s.BuildCategories()
 
-	if len(s.Categories) > params.LimitCategories {
-		s.Categories = s.Categories[:params.LimitCategories]
+	if len(s.categories) > params.LimitCategories {
+		s.categories = s.categories[:params.LimitCategories]
 	}
 
 	// Step 3: Download and parse feed
 	// we skip feeds if onlyHomepage is true
 	if !params.OnlyHomepage {
-		s.GetFeedsWithParams(params)
+		s.getFeedsWithParamsAsync(ctx, params)
 	}
 
 	return nil
@@ -85,6 +105,11 @@ func (s *AsyncSource) GetFeedsWithParams(params BuildParams) {
 
 // GetFeeds concurrently checks common feed URLs and feeds discovered in categories
 func (s *AsyncSource) GetFeedsWithParamsAsync(params BuildParams) {
+	s.getFeedsWithParamsAsync(context.Background(), params)
+}
+
+// getFeedsWithParamsAsync checks the feeds with the downloads canceled by ctx
+func (s *AsyncSource) getFeedsWithParamsAsync(ctx context.Context, params BuildParams) {
 	commonFeedURLs := s.getCommonFeeds()
 
 	in := make(chan string, helpers.Min(len(commonFeedURLs), s.Config.MaxWorkers))
@@ -97,9 +122,8 @@ func (s *AsyncSource) GetFeedsWithParamsAsync(params BuildParams) {
 			defer wg.Done()
 			for feedURL := range in {
 				url := urls.PrepareURL(feedURL, feedURL)
-				rss, valid, err := s.checkFeed(url)
-				if valid && err == nil {
-					out <- newspaper.Feed{URL: url, RSS: rss}
+				if feed, err := s.checkFeed(ctx, url); err == nil {
+					out <- feed
 				}
 			}
 		}()
@@ -132,7 +156,7 @@ func (s *AsyncSource) GetFeedsWithParamsAsync(params BuildParams) {
 	collectorWg.Wait()
 
 	// Extract feed URLs from categories (s.extractFeedURLs is promoted from DefaultSource)
-	feedURLs := s.extractFeedURLs(s.Categories)
+	feedURLs := s.extractFeedURLs(s.categories)
 	for _, fu := range feedURLs {
 		feedsCollected = append(feedsCollected, newspaper.Feed{URL: urls.PrepareURL(fu, fu)})
 	}
@@ -143,7 +167,7 @@ func (s *AsyncSource) GetFeedsWithParamsAsync(params BuildParams) {
 		helpers.UniqueOptions{CaseSensitive: true, PreserveOrder: false},
 	)
 
-	s.Feeds = validFeeds
+	s.feeds = validFeeds
 }
 
 // DownloadCategories downloads HTML for all categories
@@ -152,9 +176,14 @@ func (s *AsyncSource) DownloadCategories() {
 }
 
 func (s *AsyncSource) DownloadCategoriesAsync() {
+	s.downloadCategoriesAsync(context.Background())
+}
+
+// downloadCategoriesAsync downloads the categories with the downloads canceled by ctx
+func (s *AsyncSource) downloadCategoriesAsync(ctx context.Context) {
 
-	in := make(chan newspaper.Category, helpers.Min(len(s.Categories), s.Config.MaxWorkers))
-	out := make(chan newspaper.Category, helpers.Min(len(s.Categories), s.Config.MaxWorkers))
+	in := make(chan newspaper.Category, helpers.Min(len(s.categories), s.Config.MaxWorkers))
+	out := make(chan newspaper.Category, helpers.Min(len(s.categories), s.Config.MaxWorkers))
 	var wg sync.WaitGroup
 
 	for i := 0; i < s.Config.MaxWorkers; i++ {
@@ -162,7 +191,7 @@ func (s *AsyncSource) DownloadCategoriesAsync() {
 		go func() {
 			defer wg.Done()
 			for category := range in {
-				err := s.downloadCategory(&category)
+				err := s.downloadCategory(ctx, &category)
 				if err == nil {
 					out <- category
 				}
@@ -172,7 +201,7 @@ func (s *AsyncSource) DownloadCategoriesAsync() {
 
 	// feeder
 	go func() {
-		for _, u := range s.Categories {
+		for _, u := range s.categories {
 			in <- u
 		}
 		close(in)
@@ -202,6 +231,6 @@ func (s *AsyncSource) DownloadCategoriesAsync() {
 		helpers.UniqueOptions{CaseSensitive: true, PreserveOrder: false},
 	)
 
-	s.Categories = validCategories
+	s.categories = validCategories
 
 }
@@ -1,14 +1,25 @@
 package source
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"fmt"
 	"io"
+	"log"
 	"math/rand/v2"
+	"mime"
+	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/tguidoux/newspaper4k-go/internal/dateparse"
 	"github.com/tguidoux/newspaper4k-go/internal/helpers"
 	"github.com/tguidoux/newspaper4k-go/internal/parsers"
 	"github.com/tguidoux/newspaper4k-go/internal/urls"
@@ -20,18 +31,37 @@ import (
 // DefaultSource is the default implementation of the Source interface
 type DefaultSource struct {
 	URL          string
-	ParsedURL    *urls.URL
 	Config       *configuration.Configuration
-	Categories   []newspaper.Category
-	Feeds        []newspaper.Feed
-	Articles     []newspaper.Article
 	HTML         string
 	Doc          *goquery.Document
 	LogoURL      string
 	Favicon      string
-	Description  string
 	IsParsed     bool
 	IsDownloaded bool
+	// Diagnostics lists the problems noticed while building the source
+	Diagnostics []string
+
+	// Homepage metadata, set by Parse
+	SiteName       string   // og:site_name of the homepage
+	Keywords       []string // Values of the keywords meta tag of the homepage
+	Language       string   // 2 char code of the homepage language, from its lang attribute or meta tags
+	CanonicalURL   string   // Canonical URL of the homepage
+	AlternateFeeds []string // RSS and Atom feeds declared by the homepage <link rel="alternate"> tags
+
+	parsedURL   *urls.URL
+	categories  []newspaper.Category
+	feeds       []newspaper.Feed
+	articles    []newspaper.Article
+	description string
+
+	// seen holds the article URLs already returned by GetArticles or Refresh
+	seen map[string]bool
+	// buildParams are the params of the last build, reused by Refresh
+	buildParams *BuildParams
+	// prefetched holds BuildParams.PrefetchedPages, served instead of the network
+	prefetched map[string]string
+	// likelyRequiresJS is set when no article was found on pages rendered by scripts
+	likelyRequiresJS bool
 }
 
 // NewDefaultSource creates a new DefaultSource
@@ -47,13 +77,14 @@ func NewDefaultSource(request SourceRequest) (*DefaultSource, error) {
 
 	source := &DefaultSource{
 		URL:          url,
-		ParsedURL:    preparedURL,
+		parsedURL:    preparedURL,
 		Config:       &config,
-		Categories:   []newspaper.Category{},
-		Feeds:        []newspaper.Feed{},
-		Articles:     []newspaper.Article{},
+		categories:   []newspaper.Category{},
+		feeds:        []newspaper.Feed{},
+		articles:     []newspaper.Article{},
 		IsParsed:     false,
 		IsDownloaded: false,
+		seen:         map[string]bool{},
 	}
 
 	return source, nil
@@ -70,13 +101,20 @@ func (s *DefaultSource) Build() error {
 
 // Build encapsulates download and basic parsing
 func (s *DefaultSource) BuildWithParams(params BuildParams) error {
+	return s.buildWithParams(context.Background(), params)
+}
+
+// buildWithParams builds the source with every download canceled by ctx
+func (s *DefaultSource) buildWithParams(ctx context.Context, params BuildParams) error {
+	s.buildParams = &params
+	s.prefetched = params.PrefetchedPages
 
 	// Step 1: Download and parse homepage
 	// if InputHTML is provided, use it instead of downloading
 	if params.InputHTML != "" {
 		s.HTML = params.InputHTML
 	} else {
-		err := s.Download()
+		err := s.download(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to download source: %v", err)
 		}
@@ -89,56 +127,70 @@ func (s *DefaultSource) BuildWithParams(params BuildParams) error {
 	// Step 2: Set categories and feeds, download and parse them
 	// if onlyHomepage is true, skip categories and feeds
 	if params.OnlyHomepage {
-		s.Categories = []newspaper.Category{{URL: s.URL, HTML: s.HTML, Doc: s.Doc}}
+		s.categories = []newspaper.Category{{URL: s.URL, HTML: s.HTML, Doc: s.Doc}}
 	} else {
-		err := s.SearchCategories()
+		err := s.SearchCategoriesWithParams(params)
 		if err != nil {
 			return fmt.Errorf("failed to set categories: %v", err)
 		}
-		s.DownloadCategories()
+		s.downloadCategories(ctx)
 	}
 	s.BuildCategories()
 
-	if len(s.Categories) > params.LimitCategories {
-		s.Categories = s.Categories[:params.LimitCategories]
+	if len(s.categories) > params.LimitCategories {
+		s.categories = s.categories[:params.LimitCategories]
 	}
 
 	// Step 3: Download and parse feed
 	// we skip feeds if onlyHomepage is true
 	if !params.OnlyHomepage {
-		s.GetFeedsWithParams(params)
+		s.getFeedsWithParams(ctx, params)
 	}
 
 	return nil
 }
 
+// BuildWithContext is like Build but every download, of the homepage, the
+// categories and the feeds, is canceled with ctx. When ctx is canceled or
+// its deadline passes, the build stops promptly and returns ctx.Err(),
+// keeping what was gathered so far.
+func (s *DefaultSource) BuildWithContext(ctx context.Context) error {
+	return s.BuildWithParamsContext(ctx, DefaultBuildParams())
+}
+
+// BuildWithParamsContext is like BuildWithParams with the cancellation of
+// BuildWithContext
+func (s *DefaultSource) BuildWithParamsContext(ctx context.Context, params BuildParams) error {
+	return contextError(ctx, s.buildWithParams(ctx, params))
+}
+
+// contextError returns ctx.Err() once ctx is done, else err
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
 // Download downloads the HTML of the source
 func (s *DefaultSource) Download() error {
+	return s.download(context.Background())
+}
 
-	client := helpers.CreateHTTPClient(s.Config.RequestsParams.Timeout)
-	resp, err := client.Get(s.URL)
+// download downloads the HTML of the source, canceled with ctx
+func (s *DefaultSource) download(ctx context.Context) error {
+	resp, err := s.fetch(ctx, s.URL)
 	if err != nil {
 		// Handle error - could log or set a flag
 		return fmt.Errorf("failed to download: %v", err)
 	}
-	defer func() {
-		err = resp.Body.Close()
-		if err != nil {
-			fmt.Printf("failed to close response body: %v", err)
-		}
-	}()
 
 	if resp.StatusCode >= 400 {
 		// Handle HTTP error
 		return fmt.Errorf("received HTTP status %d", resp.StatusCode)
 	}
 
-	htmlBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		// Handle error
-		return fmt.Errorf("failed to read response body: %v", err)
-	}
-	s.HTML = string(htmlBytes)
+	s.HTML = parsers.GetUnicodeHTMLWithContentType(string(resp.Body), resp.Header.Get("Content-Type"))
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s.HTML))
 	if err != nil {
 		// Handle error
@@ -150,7 +202,7 @@ func (s *DefaultSource) Download() error {
 	return nil
 }
 
-// Parse sets the goquery document and sets description
+// Parse sets the goquery document and extracts the homepage metadata
 func (s *DefaultSource) Parse() error {
 
 	// Ensure Doc is set
@@ -163,6 +215,7 @@ func (s *DefaultSource) Parse() error {
 		s.Doc = doc
 	}
 	s.extractDescription()
+	s.extractHomepageMetadata()
 	s.IsParsed = true
 
 	return nil
@@ -174,6 +227,13 @@ func (s *DefaultSource) Parse() error {
 // SearchCategories sets the categories for the source
 // Only includes categories from the same domain as the source URL
 func (s *DefaultSource) SearchCategories() error {
+	return s.SearchCategoriesWithParams(DefaultBuildParams())
+}
+
+// SearchCategoriesWithParams sets the categories for the source. With
+// IncludeSectionSubdomains, the home pages of section subdomains such as
+// sports.example.com are categories as well.
+func (s *DefaultSource) SearchCategoriesWithParams(params BuildParams) error {
 
 	// Simple implementation: extract categories from links on the homepage
 	if s.Doc == nil {
@@ -181,7 +241,7 @@ func (s *DefaultSource) SearchCategories() error {
 	}
 
 	categoryURLs := []string{}
-	sourceDomain := s.ParsedURL.Domain
+	sourceDomain := s.parsedURL.Domain
 
 	s.Doc.Find("a").Each(func(i int, sel *goquery.Selection) {
 		href, exists := sel.Attr("href")
@@ -196,6 +256,10 @@ func (s *DefaultSource) SearchCategories() error {
 				if categoryUrl.Domain == sourceDomain {
 					categoryURLs = append(categoryURLs, categoryUrl.String())
 				}
+			} else if params.IncludeSectionSubdomains {
+				if sectionURL := s.sectionSubdomainURL(fullURL); sectionURL != "" {
+					categoryURLs = append(categoryURLs, sectionURL)
+				}
 			}
 		}
 	})
@@ -206,9 +270,9 @@ func (s *DefaultSource) SearchCategories() error {
 	// Remove duplicates
 	uniqueURLs := helpers.UniqueStringsSimple(categoryURLs)
 
-	s.Categories = make([]newspaper.Category, len(uniqueURLs))
+	s.categories = make([]newspaper.Category, len(uniqueURLs))
 	for i, u := range uniqueURLs {
-		s.Categories[i] = newspaper.Category{URL: u}
+		s.categories[i] = newspaper.Category{URL: u}
 	}
 
 	return nil
@@ -216,52 +280,131 @@ func (s *DefaultSource) SearchCategories() error {
 
 // DownloadCategories downloads HTML for all categories
 func (s *DefaultSource) DownloadCategories() {
+	s.downloadCategories(context.Background())
+}
 
+// downloadCategories downloads HTML for all categories, canceled with ctx
+func (s *DefaultSource) downloadCategories(ctx context.Context) {
 	var validCategories []newspaper.Category
 
-	for _, cat := range s.Categories {
-		err := s.downloadCategory(&cat)
+	for _, cat := range s.categories {
+		err := s.downloadCategory(ctx, &cat)
 		if err == nil {
 			validCategories = append(validCategories, cat)
 		}
 	}
 
-	s.Categories = validCategories
+	s.categories = validCategories
+}
+
+// fetch downloads a URL through the shared fetcher so that concurrent
+// downloads of the same URL across sources result in a single request.
+// With prefetched pages, the network is never used: pages missing from
+// them are not found.
+func (s *DefaultSource) fetch(ctx context.Context, rawURL string) (*helpers.FetchResult, error) {
+	return s.fetchURL(ctx, rawURL, false)
 }
 
-// downloadCategories downloads HTML for all categories
-func (s *DefaultSource) downloadCategory(category *newspaper.Category) error {
+// fetchURL is fetch, downloading the URL again instead of reusing a recently
+// downloaded body when refresh is true
+func (s *DefaultSource) fetchURL(ctx context.Context, rawURL string, refresh bool) (*helpers.FetchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.prefetched != nil {
+		page, ok := s.prefetched[rawURL]
+		if !ok {
+			return &helpers.FetchResult{URL: rawURL, StatusCode: http.StatusNotFound, Header: http.Header{}}, nil
+		}
+		return &helpers.FetchResult{URL: rawURL, StatusCode: http.StatusOK, Header: http.Header{}, Body: []byte(page)}, nil
+	}
+	client := s.httpClient(helpers.SharedHTTPClient(s.Config.RequestsParams.Timeout, -1, s.Config.RequestsParams.Proxies))
+	return helpers.DefaultFetcher.Get(client, rawURL, helpers.FetchOptions{
+		RecentCacheSize: s.Config.RecentDownloadCacheSize,
+		RecentCacheTTL:  time.Duration(s.Config.RecentDownloadCacheTTLSeconds) * time.Second,
+		Refresh:         refresh,
+		UserAgent:       s.Config.RequestsParams.NextUserAgent(),
+		Headers:         s.Config.RequestsParams.Headers,
+		MaxRetries:      s.Config.RequestsParams.MaxRetries,
+		RetryBackoff:    s.Config.RequestsParams.RetryBackoff,
+		Context:         ctx,
+	})
+}
 
-	client := helpers.CreateHTTPClient(s.Config.RequestsParams.Timeout)
-	resp, err := client.Get(category.URL)
+// downloadCategory downloads the HTML of category
+func (s *DefaultSource) downloadCategory(ctx context.Context, category *newspaper.Category) error {
+	resp, err := s.fetch(ctx, category.URL)
 	if err != nil || resp.StatusCode >= 400 {
 		return fmt.Errorf("failed to get category")
 	}
 
-	htmlBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to get category body")
+	// JSON APIs or login pages behind a category URL would yield garbage links
+	for retry := 0; !isHTMLResponse(resp); retry++ {
+		if retry >= s.Config.NonHTMLCategoryRetries {
+			if s.Config.Verbose {
+				log.Printf("skipping category %s: content type %q is not HTML", category.URL, resp.Header.Get("Content-Type"))
+			}
+			return fmt.Errorf("category %s is not HTML", category.URL)
+		}
+		// The recent download cache would replay the same response
+		resp, err = s.fetchURL(ctx, category.URL, true)
+		if err != nil || resp.StatusCode >= 400 {
+			return fmt.Errorf("failed to get category")
+		}
 	}
-	err = resp.Body.Close()
+
+	category.HTML = parsers.GetUnicodeHTMLWithContentType(string(resp.Body), resp.Header.Get("Content-Type"))
+	return nil
+}
+
+// isHTMLResponse reports whether resp is an HTML page, sniffing the body when
+// the server did not send a content type
+func isHTMLResponse(resp *helpers.FetchResult) bool {
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(resp.Body)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
 	if err != nil {
-		return fmt.Errorf("failed to close category body")
+		return false
 	}
-	category.HTML = string(htmlBytes)
-	return nil
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
 }
 
 // BuildCategories parses the HTML into goquery documents
 func (s *DefaultSource) BuildCategories() {
-	for i, cat := range s.Categories {
+	for i, cat := range s.categories {
 		if cat.HTML != "" {
 			doc, err := parsers.FromString(cat.HTML)
 			if err == nil {
-				s.Categories[i].Doc = doc
+				s.categories[i].Doc = doc
 			}
 		}
 	}
 }
 
+// sectionSubdomainURL returns the normalized home page URL when rawURL is the
+// home page of a section subdomain of the source, and "" otherwise
+func (s *DefaultSource) sectionSubdomainURL(rawURL string) string {
+	u, err := urls.Parse(rawURL)
+	if err != nil || u.Domain != s.parsedURL.Domain || u.TLD != s.parsedURL.TLD {
+		return ""
+	}
+	if u.Subdomain == "" || u.Subdomain == s.parsedURL.Subdomain || strings.Contains(u.Subdomain, ".") {
+		return ""
+	}
+	if slices.Contains(constants.NON_SECTION_SUBDOMAINS, strings.ToLower(u.Subdomain)) {
+		return ""
+	}
+	if strings.Trim(u.Path, "/") != "" || u.RawQuery != "" {
+		return ""
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
 // -----------------------------------------------------------------
 // Feeds
 // -----------------------------------------------------------------
@@ -279,13 +422,13 @@ func (s *DefaultSource) getCommonFeeds() []string {
 		parsed, _ := url.Parse(s.URL)
 		if strings.HasPrefix(parsed.Path, "/@") {
 			newPath := "/feed/" + strings.Split(parsed.Path, "/")[1]
-			newURL := s.ParsedURL.Scheme + "://" + s.ParsedURL.Domain + newPath
+			newURL := s.parsedURL.Scheme + "://" + s.parsedURL.Domain + newPath
 			commonFeedURLs = append(commonFeedURLs, newURL)
 		}
 	}
 
 	// Add feeds from categories
-	for _, cat := range s.Categories {
+	for _, cat := range s.categories {
 		pathChunks := strings.Split(strings.Trim(cat.URL, "/"), "/")
 		if len(pathChunks) > 0 && strings.Contains(pathChunks[len(pathChunks)-1], ".") {
 			continue // skip files
@@ -302,29 +445,136 @@ func (s *DefaultSource) getCommonFeeds() []string {
 	return commonFeedURLs
 }
 
-func (s *DefaultSource) checkFeed(feedURL string) (string, bool, error) {
-	client := helpers.CreateHTTPClient(s.Config.RequestsParams.Timeout)
-
-	resp, err := client.Get(feedURL)
-	if err != nil || resp.StatusCode >= 300 {
-		return "", false, fmt.Errorf("invalid status code while fetching rss")
+// checkFeed downloads feedURL, following at most Config.FeedMaxRedirects
+// redirects, and returns the feed served there with its polling hints. Feeds redirected to another
+// registrable domain, unless Config.FeedAllowCrossDomainRedirects is set, and
+// pages that are not feeds, such as login pages, are rejected.
+func (s *DefaultSource) checkFeed(ctx context.Context, feedURL string) (newspaper.Feed, error) {
+	resp, err := s.fetchFeed(ctx, feedURL)
+	if err != nil {
+		return newspaper.Feed{}, fmt.Errorf("error fetching feed %s: %w", feedURL, err)
+	}
+	if resp.StatusCode >= 300 {
+		return newspaper.Feed{}, fmt.Errorf("invalid status code %d while fetching feed %s", resp.StatusCode, feedURL)
 	}
 
-	rssBytes, err := io.ReadAll(resp.Body)
+	body, err := decodeContentEncoding(resp)
 	if err != nil {
-		return "", false, fmt.Errorf("failed to read rss")
+		return newspaper.Feed{}, fmt.Errorf("error decoding feed %s: %w", feedURL, err)
 	}
-	err = resp.Body.Close()
-	if err != nil {
-		return "", false, fmt.Errorf("failed to close rss body")
+	decoded := *resp
+	decoded.Body = body
+	resp = &decoded
+
+	feed := newspaper.Feed{URL: feedURL, RSS: string(resp.Body)}
+	if len(resp.Redirects) > 0 {
+		feed.RedirectChain = append(slices.Clone(resp.Redirects), resp.URL)
+		if !s.Config.FeedAllowCrossDomainRedirects && !sameRegistrableDomain(s.URL, resp.URL) {
+			return newspaper.Feed{}, fmt.Errorf("feed %s redirected to another domain: %s", feedURL, strings.Join(feed.RedirectChain, " -> "))
+		}
+	}
+	if !isFeedResponse(resp) {
+		return newspaper.Feed{}, fmt.Errorf("%s is not a feed", resp.URL)
+	}
+	feed.PollingHints = newspaper.ParsePollingHints(feed.RSS)
+	return feed, nil
+}
+
+// httpClient returns Config.HTTPClient, else fallback
+func (s *DefaultSource) httpClient(fallback *http.Client) configuration.HTTPClient {
+	if s.Config.HTTPClient != nil {
+		return s.Config.HTTPClient
 	}
-	rss := string(rssBytes)
+	return fallback
+}
 
-	return rss, true, nil
+// fetchFeed is like fetch but follows at most Config.FeedMaxRedirects redirects
+func (s *DefaultSource) fetchFeed(ctx context.Context, rawURL string) (*helpers.FetchResult, error) {
+	if s.prefetched != nil {
+		return s.fetch(ctx, rawURL)
+	}
+	client := s.httpClient(helpers.SharedHTTPClient(s.Config.RequestsParams.Timeout, max(s.Config.FeedMaxRedirects, 0), s.Config.RequestsParams.Proxies))
+	return helpers.DefaultFetcher.Get(client, rawURL, helpers.FetchOptions{
+		RecentCacheSize: s.Config.RecentDownloadCacheSize,
+		RecentCacheTTL:  time.Duration(s.Config.RecentDownloadCacheTTLSeconds) * time.Second,
+		UserAgent:       s.Config.RequestsParams.NextUserAgent(),
+		Headers:         s.Config.RequestsParams.Headers,
+		MaxRetries:      s.Config.RequestsParams.MaxRetries,
+		RetryBackoff:    s.Config.RequestsParams.RetryBackoff,
+		Context:         ctx,
+	})
+}
+
+// decodeContentEncoding returns the body of resp decoded according to its
+// Content-Encoding header: gzip and deflate are decompressed, other encodings
+// are returned as is. The body of the shared FetchResult is not modified.
+func decodeContentEncoding(resp *helpers.FetchResult) ([]byte, error) {
+	var reader io.ReadCloser
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		if len(resp.Body) < 2 || resp.Body[0] != 0x1f || resp.Body[1] != 0x8b {
+			// Already decompressed on the way, e.g. by a proxy
+			return resp.Body, nil
+		}
+		gz, err := gzip.NewReader(bytes.NewReader(resp.Body))
+		if err != nil {
+			return nil, err
+		}
+		reader = gz
+	case "deflate":
+		// deflate is meant to be zlib wrapped but some servers send raw deflate
+		if zr, err := zlib.NewReader(bytes.NewReader(resp.Body)); err == nil {
+			reader = zr
+		} else {
+			reader = flate.NewReader(bytes.NewReader(resp.Body))
+		}
+	default:
+		return resp.Body, nil
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	return io.ReadAll(reader)
+}
+
+// isFeedResponse reports whether resp is an RSS, Atom or RDF feed, from its
+// XML content type or, for generic types, from the start of its body
+func isFeedResponse(resp *helpers.FetchResult) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if strings.Contains(mediaType, "xml") && mediaType != "application/xhtml+xml" {
+		return true
+	}
+	if mediaType == "text/html" || mediaType == "application/xhtml+xml" {
+		return false
+	}
+	head := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(string(resp.Body[:min(len(resp.Body), 512)]), "\ufeff")))
+	for _, prefix := range []string{"<?xml", "<rss", "<feed", "<rdf:rdf"} {
+		if strings.HasPrefix(head, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameRegistrableDomain reports whether the URLs share their registrable
+// domain, or their host when it cannot be determined
+func sameRegistrableDomain(a, b string) bool {
+	ua, errA := urls.Parse(a)
+	ub, errB := urls.Parse(b)
+	if errA != nil || errB != nil || ua.Domain == "" || ub.Domain == "" {
+		ha, errA := url.Parse(a)
+		hb, errB := url.Parse(b)
+		return errA == nil && errB == nil && strings.EqualFold(ha.Hostname(), hb.Hostname())
+	}
+	return strings.EqualFold(ua.Domain, ub.Domain) && strings.EqualFold(ua.TLD, ub.TLD)
 }
 
 func (s *DefaultSource) GetFeedsWithParams(params BuildParams) {
+	s.getFeedsWithParams(context.Background(), params)
+}
 
+// getFeedsWithParams checks the feeds with the downloads canceled by ctx
+func (s *DefaultSource) getFeedsWithParams(ctx context.Context, params BuildParams) {
 	commonFeedURLs := s.getCommonFeeds()
 
 	// Download and check feeds
@@ -332,15 +582,13 @@ func (s *DefaultSource) GetFeedsWithParams(params BuildParams) {
 
 	for _, feedURL := range commonFeedURLs {
 		url := urls.PrepareURL(feedURL, feedURL)
-		rss, valid, err := s.checkFeed(url)
-		if valid && err != nil {
-			feed := newspaper.Feed{URL: url, RSS: rss}
+		if feed, err := s.checkFeed(ctx, url); err == nil {
 			validFeeds = append(validFeeds, feed)
 		}
 	}
 
 	// Extract feed URLs from categories
-	feedURLs := s.extractFeedURLs(s.Categories)
+	feedURLs := s.extractFeedURLs(s.categories)
 	for _, feedURL := range feedURLs {
 		validFeeds = append(validFeeds, newspaper.Feed{URL: urls.PrepareURL(feedURL, feedURL)})
 	}
@@ -353,7 +601,7 @@ func (s *DefaultSource) GetFeedsWithParams(params BuildParams) {
 		helpers.UniqueOptions{CaseSensitive: true, PreserveOrder: true},
 	)
 
-	s.Feeds = validFeeds
+	s.feeds = validFeeds
 }
 
 func (s *DefaultSource) GetFeeds() {
@@ -365,10 +613,10 @@ func (s *DefaultSource) GetFeeds() {
 // -----------------------------------------------------------------
 
 // feedsToArticles returns articles from RSS feeds
-func (s *DefaultSource) feedsToArticles() []newspaper.Article {
+func (s *DefaultSource) feedsToArticles(params BuildParams, filter *articleURLFilter) []newspaper.Article {
 	articles := []newspaper.Article{}
 
-	for _, feed := range s.Feeds {
+	for _, feed := range s.feeds {
 		if feed.RSS == "" {
 
 			continue
@@ -451,20 +699,31 @@ func (s *DefaultSource) feedsToArticles() []newspaper.Article {
 			}
 
 			// Clean up the URL and validate
-			articleURL = urls.PrepareURL(articleURL, s.URL)
+			articleURL = prepareArticleURL(articleURL, s.URL, params)
+			if articleURL == "" {
+				return
+			}
+			article := newspaper.Article{
+				URL:       articleURL,
+				SourceURL: s.parsedURL.String(),
+				Config:    s.Config,
+			}
+
+			switch filter.decide(articleURL, DiscoveryMeta{Origin: DiscoveredInFeed, PageURL: feed.URL}) {
+			case Accept:
+				articles = append(articles, article)
+				return
+			case Reject:
+				return
+			}
 
-			if articleURL != "" && newspaper.IsLikelyArticleURL(articleURL) {
+			if newspaper.IsLikelyArticleURL(articleURL) {
 				// Only include articles from the same domain as the source
 				parsedArticleURL, err := urls.Parse(articleURL)
 				if err != nil {
 					return
 				}
-				if parsedArticleURL.Domain == s.ParsedURL.Domain && parsedArticleURL.String() != parsedFeedURL.String() {
-					article := newspaper.Article{
-						URL:       articleURL,
-						SourceURL: s.ParsedURL.String(),
-						Config:    s.Config,
-					}
+				if parsedArticleURL.Domain == s.parsedURL.Domain && parsedArticleURL.String() != parsedFeedURL.String() {
 					articles = append(articles, article)
 				}
 			}
@@ -478,7 +737,7 @@ func (s *DefaultSource) feedsToArticles() []newspaper.Article {
 // Only includes feeds from the same domain as the source URL
 func (s *DefaultSource) extractFeedURLs(categories []newspaper.Category) []string {
 	feedURLs := []string{}
-	sourceDomain := s.ParsedURL.Domain
+	sourceDomain := s.parsedURL.Domain
 
 	for _, cat := range categories {
 		if cat.Doc == nil {
@@ -506,11 +765,11 @@ func (s *DefaultSource) extractFeedURLs(categories []newspaper.Category) []strin
 
 // categoriesToArticles returns articles from categories
 // Only includes articles from the same domain as the source URL
-func (s *DefaultSource) categoriesToArticles() []newspaper.Article {
+func (s *DefaultSource) categoriesToArticles(params BuildParams, filter *articleURLFilter) []newspaper.Article {
 	articles := []newspaper.Article{}
-	sourceDomain := s.ParsedURL.Domain
+	sourceDomain := s.parsedURL.Domain
 
-	for _, cat := range s.Categories {
+	for _, cat := range s.categories {
 		if cat.Doc == nil && cat.HTML != "" {
 			doc, err := parsers.FromString(cat.HTML)
 			if err != nil {
@@ -520,26 +779,40 @@ func (s *DefaultSource) categoriesToArticles() []newspaper.Article {
 		} else if cat.Doc == nil && cat.HTML == "" {
 			continue
 		}
+		origin := DiscoveredInCategory
+		if cat.URL == s.URL {
+			origin = DiscoveredOnHomepage
+		}
 		cat.Doc.Find("a").Each(func(i int, sel *goquery.Selection) {
 			href, exists := sel.Attr("href")
 			if exists && href != "" && href != "/" && href != "#" {
-				articleURL := urls.PrepareURL(href, cat.URL)
-				if newspaper.IsLikelyArticleURL(articleURL) && articleURL != s.URL && articleURL != cat.URL {
+				articleURL := prepareArticleURL(href, cat.URL, params)
+				if articleURL == "" || articleURL == s.URL || articleURL == cat.URL {
+					return
+				}
+				title := sel.Text()
+				article := newspaper.Article{
+					URL:       articleURL,
+					SourceURL: s.parsedURL.String(),
+					Title:     title,
+					Config:    s.Config,
+				}
+
+				switch filter.decide(articleURL, DiscoveryMeta{Origin: origin, PageURL: cat.URL, AnchorText: strings.TrimSpace(title)}) {
+				case Accept:
+					articles = append(articles, article)
+					return
+				case Reject:
+					return
+				}
+
+				if newspaper.IsLikelyArticleURL(articleURL) {
 					// Only include articles from the same domain as the source
 					parsedArticleURL, err := urls.Parse(articleURL)
 					if err != nil {
 						return
 					}
 					if parsedArticleURL.Domain == sourceDomain {
-						// Skip navigation links - only include links that look like articles
-						// Articles typically have IDs, dates, or specific patterns
-						title := sel.Text()
-						article := newspaper.Article{
-							URL:       articleURL,
-							SourceURL: s.ParsedURL.String(),
-							Title:     title,
-							Config:    s.Config,
-						}
 						articles = append(articles, article)
 					}
 				}
@@ -551,10 +824,19 @@ func (s *DefaultSource) categoriesToArticles() []newspaper.Article {
 }
 
 func (s *DefaultSource) GetArticlesWithParams(params BuildParams) []newspaper.Article {
-	categoryArticles := s.categoriesToArticles()
-	feedArticles := s.feedsToArticles()
+	s.articles = s.collectArticles(params)
+	s.markSeen(s.articles)
+	return s.articles
+}
+
+// collectArticles gathers the articles linked from the categories and feeds
+func (s *DefaultSource) collectArticles(params BuildParams) []newspaper.Article {
+	filter := newArticleURLFilter(params.ArticleURLFilter)
+	categoryArticles := s.categoriesToArticles(params, filter)
+	feedArticles := s.feedsToArticles(params, filter)
 
 	allArticles := append(feedArticles, categoryArticles...)
+	s.detectScriptRendering(len(allArticles))
 
 	// Remove duplicates
 	uniqueArticles := helpers.UniqueStructByKey(
@@ -573,10 +855,8 @@ func (s *DefaultSource) GetArticlesWithParams(params BuildParams) []newspaper.Ar
 			if err != nil {
 				continue
 			}
-			if s.ParsedURL.Domain == parsedArticleURL.Domain {
-				if params.AllowSubDomain || (!params.AllowSubDomain && s.ParsedURL.Subdomain == parsedArticleURL.Subdomain) {
-					filteredArticles = append(filteredArticles, article)
-				}
+			if isSameSite(s.parsedURL, parsedArticleURL, params) {
+				filteredArticles = append(filteredArticles, article)
 			}
 		}
 		uniqueArticles = filteredArticles
@@ -589,12 +869,79 @@ func (s *DefaultSource) GetArticlesWithParams(params BuildParams) []newspaper.Ar
 	}
 
 	if params.LimitArticles > 0 && len(uniqueArticles) > params.LimitArticles {
-		s.Articles = uniqueArticles[:params.LimitArticles]
-	} else {
-		s.Articles = uniqueArticles
+		return uniqueArticles[:params.LimitArticles]
+	}
+	return uniqueArticles
+}
+
+// prepareArticleURL canonicalizes a link to an article found on the page at
+// baseURL, stripping its query with BuildParams.StripAllQueryParams
+func prepareArticleURL(href, baseURL string, params BuildParams) string {
+	articleURL := urls.PrepareURL(href, baseURL)
+	if params.StripAllQueryParams && articleURL != "" {
+		articleURL = urls.StripQuery(articleURL, params.KeepQueryParams)
+	}
+	return articleURL
+}
+
+// articleURLFilter memoizes the decisions of a BuildParams.ArticleURLFilter so
+// that it is called once per candidate URL, however often the URL is linked
+type articleURLFilter struct {
+	filter    func(u *urls.URL, meta DiscoveryMeta) FilterDecision
+	decisions map[string]FilterDecision
+}
+
+// newArticleURLFilter returns nil when filter is nil
+func newArticleURLFilter(filter func(u *urls.URL, meta DiscoveryMeta) FilterDecision) *articleURLFilter {
+	if filter == nil {
+		return nil
+	}
+	return &articleURLFilter{filter: filter, decisions: map[string]FilterDecision{}}
+}
+
+// decide returns the decision for the canonical articleURL, DefaultHeuristics
+// without filter or when the URL cannot be parsed
+func (f *articleURLFilter) decide(articleURL string, meta DiscoveryMeta) FilterDecision {
+	if f == nil {
+		return DefaultHeuristics
+	}
+	if decision, ok := f.decisions[articleURL]; ok {
+		return decision
+	}
+	decision := DefaultHeuristics
+	if u, err := urls.Parse(articleURL); err == nil {
+		decision = f.filter(u, meta)
+	}
+	f.decisions[articleURL] = decision
+	return decision
+}
+
+// mobileSubdomainLabels are the leading subdomain labels of mobile and AMP
+// variants of a site, ignored with BuildParams.MergeMobileSubdomains
+var mobileSubdomainLabels = []string{"amp", "m", "mobile"}
+
+// isSameSite reports whether articleURL belongs to the site of sourceURL for
+// BuildParams.OnlySameDomain: both must share the registrable domain and,
+// unless AllowSubDomain is set, the same canonical subdomain
+func isSameSite(sourceURL, articleURL *urls.URL, params BuildParams) bool {
+	if !strings.EqualFold(sourceURL.Domain, articleURL.Domain) || !strings.EqualFold(sourceURL.TLD, articleURL.TLD) {
+		return false
+	}
+	if params.AllowSubDomain {
+		return true
 	}
+	return canonicalSubdomain(sourceURL.Subdomain, params.MergeMobileSubdomains) ==
+		canonicalSubdomain(articleURL.Subdomain, params.MergeMobileSubdomains)
+}
 
-	return s.Articles
+// canonicalSubdomain lowercases subdomain and strips its leading "www" label
+// and, with mergeMobile, its leading mobile and AMP labels
+func canonicalSubdomain(subdomain string, mergeMobile bool) string {
+	labels := strings.Split(strings.ToLower(subdomain), ".")
+	for len(labels) > 0 && (labels[0] == "www" || (mergeMobile && slices.Contains(mobileSubdomainLabels, labels[0]))) {
+		labels = labels[1:]
+	}
+	return strings.Join(labels, ".")
 }
 
 // GetArticles creates the list of Article objects
@@ -602,6 +949,71 @@ func (s *DefaultSource) GetArticles() []newspaper.Article {
 	return s.GetArticlesWithParams(DefaultBuildParams())
 }
 
+// LikelyRequiresJS reports whether the last article discovery found no
+// article while the homepage or a category looked rendered by JavaScript,
+// such as infinite-scroll pages loading their links with scripts. Such
+// sources need a fetcher executing JavaScript, like a headless browser.
+func (s *DefaultSource) LikelyRequiresJS() bool {
+	return s.likelyRequiresJS
+}
+
+// detectScriptRendering sets likelyRequiresJS and adds a diagnostic for each
+// index page rendered by scripts when no article was discovered
+func (s *DefaultSource) detectScriptRendering(found int) {
+	s.likelyRequiresJS = false
+	if found > 0 {
+		return
+	}
+
+	pages := []newspaper.Category{{URL: s.URL, Doc: s.Doc}}
+	pages = append(pages, s.categories...)
+	for _, page := range pages {
+		doc := page.Doc
+		if doc == nil && page.HTML != "" {
+			doc, _ = parsers.FromString(page.HTML)
+		}
+		if doc == nil || !isScriptRenderedPage(doc) {
+			continue
+		}
+		s.likelyRequiresJS = true
+		diagnostic := fmt.Sprintf("no article link found on %s, which looks rendered by JavaScript: a headless browser fetcher is likely required", page.URL)
+		if !slices.Contains(s.Diagnostics, diagnostic) {
+			s.Diagnostics = append(s.Diagnostics, diagnostic)
+			if s.Config.Verbose {
+				log.Print(diagnostic)
+			}
+		}
+	}
+}
+
+const (
+	// scriptMountSelector matches the elements JavaScript frameworks render pages into
+	scriptMountSelector = "#root, #app, #__next, #__nuxt, [data-reactroot], [ng-app], app-root"
+	// scriptRenderedMaxWords is the number of words under which a page with
+	// scripts and no article link is considered rendered by them
+	scriptRenderedMaxWords = 50
+)
+
+// isScriptRenderedPage reports whether doc is a shell filled by scripts: it
+// has scripts and either an empty mount element, a noscript notice about
+// JavaScript, or barely any text outside of them
+func isScriptRenderedPage(doc *goquery.Document) bool {
+	if doc.Find("script").Length() == 0 {
+		return false
+	}
+	emptyMount := false
+	doc.Find(scriptMountSelector).EachWithBreak(func(i int, sel *goquery.Selection) bool {
+		emptyMount = strings.TrimSpace(sel.Text()) == ""
+		return !emptyMount
+	})
+	if emptyMount || strings.Contains(strings.ToLower(doc.Find("noscript").Text()), "javascript") {
+		return true
+	}
+	body := doc.Find("body").Clone()
+	body.Find("script, style, noscript, template").Remove()
+	return len(strings.Fields(body.Text())) < scriptRenderedMaxWords
+}
+
 // -----------------------------------------------------------------
 // Utility methods
 // -----------------------------------------------------------------
@@ -613,11 +1025,115 @@ func (s *DefaultSource) extractDescription() {
 	}
 	description, exists := s.Doc.Find("meta[name='description']").Attr("content")
 	if exists {
-		s.Description = description
+		s.description = description
 	}
 }
 
+// extractHomepageMetadata sets the site name, keywords, language, canonical
+// URL and declared feeds from the homepage, like the article metadata
+func (s *DefaultSource) extractHomepageMetadata() {
+	if s.Doc == nil {
+		return
+	}
+	s.SiteName = parsers.MetaField(s.Doc.Selection, "og:site_name")
+	s.Keywords = parsers.MetaKeywords(s.Doc.Selection)
+	s.Language = parsers.LangAttribute(s.Doc.Find("html"))
+	if s.Language == "" {
+		s.Language = parsers.LanguageCode(parsers.MetaField(s.Doc.Selection, "og:locale", "lang"))
+	}
+	if tags := parsers.GetTags(s.Doc.Selection, "meta", map[string]string{"http-equiv": "content-language"}, "exact", false); s.Language == "" && len(tags) > 0 {
+		s.Language = parsers.LanguageCode(tags[0].AttrOr("content", ""))
+	}
+	s.CanonicalURL = parsers.CanonicalLink(s.URL, s.Doc.Selection)
+	s.AlternateFeeds = parsers.FeedLinks(s.URL, s.Doc.Selection)
+}
+
 // Size returns the number of articles
 func (s *DefaultSource) Size() int {
-	return len(s.Articles)
+	return len(s.articles)
+}
+
+// Categories returns the categories discovered on the homepage
+func (s *DefaultSource) Categories() []newspaper.Category {
+	return s.categories
+}
+
+// Feeds returns the RSS and Atom feeds of the source
+func (s *DefaultSource) Feeds() []newspaper.Feed {
+	return s.feeds
+}
+
+// Articles returns the articles of the last GetArticles or Refresh call
+func (s *DefaultSource) Articles() []newspaper.Article {
+	return s.articles
+}
+
+// Description returns the description of the source from its homepage metadata
+func (s *DefaultSource) Description() string {
+	return s.description
+}
+
+// ParsedURL returns the parsed URL of the source
+func (s *DefaultSource) ParsedURL() *urls.URL {
+	return s.parsedURL
+}
+
+// EstimatePublishCadence estimates how often the source publishes from the median
+// time between the dated items of its feeds. It returns zero when fewer than
+// three dated items are available.
+func (s *DefaultSource) EstimatePublishCadence() time.Duration {
+	dates := s.feedItemDates()
+	if len(dates) < 3 {
+		return 0
+	}
+
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+
+	var intervals []time.Duration
+	for i := 1; i < len(dates); i++ {
+		if d := dates[i].Sub(dates[i-1]); d > 0 {
+			intervals = append(intervals, d)
+		}
+	}
+	if len(intervals) < 2 {
+		return 0
+	}
+
+	slices.Sort(intervals)
+	mid := len(intervals) / 2
+	if len(intervals)%2 == 0 {
+		return (intervals[mid-1] + intervals[mid]) / 2
+	}
+	return intervals[mid]
+}
+
+// feedItemDates returns the publish dates of all items found in the downloaded feeds
+func (s *DefaultSource) feedItemDates() []time.Time {
+	var dates []time.Time
+	parser := dateparse.New(s.Config.ExtraDateLayouts...)
+
+	for _, feed := range s.feeds {
+		if feed.RSS == "" {
+			continue
+		}
+		doc, err := parsers.FromString(feed.RSS)
+		if err != nil {
+			continue
+		}
+
+		doc.Find("item, entry").Each(func(i int, item *goquery.Selection) {
+			for _, tag := range []string{"pubdate", "published", "updated", "dc\\:date"} {
+				value := strings.TrimSpace(item.Find(tag).First().Text())
+				if value == "" {
+					continue
+				}
+				if t, err := parser.Parse(value); err == nil {
+					dates = append(dates, t)
+					return
+				}
+			}
+		})
+	}
+
+	return dates
 }
@@ -0,0 +1,719 @@
+package source
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/internal/urls"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+func newTestSource(t *testing.T, sourceURL string) *DefaultSource {
+	t.Helper()
+	s, err := NewDefaultSource(SourceRequest{URL: sourceURL, Config: *configuration.NewConfiguration()})
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+	return s
+}
+
+func TestEstimatePublishCadence(t *testing.T) {
+	s := newTestSource(t, "https://news.example.com")
+
+	if cadence := s.EstimatePublishCadence(); cadence != 0 {
+		t.Errorf("Expected zero cadence without feeds, got %v", cadence)
+	}
+
+	s.feeds = []newspaper.Feed{
+		{URL: "https://news.example.com/rss", RSS: `<?xml version="1.0"?>
+<rss version="2.0"><channel>
+	<item><title>A</title><link>https://news.example.com/a</link><pubDate>Mon, 06 Jan 2025 08:00:00 GMT</pubDate></item>
+	<item><title>B</title><link>https://news.example.com/b</link><pubDate>Mon, 06 Jan 2025 10:00:00 GMT</pubDate></item>
+	<item><title>C</title><link>https://news.example.com/c</link><pubDate>Mon, 06 Jan 2025 12:30:00 GMT</pubDate></item>
+</channel></rss>`},
+		{URL: "https://news.example.com/atom", RSS: `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<entry><title>D</title><published>2025-01-06T14:00:00Z</published></entry>
+	<entry><title>E</title><published>2025-01-06T20:00:00Z</published></entry>
+</feed>`},
+	}
+
+	// Intervals are 2h, 2h30m, 1h30m and 6h: the median is 2h15m
+	if cadence := s.EstimatePublishCadence(); cadence != 2*time.Hour+15*time.Minute {
+		t.Errorf("Expected a cadence of 2h15m, got %v", cadence)
+	}
+}
+
+func TestSearchCategoriesSectionSubdomains(t *testing.T) {
+	html := `<html><body>
+<a href="/politics">Politics</a>
+<a href="https://sports.example.com/">Sports</a>
+<a href="https://culture.example.com">Culture</a>
+<a href="https://www.example.com/">Home</a>
+<a href="https://cdn.example.com/">Assets</a>
+<a href="https://sports.example.com/2025/01/06/local-team-wins-the-cup.html">Cup final</a>
+<a href="https://sports.other.com/">Other sports</a>
+</body></html>`
+
+	for _, include := range []bool{false, true} {
+		s := newTestSource(t, "https://example.com")
+		s.HTML = html
+		if err := s.Parse(); err != nil {
+			t.Fatalf("Error parsing source: %v", err)
+		}
+
+		params := DefaultBuildParams()
+		params.IncludeSectionSubdomains = include
+		if err := s.SearchCategoriesWithParams(params); err != nil {
+			t.Fatalf("Error searching categories: %v", err)
+		}
+
+		var found []string
+		for _, cat := range s.Categories() {
+			found = append(found, cat.URL)
+		}
+		expected := []string{"https://example.com/politics"}
+		if include {
+			expected = append(expected, "https://sports.example.com", "https://culture.example.com")
+		}
+		if !slices.Equal(found, expected) {
+			t.Errorf("IncludeSectionSubdomains=%v: expected categories %v, got %v", include, expected, found)
+		}
+	}
+}
+
+func TestDownloadCategoriesSkipsNonHTML(t *testing.T) {
+	var mu sync.Mutex
+	flakyRequests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/world":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, `<html><body><a href="/2025/01/06/storm-hits-the-coast.html">Storm</a></body></html>`)
+		case "/api":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"teaser": "<a href=\"/2025/01/06/leaked-json-story.html\">Leaked</a>"}`)
+		case "/sports":
+			mu.Lock()
+			flakyRequests++
+			first := flakyRequests == 1
+			mu.Unlock()
+			if first {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"status": "warming up"}`)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html><body><a href="/2025/01/06/team-wins-the-cup.html">Cup</a></body></html>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// The retries download the category again, whatever the recent download cache
+	for _, tt := range []struct{ retries, cacheSize int }{{0, 0}, {1, 0}, {1, 10}} {
+		retries := tt.retries
+		mu.Lock()
+		flakyRequests = 0
+		mu.Unlock()
+
+		s := newTestSource(t, srv.URL)
+		s.Config.NonHTMLCategoryRetries = retries
+		s.Config.RecentDownloadCacheSize = tt.cacheSize
+		s.Config.RecentDownloadCacheTTLSeconds = 60
+		s.categories = []newspaper.Category{{URL: srv.URL + "/world"}, {URL: srv.URL + "/api"}, {URL: srv.URL + "/sports"}}
+		s.DownloadCategories()
+		s.BuildCategories()
+
+		var categoryURLs []string
+		for _, c := range s.Categories() {
+			categoryURLs = append(categoryURLs, strings.TrimPrefix(c.URL, srv.URL))
+		}
+		expected := []string{"/world"}
+		if retries > 0 {
+			expected = append(expected, "/sports")
+		}
+		if !slices.Equal(categoryURLs, expected) {
+			t.Errorf("NonHTMLCategoryRetries=%d: expected categories %v, got %v", retries, expected, categoryURLs)
+		}
+
+		var articleURLs []string
+		for _, a := range s.categoriesToArticles(DefaultBuildParams(), nil) {
+			articleURLs = append(articleURLs, strings.TrimPrefix(a.URL, srv.URL))
+		}
+		if !slices.Contains(articleURLs, "/2025/01/06/storm-hits-the-coast.html") {
+			t.Errorf("NonHTMLCategoryRetries=%d: expected the links of the HTML category, got %v", retries, articleURLs)
+		}
+		if slices.Contains(articleURLs, "/2025/01/06/leaked-json-story.html") {
+			t.Errorf("NonHTMLCategoryRetries=%d: links of the JSON category should be skipped, got %v", retries, articleURLs)
+		}
+	}
+}
+
+func TestIsSameSite(t *testing.T) {
+	tests := []struct {
+		source, article string
+		allowSubDomain  bool
+		mergeMobile     bool
+		expected        bool
+	}{
+		{"https://www.example.com", "https://example.com/a.html", false, false, true},
+		{"https://example.com", "https://www.example.com/a.html", false, false, true},
+		{"https://example.com", "https://www.example.com/a.html", true, false, true},
+		{"https://m.example.com", "https://amp.m.example.com/a.html", false, false, false},
+		{"https://m.example.com", "https://amp.m.example.com/a.html", false, true, true},
+		{"https://amp.m.example.com", "https://m.example.com/a.html", false, true, true},
+		{"https://www.example.com", "https://mobile.example.com/a.html", false, true, true},
+		{"https://www.example.com", "https://mobile.example.com/a.html", false, false, false},
+		{"https://news.example.com", "https://blog.example.com/a.html", false, false, false},
+		{"https://news.example.com", "https://blog.example.com/a.html", false, true, false},
+		{"https://news.example.com", "https://blog.example.com/a.html", true, false, true},
+		{"https://m.news.example.com", "https://news.example.com/a.html", false, true, true},
+		{"https://example.com", "https://example.org/a.html", true, false, false},
+		{"https://example.com", "https://other.com/a.html", true, true, false},
+	}
+
+	for _, tt := range tests {
+		source, err := urls.Parse(tt.source)
+		if err != nil {
+			t.Fatalf("Error parsing %s: %v", tt.source, err)
+		}
+		article, err := urls.Parse(tt.article)
+		if err != nil {
+			t.Fatalf("Error parsing %s: %v", tt.article, err)
+		}
+		params := BuildParams{AllowSubDomain: tt.allowSubDomain, MergeMobileSubdomains: tt.mergeMobile}
+		if got := isSameSite(source, article, params); got != tt.expected {
+			t.Errorf("isSameSite(%s, %s, AllowSubDomain=%v, MergeMobileSubdomains=%v) = %v, expected %v",
+				tt.source, tt.article, tt.allowSubDomain, tt.mergeMobile, got, tt.expected)
+		}
+	}
+}
+
+func TestGetArticlesTrailingSlash(t *testing.T) {
+	s := newTestSource(t, "https://news.example.com")
+	s.categories = []newspaper.Category{{
+		URL:  "https://news.example.com/world",
+		HTML: `<html><body><a href="/2025/01/06/storm-hits-the-coast/">Storm</a><a href="/2025/01/06/storm-hits-the-coast">Storm</a></body></html>`,
+	}}
+	s.feeds = []newspaper.Feed{{
+		URL: "https://news.example.com/rss",
+		RSS: `<rss><channel><item><guid>https://news.example.com/2025/01/06/storm-hits-the-coast/</guid></item></channel></rss>`,
+	}}
+
+	articles := s.GetArticles()
+	if len(articles) != 1 || articles[0].URL != "https://news.example.com/2025/01/06/storm-hits-the-coast" {
+		var got []string
+		for _, a := range articles {
+			got = append(got, a.URL)
+		}
+		t.Errorf("Expected a single article without trailing slash, got %v", got)
+	}
+}
+
+func TestArticleURLFilter(t *testing.T) {
+	s := newTestSource(t, "https://news.example.com")
+	s.categories = []newspaper.Category{{
+		URL: "https://news.example.com/world",
+		HTML: `<html><body>
+<a href="/2025/01/06/storm-hits-the-coast.html">Storm</a>
+<a href="/2025/01/06/council-approves-budget.html">Budget</a>
+<a href="/live/election-results">Election results</a>
+<a href="/2025/01/06/storm-hits-the-coast.html#comments">Comments</a>
+</body></html>`,
+	}}
+	s.feeds = []newspaper.Feed{{
+		URL: "https://news.example.com/rss",
+		RSS: `<rss><channel><item><guid>https://news.example.com/2025/01/06/council-approves-budget.html</guid></item></channel></rss>`,
+	}}
+
+	if newspaper.IsLikelyArticleURL("https://news.example.com/live/election-results") {
+		t.Fatal("Expected the live page to be rejected by the default heuristics")
+	}
+
+	calls := map[string]int{}
+	var metas []DiscoveryMeta
+	params := DefaultBuildParams()
+	params.ArticleURLFilter = func(u *urls.URL, meta DiscoveryMeta) FilterDecision {
+		calls[u.String()]++
+		metas = append(metas, meta)
+		switch u.Path {
+		case "/live/election-results":
+			return Accept
+		case "/2025/01/06/storm-hits-the-coast.html":
+			return Reject
+		}
+		return DefaultHeuristics
+	}
+
+	var got []string
+	for _, a := range s.GetArticlesWithParams(params) {
+		got = append(got, a.URL)
+	}
+	expected := []string{
+		"https://news.example.com/2025/01/06/council-approves-budget.html",
+		"https://news.example.com/live/election-results",
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected articles %v, got %v", expected, got)
+	}
+
+	for u, n := range calls {
+		if n != 1 {
+			t.Errorf("Expected the filter to be called once for %s, got %d", u, n)
+		}
+	}
+	if len(calls) != 3 {
+		t.Errorf("Expected the filter to be called for 3 candidates, got %v", calls)
+	}
+	if !slices.Contains(metas, DiscoveryMeta{Origin: DiscoveredInCategory, PageURL: "https://news.example.com/world", AnchorText: "Election results"}) {
+		t.Errorf("Expected the anchor text of the category link, got %+v", metas)
+	}
+}
+
+func TestLikelyRequiresJS(t *testing.T) {
+	shell := `<html><head><title>Local News</title><script src="/static/js/main.4f2a9c.js" defer></script></head>` +
+		`<body><noscript>You need to enable JavaScript to run this app.</noscript><div id="root"></div></body></html>`
+
+	s := newTestSource(t, "https://news.example.com")
+	params := DefaultBuildParams()
+	params.InputHTML = shell
+	params.PrefetchedPages = map[string]string{}
+	if err := s.BuildWithParams(params); err != nil {
+		t.Fatalf("Error building source: %v", err)
+	}
+	if articles := s.GetArticles(); len(articles) != 0 {
+		t.Fatalf("Expected no article, got %d", len(articles))
+	}
+	if !s.LikelyRequiresJS() {
+		t.Error("Expected a page with only a JS bundle to require JavaScript")
+	}
+	if len(s.Diagnostics) != 1 || !strings.Contains(s.Diagnostics[0], "https://news.example.com") {
+		t.Errorf("Expected a diagnostic for the homepage, got %q", s.Diagnostics)
+	}
+
+	s = newTestSource(t, "https://news.example.com")
+	params.InputHTML = `<html><head><script src="/static/js/main.js"></script></head><body><div id="root">` +
+		`<a href="/2025/01/06/storm-hits-the-coast.html">Storm hits the coast</a></div></body></html>`
+	params.OnlyHomepage = true
+	if err := s.BuildWithParams(params); err != nil {
+		t.Fatalf("Error building source: %v", err)
+	}
+	if articles := s.GetArticles(); len(articles) != 1 || s.LikelyRequiresJS() {
+		t.Errorf("Expected a server-rendered page not to require JavaScript, got %d articles", len(articles))
+	}
+}
+
+func TestStripAllQueryParams(t *testing.T) {
+	const site = "https://news.example.com"
+	pages := func(session int) map[string]string {
+		return map[string]string{
+			site: `<html><body><a href="/world">World</a></body></html>`,
+			site + "/world": fmt.Sprintf(`<html><body>
+<a href="/2025/01/06/storm-hits-the-coast.html?sess=%[1]d&ref=homepage">Storm</a>
+<a href="/2025/01/06/council-approves-budget.html?sess=%[1]d">Budget</a>
+<a href="/item?id=42&sess=%[1]d">Discussion</a>
+<a href="/world/latest?sess=%[1]d">Latest</a>
+</body></html>`, session),
+		}
+	}
+	build := func(session int, strip bool) []string {
+		s := newTestSource(t, site)
+		params := DefaultBuildParams()
+		params.PrefetchedPages = pages(session)
+		params.StripAllQueryParams = strip
+		params.KeepQueryParams = []string{"id"}
+		if err := s.BuildWithParams(params); err != nil {
+			t.Fatalf("Error building source: %v", err)
+		}
+		var got []string
+		for _, a := range s.GetArticlesWithParams(params) {
+			got = append(got, strings.TrimPrefix(a.URL, site))
+		}
+		slices.Sort(got)
+		return got
+	}
+
+	expected := []string{
+		"/2025/01/06/council-approves-budget.html",
+		"/2025/01/06/storm-hits-the-coast.html",
+		"/item?id=42",
+	}
+	first, second := build(1, true), build(2, true)
+	if !slices.Equal(first, expected) || !slices.Equal(second, expected) {
+		t.Errorf("Expected one URL per story across builds, got %v and %v", first, second)
+	}
+
+	// Without stripping, every build yields new URLs and the query makes the section look like an article
+	unstripped := build(1, false)
+	if slices.Equal(unstripped, build(2, false)) || !slices.Contains(unstripped, "/world/latest?sess=1") {
+		t.Errorf("Expected session parameters to be kept by default, got %v", unstripped)
+	}
+}
+
+func TestCheckFeedRedirects(t *testing.T) {
+	const rss = `<?xml version="1.0"?><rss version="2.0"><channel><title>Local News</title><ttl>120</ttl></channel></rss>`
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html><body><form>Sign in</form></body></html>`)
+		case "/feed.xml":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			fmt.Fprint(w, rss)
+		}
+	}))
+	defer other.Close()
+	// localhost is another host than the 127.0.0.1 of the source server
+	otherURL := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed":
+			http.Redirect(w, r, "/feed/", http.StatusMovedPermanently)
+		case "/feed/":
+			http.Redirect(w, r, "/feed", http.StatusMovedPermanently)
+		case "/rss":
+			http.Redirect(w, r, "/rss.xml", http.StatusFound)
+		case "/rss.xml":
+			w.Header().Set("Content-Type", "text/xml")
+			fmt.Fprint(w, rss)
+		case "/members":
+			http.Redirect(w, r, otherURL+"/login", http.StatusFound)
+		case "/syndication":
+			http.Redirect(w, r, otherURL+"/feed.xml", http.StatusFound)
+		case "/index.html":
+			fmt.Fprint(w, `<html><body>Home</body></html>`)
+		default:
+			if n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop")); err == nil && n > 0 {
+				http.Redirect(w, r, fmt.Sprintf("/hop%d", n-1), http.StatusFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/rss+xml")
+			fmt.Fprint(w, rss)
+		}
+	}))
+	defer srv.Close()
+
+	s := newTestSource(t, srv.URL)
+
+	feed, err := s.checkFeed(context.Background(), srv.URL+"/rss")
+	if err != nil {
+		t.Fatalf("Expected the redirected feed to be accepted, got %v", err)
+	}
+	if expected := []string{srv.URL + "/rss", srv.URL + "/rss.xml"}; !slices.Equal(feed.RedirectChain, expected) || feed.RSS != rss {
+		t.Errorf("Expected the redirect chain %v, got %v", expected, feed.RedirectChain)
+	}
+	if feed.PollingHints == nil || feed.SuggestedPollInterval(time.Hour) != 2*time.Hour {
+		t.Errorf("Expected the polling hints of the feed, got %+v", feed.PollingHints)
+	}
+	if feed, err := s.checkFeed(context.Background(), srv.URL+"/hop0"); err != nil || feed.RedirectChain != nil {
+		t.Errorf("Expected a feed without redirect chain, got %+v, %v", feed, err)
+	}
+
+	for path, reason := range map[string]string{
+		"/feed":        "redirect loop",
+		"/hop4":        "stopped after 3 redirects",
+		"/members":     "redirected to another domain: " + srv.URL + "/members -> " + otherURL + "/login",
+		"/syndication": "redirected to another domain",
+		"/index.html":  "is not a feed",
+	} {
+		if _, err := s.checkFeed(context.Background(), srv.URL+path); err == nil || !strings.Contains(err.Error(), reason) {
+			t.Errorf("%s: expected an error containing %q, got %v", path, reason, err)
+		}
+	}
+	if _, err := s.checkFeed(context.Background(), srv.URL+"/hop3"); err != nil {
+		t.Errorf("Expected 3 redirects to be followed, got %v", err)
+	}
+
+	s.Config.FeedAllowCrossDomainRedirects = true
+	if feed, err := s.checkFeed(context.Background(), srv.URL+"/syndication"); err != nil || len(feed.RedirectChain) != 2 {
+		t.Errorf("Expected the cross-domain feed to be accepted, got %+v, %v", feed, err)
+	}
+	if _, err := s.checkFeed(context.Background(), srv.URL+"/members"); err == nil || !strings.Contains(err.Error(), "is not a feed") {
+		t.Errorf("Expected the cross-domain login page to be rejected, got %v", err)
+	}
+}
+
+func TestCheckFeedContentEncoding(t *testing.T) {
+	const rss = `<?xml version="1.0"?><rss version="2.0"><channel><title>Local News</title>` +
+		`<item><link>https://news.example.com/2025/03/14/storm-hits-the-coast.html</link></item>` +
+		`<item><link>https://news.example.com/2025/03/14/harbour-reopens.html</link></item>` +
+		`</channel></rss>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body bytes.Buffer
+		var writer io.WriteCloser
+		switch r.URL.Path {
+		case "/gzip.xml":
+			w.Header().Set("Content-Encoding", "gzip")
+			writer = gzip.NewWriter(&body)
+		case "/x-gzip.xml":
+			// Not decoded by the HTTP transport
+			w.Header().Set("Content-Encoding", "x-gzip")
+			writer = gzip.NewWriter(&body)
+		case "/deflate.xml":
+			w.Header().Set("Content-Encoding", "deflate")
+			writer = zlib.NewWriter(&body)
+		case "/raw-deflate.xml":
+			w.Header().Set("Content-Encoding", "deflate")
+			writer, _ = flate.NewWriter(&body, flate.DefaultCompression)
+		}
+		_, _ = io.WriteString(writer, rss)
+		_ = writer.Close()
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write(body.Bytes())
+	}))
+	defer srv.Close()
+
+	for _, path := range []string{"/gzip.xml", "/x-gzip.xml", "/deflate.xml", "/raw-deflate.xml"} {
+		s := newTestSource(t, "https://news.example.com")
+		feed, err := s.checkFeed(context.Background(), srv.URL+path)
+		if err != nil {
+			t.Fatalf("%s: expected the encoded feed to be accepted, got %v", path, err)
+		}
+		if feed.RSS != rss {
+			t.Errorf("%s: expected the decoded feed, got %q", path, feed.RSS)
+		}
+		s.feeds = []newspaper.Feed{feed}
+		if articles := s.feedsToArticles(BuildParams{}, nil); len(articles) != 2 {
+			t.Errorf("%s: expected the 2 items of the feed, got %d articles", path, len(articles))
+		}
+	}
+}
+
+func TestSourceRequestHeaders(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]http.Header{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path] = r.Header.Clone()
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/world":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, `<html><body><a href="/2025/01/06/storm-hits-the-coast.html">Storm</a></body></html>`)
+		case "/feed.xml":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>World</title></channel></rss>`)
+		}
+	}))
+	defer srv.Close()
+
+	s := newTestSource(t, srv.URL)
+	s.Config.RequestsParams.Headers = map[string]string{"Accept-Language": "de-DE", "Cookie": "consent=1"}
+	s.Config.RequestsParams.UserAgent = "reader/1.0"
+	s.categories = []newspaper.Category{{URL: srv.URL + "/world"}}
+	s.DownloadCategories()
+	if _, err := s.checkFeed(context.Background(), srv.URL+"/feed.xml"); err != nil {
+		t.Fatalf("Error checking feed: %v", err)
+	}
+
+	for _, path := range []string{"/world", "/feed.xml"} {
+		header, ok := seen[path]
+		if !ok {
+			t.Errorf("%s: expected a request", path)
+			continue
+		}
+		if header.Get("Accept-Language") != "de-DE" || header.Get("Cookie") != "consent=1" || header.Get("User-Agent") != "reader/1.0" {
+			t.Errorf("%s: expected the configured headers, got %v", path, header)
+		}
+	}
+}
+
+func TestCheckFeedRetries(t *testing.T) {
+	var mu sync.Mutex
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits++
+		failing := hits <= 2
+		mu.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>World</title></channel></rss>`)
+	}))
+	defer srv.Close()
+
+	s := newTestSource(t, srv.URL)
+	s.Config.RequestsParams.MaxRetries = 2
+	s.Config.RequestsParams.RetryBackoff = time.Millisecond
+	if _, err := s.checkFeed(context.Background(), srv.URL+"/feed.xml"); err != nil {
+		t.Errorf("Expected the feed to be accepted after the retries, got %v", err)
+	}
+	if hits != 3 {
+		t.Errorf("Expected 3 requests, server saw %d", hits)
+	}
+}
+
+// pageClient serves canned pages without network access and records the
+// requested URLs
+type pageClient struct {
+	mu        sync.Mutex
+	pages     map[string]string
+	requested []string
+}
+
+func (c *pageClient) Do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	c.requested = append(c.requested, req.URL.String())
+	c.mu.Unlock()
+	page, ok := c.pages[req.URL.String()]
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: req, Body: io.NopCloser(strings.NewReader(page))}
+	if !ok {
+		resp.StatusCode = http.StatusNotFound
+	}
+	if strings.HasSuffix(req.URL.Path, ".xml") {
+		resp.Header.Set("Content-Type", "application/rss+xml")
+	} else {
+		resp.Header.Set("Content-Type", "text/html; charset=utf-8")
+	}
+	return resp, nil
+}
+
+func TestBuildWithHTTPClient(t *testing.T) {
+	client := &pageClient{pages: map[string]string{
+		"https://offline.example.com/": `<html><head><title>Offline News</title>
+<link rel="alternate" type="application/rss+xml" href="/feed.xml"></head><body>
+<a href="/world">World</a>
+</body></html>`,
+		"https://offline.example.com/world": `<html><body>
+<a href="/2025/03/14/storm-hits-the-coast.html">Storm hits the coast</a>
+</body></html>`,
+		"https://offline.example.com/feed.xml": `<?xml version="1.0"?><rss version="2.0"><channel><title>Offline News</title>
+<item><link>https://offline.example.com/2025/03/14/harbour-reopens.html</link></item>
+</channel></rss>`,
+	}}
+
+	config := configuration.NewConfiguration()
+	config.HTTPClient = client
+	s, err := NewDefaultSource(SourceRequest{URL: "https://offline.example.com/", Config: *config})
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+	if err := s.Build(); err != nil {
+		t.Fatalf("Error building source: %v", err)
+	}
+
+	var articleURLs []string
+	for _, a := range s.GetArticles() {
+		articleURLs = append(articleURLs, a.URL)
+	}
+	for _, expected := range []string{
+		"https://offline.example.com/2025/03/14/storm-hits-the-coast.html",
+		"https://offline.example.com/2025/03/14/harbour-reopens.html",
+	} {
+		if !slices.Contains(articleURLs, expected) {
+			t.Errorf("Expected %s among the articles, got %v", expected, articleURLs)
+		}
+	}
+	for _, expected := range []string{"https://offline.example.com/", "https://offline.example.com/world", "https://offline.example.com/feed.xml"} {
+		if !slices.Contains(client.requested, expected) {
+			t.Errorf("Expected %s to be requested through the client, got %v", expected, client.requested)
+		}
+	}
+}
+
+func TestBuildWithContextCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, `<html><head><title>Local News</title></head><body>
+<a href="/world">World</a><a href="/sports">Sports</a><a href="/business">Business</a>
+</body></html>`)
+			return
+		}
+		// Categories and feeds hang until the request is canceled
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	for _, async := range []bool{false, true} {
+		s := newTestSource(t, srv.URL)
+		build := s.BuildWithContext
+		if async {
+			build = (&AsyncSource{DefaultSource: s}).BuildWithContext
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+
+		start := time.Now()
+		err := build(ctx)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("async=%v: expected the deadline error, got %v", async, err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("async=%v: expected the build to stop promptly, took %v", async, elapsed)
+		}
+		if !s.IsDownloaded || s.Doc == nil {
+			t.Errorf("async=%v: expected the homepage gathered before the deadline to be kept", async)
+		}
+
+		// The context only applies to the build it was passed to
+		if err := s.Download(); err != nil {
+			t.Errorf("async=%v: expected a download after the build to succeed, got %v", async, err)
+		}
+	}
+}
+
+func TestParseHomepageMetadata(t *testing.T) {
+	homepage := `<html lang="en-GB"><head><title>Coast News</title>
+<meta property="og:site_name" content="Coast News">
+<meta name="keywords" content="news, weather , coast,">
+<meta name="description" content="Local news from the coast">
+<link rel="canonical" href="/">
+<link rel="alternate" type="application/rss+xml" title="Top stories" href="/rss.xml">
+<link rel="alternate" type="application/atom+xml" href="https://feeds.example.com/atom">
+<link rel="alternate" hreflang="fr" href="/fr/">
+</head><body><p>Coast News</p></body></html>`
+
+	s := newTestSource(t, "https://news.example.com/?utm_source=test")
+	s.HTML = homepage
+	if err := s.Parse(); err != nil {
+		t.Fatalf("Error parsing source: %v", err)
+	}
+
+	if s.SiteName != "Coast News" {
+		t.Errorf("Expected site name %q, got %q", "Coast News", s.SiteName)
+	}
+	if expected := []string{"news", "weather", "coast"}; !slices.Equal(s.Keywords, expected) {
+		t.Errorf("Expected keywords %q, got %q", expected, s.Keywords)
+	}
+	if s.Language != "en" {
+		t.Errorf("Expected language %q, got %q", "en", s.Language)
+	}
+	if s.CanonicalURL != "https://news.example.com/" {
+		t.Errorf("Expected canonical URL %q, got %q", "https://news.example.com/", s.CanonicalURL)
+	}
+	if expected := []string{"https://news.example.com/rss.xml", "https://feeds.example.com/atom"}; !slices.Equal(s.AlternateFeeds, expected) {
+		t.Errorf("Expected alternate feeds %q, got %q", expected, s.AlternateFeeds)
+	}
+
+	state := s.ExportState()
+	if state.SiteName != s.SiteName || state.Language != s.Language || state.CanonicalURL != s.CanonicalURL ||
+		!slices.Equal(state.Keywords, s.Keywords) || !slices.Equal(state.AlternateFeeds, s.AlternateFeeds) {
+		t.Errorf("Expected the homepage metadata in the state, got %+v", state)
+	}
+}
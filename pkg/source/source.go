@@ -1,7 +1,9 @@
 package source
 
 import (
+	"github.com/tguidoux/newspaper4k-go/internal/urls"
 	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
 )
 
 // Source interface defines the methods for a news source
@@ -13,8 +15,15 @@ type Source interface {
 	GetFeeds()
 	DownloadCategories()
 	BuildCategories()
-	GetArticles()
+	GetArticles() []newspaper.Article
 	Size() int
+
+	Categories() []newspaper.Category
+	Feeds() []newspaper.Feed
+	Articles() []newspaper.Article
+	Description() string
+	ParsedURL() *urls.URL
+	LikelyRequiresJS() bool
 }
 
 type SourceRequest struct {
@@ -22,6 +31,12 @@ type SourceRequest struct {
 	Config configuration.Configuration
 }
 
+// BuildParams tunes how a source is built and which articles it returns.
+//
+// OnlySameDomain keeps the articles sharing the registrable domain of the
+// source (example.com for news.example.com). With it, AllowSubDomain keeps
+// the articles of every subdomain; when false, only the articles of the
+// source's own subdomain are kept, "www" being the same as no subdomain.
 type BuildParams struct {
 	InputHTML       string
 	OnlyHomepage    bool
@@ -30,8 +45,63 @@ type BuildParams struct {
 	LimitCategories int
 	LimitArticles   int
 	Shuffle         bool
+
+	// IncludeSectionSubdomains treats subdomains of the source such as sports.example.com as categories
+	IncludeSectionSubdomains bool
+	// PrefetchedPages maps URLs to the HTML or feed served for them instead of
+	// downloading them. When set, the network is not used at all and the URLs
+	// missing from it are treated as not found, which allows building a source
+	// entirely from fixtures.
+	PrefetchedPages map[string]string
+	// MergeMobileSubdomains makes AllowSubDomain=false also ignore the leading
+	// amp., m. and mobile. labels, so that m.example.com matches example.com
+	MergeMobileSubdomains bool
+	// ArticleURLFilter, when set, is called once per candidate article URL
+	// after canonicalization and before the built-in article URL heuristics.
+	// Accept keeps the URL and Reject drops it whatever the heuristics say,
+	// DefaultHeuristics leaves the decision to them. OnlySameDomain and
+	// LimitArticles still apply to the accepted URLs.
+	ArticleURLFilter func(u *urls.URL, meta DiscoveryMeta) FilterDecision
+	// StripAllQueryParams removes the query string of the discovered article
+	// URLs, for sites adding session or referrer parameters to their links,
+	// except the parameters listed in KeepQueryParams (such as "v" for
+	// YouTube or "id" for Hacker News). The query is stripped before the
+	// article URL heuristics, so that a query no longer makes a URL look like
+	// an article unless it has kept parameters, and before deduplication.
+	StripAllQueryParams bool
+	KeepQueryParams     []string
+}
+
+// DiscoveryOrigin is the kind of page a candidate article URL was found on
+type DiscoveryOrigin string
+
+const (
+	DiscoveredInFeed     DiscoveryOrigin = "feed"
+	DiscoveredInCategory DiscoveryOrigin = "category"
+	DiscoveredOnHomepage DiscoveryOrigin = "homepage"
+)
+
+// DiscoveryMeta describes where a candidate article URL was found
+type DiscoveryMeta struct {
+	Origin DiscoveryOrigin
+	// PageURL is the URL of the feed, category or homepage linking to the candidate
+	PageURL string
+	// AnchorText is the text of the link, empty for feed items
+	AnchorText string
 }
 
+// FilterDecision is the verdict of a BuildParams.ArticleURLFilter
+type FilterDecision int
+
+const (
+	// DefaultHeuristics lets the built-in article URL heuristics decide
+	DefaultHeuristics FilterDecision = iota
+	// Accept keeps the URL as an article
+	Accept
+	// Reject drops the URL
+	Reject
+)
+
 func DefaultBuildParams() BuildParams {
 	return BuildParams{
 		InputHTML:       "",
@@ -0,0 +1,134 @@
+package source
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspapertest"
+)
+
+var (
+	_ Source = (*DefaultSource)(nil)
+	_ Source = (*AsyncSource)(nil)
+)
+
+func TestSourceInterface(t *testing.T) {
+	site := newspapertest.NewFakeSite(t, newspapertest.SiteSpec{
+		Description: "Local news",
+		Categories: []newspapertest.CategorySpec{
+			{Name: "World", Articles: []newspapertest.ArticleSpec{{Title: "Storm hits the coast"}, {Title: "Council approves budget"}}},
+			{Name: "Sports", Articles: []newspapertest.ArticleSpec{{Title: "Local team wins the cup"}}},
+		},
+	})
+
+	constructors := map[string]func(SourceRequest) (Source, error){
+		"default": func(req SourceRequest) (Source, error) { return NewDefaultSource(req) },
+		"async":   func(req SourceRequest) (Source, error) { return NewAsyncSource(req) },
+	}
+
+	for name, newSource := range constructors {
+		t.Run(name, func(t *testing.T) {
+			src, err := newSource(SourceRequest{URL: site.URL, Config: *configuration.NewConfiguration()})
+			if err != nil {
+				t.Fatalf("Error creating source: %v", err)
+			}
+			if src.ParsedURL() == nil || src.ParsedURL().Scheme != "http" {
+				t.Errorf("Expected a parsed source URL, got %+v", src.ParsedURL())
+			}
+
+			if err := src.Build(); err != nil {
+				t.Fatalf("Error building source: %v", err)
+			}
+
+			if src.Description() != "Local news" {
+				t.Errorf("Expected description %q, got %q", "Local news", src.Description())
+			}
+			if len(src.Categories()) != 2 {
+				t.Errorf("Expected 2 categories, got %d", len(src.Categories()))
+			}
+			if feeds := src.Feeds(); len(feeds) != 1 || feeds[0].URL != site.FeedURL {
+				t.Errorf("Expected the feed linked from a category, got %v", feeds)
+			}
+
+			articles := src.GetArticles()
+			if len(articles) != 3 {
+				t.Errorf("Expected 3 articles, got %d", len(articles))
+			}
+			if len(src.Articles()) != len(articles) || src.Size() != len(articles) {
+				t.Errorf("Expected Articles() and Size() to match GetArticles, got %d and %d", len(src.Articles()), src.Size())
+			}
+		})
+	}
+}
+
+// countingTransport counts the requests that reach the network
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (ct *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ct.requests.Add(1)
+	return nil, fmt.Errorf("unexpected request to %s", r.URL)
+}
+
+func TestBuildFromPrefetchedPages(t *testing.T) {
+	transport := &countingTransport{}
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = transport
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	const site = "https://news.example.com"
+	pages := map[string]string{
+		site: `<html><head><meta name="description" content="Local news"></head><body>` +
+			`<a href="/world">World</a><a href="/sports">Sports</a></body></html>`,
+		site + "/world": `<html><body><a href="/2025/01/06/storm-hits-the-coast.html">Storm</a>` +
+			`<a href="/2025/01/06/council-approves-budget.html">Budget</a></body></html>`,
+		site + "/sports": `<html><body><a href="/2025/01/06/local-team-wins-the-cup.html">Cup</a></body></html>`,
+		site + "/rss": `<?xml version="1.0"?><rss version="2.0"><channel>` +
+			`<item><title>Bridge</title><guid>` + site + `/2025/01/05/new-bridge-opens-to-traffic.html</guid></item>` +
+			`</channel></rss>`,
+	}
+
+	src, err := NewDefaultSource(SourceRequest{URL: site, Config: *configuration.NewConfiguration()})
+	if err != nil {
+		t.Fatalf("Error creating source: %v", err)
+	}
+	params := DefaultBuildParams()
+	params.PrefetchedPages = pages
+	if err := src.BuildWithParams(params); err != nil {
+		t.Fatalf("Error building source: %v", err)
+	}
+
+	if src.Description() != "Local news" {
+		t.Errorf("Expected description %q, got %q", "Local news", src.Description())
+	}
+	if len(src.Categories()) != 2 {
+		t.Errorf("Expected 2 categories, got %v", src.Categories())
+	}
+	if feeds := src.Feeds(); len(feeds) != 1 || feeds[0].URL != site+"/rss" {
+		t.Errorf("Expected the prefetched feed, got %v", feeds)
+	}
+
+	var articleURLs []string
+	for _, a := range src.GetArticles() {
+		articleURLs = append(articleURLs, strings.TrimPrefix(a.URL, site))
+	}
+	slices.Sort(articleURLs)
+	expected := []string{
+		"/2025/01/05/new-bridge-opens-to-traffic.html",
+		"/2025/01/06/council-approves-budget.html",
+		"/2025/01/06/local-team-wins-the-cup.html",
+		"/2025/01/06/storm-hits-the-coast.html",
+	}
+	if !slices.Equal(articleURLs, expected) {
+		t.Errorf("Expected articles %v, got %v", expected, articleURLs)
+	}
+	if n := transport.requests.Load(); n != 0 {
+		t.Errorf("Expected no network request, got %d", n)
+	}
+}
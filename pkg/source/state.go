@@ -0,0 +1,158 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/tguidoux/newspaper4k-go/internal/urls"
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+)
+
+// SourceState is the discovered structure of a source, suitable for JSON
+// persistence so that a crawler can resume without rediscovering it
+type SourceState struct {
+	URL            string   `json:"url"`
+	Description    string   `json:"description,omitempty"`
+	SiteName       string   `json:"site_name,omitempty"`
+	Keywords       []string `json:"keywords,omitempty"`
+	Language       string   `json:"language,omitempty"`
+	CanonicalURL   string   `json:"canonical_url,omitempty"`
+	AlternateFeeds []string `json:"alternate_feeds,omitempty"`
+	Categories     []string `json:"categories"`
+	Feeds          []string `json:"feeds"`
+	SeenURLs       []string `json:"seen_urls"`
+}
+
+// ExportState returns the categories, feeds, homepage metadata and seen
+// article URLs of the source. Downloaded pages are not part of the state.
+func (s *DefaultSource) ExportState() SourceState {
+	state := SourceState{
+		URL:            s.URL,
+		Description:    s.description,
+		SiteName:       s.SiteName,
+		Keywords:       s.Keywords,
+		Language:       s.Language,
+		CanonicalURL:   s.CanonicalURL,
+		AlternateFeeds: s.AlternateFeeds,
+		Categories:     make([]string, 0, len(s.categories)),
+		Feeds:          make([]string, 0, len(s.feeds)),
+		SeenURLs:       make([]string, 0, len(s.seen)),
+	}
+	for _, cat := range s.categories {
+		state.Categories = append(state.Categories, cat.URL)
+	}
+	for _, feed := range s.feeds {
+		state.Feeds = append(state.Feeds, feed.URL)
+	}
+	for u := range s.seen {
+		state.SeenURLs = append(state.SeenURLs, u)
+	}
+	sort.Strings(state.SeenURLs)
+	return state
+}
+
+// NewSourceFromState creates a DefaultSource from a state returned by
+// ExportState. Call Refresh to download the restored categories and feeds.
+func NewSourceFromState(state SourceState, config configuration.Configuration) (*DefaultSource, error) {
+	if state.URL == "" {
+		return nil, fmt.Errorf("state has no source URL")
+	}
+
+	s, err := NewDefaultSource(SourceRequest{URL: state.URL, Config: config})
+	if err != nil {
+		return nil, err
+	}
+
+	s.description = state.Description
+	s.SiteName = state.SiteName
+	s.Keywords = state.Keywords
+	s.Language = state.Language
+	s.CanonicalURL = state.CanonicalURL
+	s.AlternateFeeds = state.AlternateFeeds
+	for _, u := range state.Categories {
+		s.categories = append(s.categories, newspaper.Category{URL: u})
+	}
+	for _, u := range state.Feeds {
+		s.feeds = append(s.feeds, newspaper.Feed{URL: urls.PrepareURL(u, u)})
+	}
+	for _, u := range state.SeenURLs {
+		s.seen[u] = true
+	}
+	return s, nil
+}
+
+// Refresh downloads the known categories and feeds again, without
+// rediscovering them, and returns the articles that were not seen before.
+// The returned articles are marked as seen and replace Articles. The article
+// URLs are filtered and normalized with the BuildParams of the last build, or
+// of the last RefreshWithParams, DefaultBuildParams() for a source never built.
+func (s *DefaultSource) Refresh() []newspaper.Article {
+	params := DefaultBuildParams()
+	if s.buildParams != nil {
+		params = *s.buildParams
+	}
+	return s.RefreshWithParams(params)
+}
+
+// RefreshWithParams is like Refresh with the given params, kept for the
+// following calls to Refresh, e.g. for a source restored by NewSourceFromState
+func (s *DefaultSource) RefreshWithParams(params BuildParams) []newspaper.Article {
+	fresh, _ := s.refresh(context.Background(), params)
+	return fresh
+}
+
+// RefreshWithContext is like Refresh with the downloads canceled by ctx. When
+// ctx is done, it returns ctx.Err() and no articles, leaving the seen URLs
+// unchanged.
+func (s *DefaultSource) RefreshWithContext(ctx context.Context) ([]newspaper.Article, error) {
+	params := DefaultBuildParams()
+	if s.buildParams != nil {
+		params = *s.buildParams
+	}
+	return s.refresh(ctx, params)
+}
+
+// refresh downloads the categories and feeds again, canceled with ctx, and
+// returns the articles not seen before
+func (s *DefaultSource) refresh(ctx context.Context, params BuildParams) ([]newspaper.Article, error) {
+	s.buildParams = &params
+	// Unlike DownloadCategories, a category that fails to download is kept
+	// so that a transient error does not drop it from the state
+	for i := range s.categories {
+		_ = s.downloadCategory(ctx, &s.categories[i])
+	}
+	s.BuildCategories()
+
+	for i, feed := range s.feeds {
+		if checked, err := s.checkFeed(ctx, feed.URL); err == nil {
+			s.feeds[i] = checked
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fresh := []newspaper.Article{}
+	for _, article := range s.collectArticles(params) {
+		if !s.seen[article.URL] {
+			fresh = append(fresh, article)
+		}
+	}
+
+	s.articles = fresh
+	s.markSeen(fresh)
+	return fresh, nil
+}
+
+// markSeen records the URLs of articles in the seen set
+func (s *DefaultSource) markSeen(articles []newspaper.Article) {
+	if s.seen == nil {
+		s.seen = map[string]bool{}
+	}
+	for _, article := range articles {
+		s.seen[article.URL] = true
+	}
+}
@@ -0,0 +1,143 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tguidoux/newspaper4k-go/pkg/configuration"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspaper"
+	"github.com/tguidoux/newspaper4k-go/pkg/newspapertest"
+)
+
+func TestSourceStateRoundTrip(t *testing.T) {
+	site := newspapertest.NewFakeSite(t, newspapertest.SiteSpec{
+		Categories: []newspapertest.CategorySpec{
+			{Name: "World", Articles: []newspapertest.ArticleSpec{{Title: "Storm hits the coast"}, {Title: "Council approves budget"}}},
+		},
+		FeedItems: []newspapertest.ArticleSpec{{Title: "New bridge opens to traffic", Date: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)}},
+	})
+
+	s := newTestSource(t, site.URL)
+	s.description = "Local news"
+	s.categories = []newspaper.Category{{URL: site.CategoryURLs[0]}}
+	s.feeds = []newspaper.Feed{{URL: site.FeedURL}}
+
+	if first := s.Refresh(); len(first) != 3 {
+		t.Fatalf("Expected 3 articles on the first refresh, got %d", len(first))
+	}
+
+	data, err := json.Marshal(s.ExportState())
+	if err != nil {
+		t.Fatalf("Error marshaling state: %v", err)
+	}
+	var state SourceState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("Error unmarshaling state: %v", err)
+	}
+	if len(state.SeenURLs) != 3 || len(state.Categories) != 1 || len(state.Feeds) != 1 {
+		t.Fatalf("Unexpected state: %+v", state)
+	}
+
+	restored, err := NewSourceFromState(state, *configuration.NewConfiguration())
+	if err != nil {
+		t.Fatalf("Error restoring source: %v", err)
+	}
+	if restored.description != "Local news" || restored.URL != s.URL {
+		t.Errorf("Expected description and URL to be restored, got %q and %q", restored.description, restored.URL)
+	}
+
+	added := site.AddArticle("World", newspapertest.ArticleSpec{Title: "School reopens after floods", Date: time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC)})
+
+	fresh := restored.Refresh()
+	if len(fresh) != 1 || fresh[0].URL != added.URL {
+		t.Errorf("Expected only the new article after restoring, got %v", fresh)
+	}
+	if again := restored.Refresh(); len(again) != 0 {
+		t.Errorf("Expected no new articles on a second refresh, got %d", len(again))
+	}
+
+	if _, err := NewSourceFromState(SourceState{}, *configuration.NewConfiguration()); err == nil {
+		t.Error("Expected an error for a state without URL")
+	}
+}
+
+func TestRefreshKeepsBuildParams(t *testing.T) {
+	var session atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<html><body><a href="/world">World</a></body></html>`)
+		case "/world":
+			fmt.Fprintf(w, `<html><body>
+<a href="/2025/01/06/storm-hits-the-coast.html?sess=%[1]d">Storm</a>
+<a href="/2025/01/06/council-approves-budget.html?sess=%[1]d">Budget</a>
+</body></html>`, session.Add(1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	s := newTestSource(t, srv.URL)
+	params := DefaultBuildParams()
+	params.StripAllQueryParams = true
+	if err := s.BuildWithParams(params); err != nil {
+		t.Fatalf("Error building source: %v", err)
+	}
+	if first := s.GetArticlesWithParams(params); len(first) != 2 {
+		t.Fatalf("Expected 2 articles, got %d", len(first))
+	}
+
+	for range 2 {
+		if fresh := s.Refresh(); len(fresh) != 0 {
+			t.Errorf("Expected the rotating query parameters to be stripped on refresh, got %d new articles", len(fresh))
+		}
+	}
+	if session.Load() < 3 {
+		t.Errorf("Expected the category to be downloaded on every refresh, got %d downloads", session.Load())
+	}
+
+	if fresh := s.RefreshWithParams(DefaultBuildParams()); len(fresh) != 2 {
+		t.Errorf("Expected new URLs with the query parameters kept, got %d", len(fresh))
+	}
+}
+
+func TestRefreshWithContext(t *testing.T) {
+	var session atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<html><body><a href="/world">World</a></body></html>`)
+		case "/world":
+			fmt.Fprintf(w, `<html><body><a href="/2025/01/06/storm-hits-the-coast-%d.html">Storm</a></body></html>`, session.Add(1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	s := newTestSource(t, srv.URL)
+	if err := s.Build(); err != nil {
+		t.Fatalf("Error building source: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if fresh, err := s.RefreshWithContext(ctx); !errors.Is(err, context.Canceled) || fresh != nil {
+		t.Errorf("Expected a canceled refresh without articles, got %d articles and %v", len(fresh), err)
+	}
+
+	fresh, err := s.RefreshWithContext(context.Background())
+	if err != nil || len(fresh) != 1 {
+		t.Errorf("Expected the new article of the category, got %d articles and %v", len(fresh), err)
+	}
+}